@@ -0,0 +1,276 @@
+/*
+Package accounting builds tax lots of coins from user trades
+and calculates cost basis, realized and unrealized PnL in report currency
+with FIFO, LIFO or average cost method.
+
+Every trade is disposal of one coin and acquisition of another:
+buying BTC-USDT sends USDT and receives BTC, selling is opposite.
+Report currency isn't kept in lots
+*/
+package accounting
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/export"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// Methods
+const (
+	FIFO    = "fifo"
+	LIFO    = "lifo"
+	Average = "average"
+)
+
+// Config - accounting config
+type Config struct {
+	Method   string // FIFO by default
+	Currency string // report currency, i.e. USD or USDT
+
+	/*
+		historical prices of coins in currency. Optional:
+		prices of trades with currency (i.e. BTC-USDT for USDT) are used when coin has no price
+	*/
+	Prices   Prices
+	Location *time.Location // time zone of periods, UTC by default
+}
+
+// entry - trade of exchange
+type entry struct {
+	exchange string
+	trade    schemas.Trade
+	at       time.Time
+}
+
+// Ledger - user trades of exchanges
+type Ledger struct {
+	config  Config
+	entries []entry
+	seen    map[string]bool
+	prices  *PriceTable // prices of trades with currency
+
+	sync.Mutex
+}
+
+// New - Ledger constructor
+func New(config Config) (*Ledger, error) {
+	switch config.Method {
+	case "":
+		config.Method = FIFO
+	case FIFO, LIFO, Average:
+	default:
+		return nil, fmt.Errorf("Unknown accounting method: %s", config.Method)
+	}
+	if config.Currency == "" {
+		return nil, fmt.Errorf("Report currency is required")
+	}
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	return &Ledger{
+		config: config,
+		seen:   make(map[string]bool),
+		prices: NewPriceTable(),
+	}, nil
+}
+
+/*
+Add - adding trades of exchange, trades can be added in any order.
+Trades which were added before are skipped by export.TradeKey: exchange, symbol and ID,
+or order ID, price, amount and time for trades without ID
+*/
+func (l *Ledger) Add(exchange string, trades []schemas.Trade) {
+	l.Lock()
+	defer l.Unlock()
+	for _, t := range trades {
+		key := export.TradeKey(exchange, t)
+		if l.seen[key] {
+			continue
+		}
+		l.seen[key] = true
+		at := schemas.UnixTime(t.Timestamp)
+		l.entries = append(l.entries, entry{exchange: exchange, trade: t, at: at})
+
+		base, quote := coins(t.Symbol)
+		switch l.config.Currency {
+		case quote:
+			l.prices.Add(base, at, t.Price)
+		case base:
+			if t.Price > 0 {
+				l.prices.Add(quote, at, 1/t.Price)
+			}
+		}
+	}
+}
+
+/*
+Import - adding trades of exchange from ImportTrades channel until it's closed.
+Batches are acknowledged (ImportTrades with Acknowledge option) after they're added.
+Error of final status or last batch error is returned
+*/
+func (l *Ledger) Import(exchange string, ch chan schemas.UserTradesChannel) (err error) {
+	for msg := range ch {
+		if msg.Error != nil {
+			err = msg.Error
+			continue
+		}
+		l.Add(exchange, msg.Data)
+		if msg.Ack != nil {
+			msg.Ack(nil)
+		}
+	}
+	return
+}
+
+// sorted - entries by time
+func (l *Ledger) sorted() []entry {
+	l.Lock()
+	defer l.Unlock()
+	entries := append([]entry(nil), l.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].at.Before(entries[j].at)
+	})
+	return entries
+}
+
+// price - price of coin in currency: 1 for currency, configured prices, then prices of trades
+func (l *Ledger) price(coin string, at time.Time) (float64, error) {
+	if coin == l.config.Currency {
+		return 1, nil
+	}
+	if l.config.Prices != nil {
+		if p, err := l.config.Prices.Price(coin, at); err == nil {
+			return p, nil
+		}
+	}
+	return l.prices.Price(coin, at)
+}
+
+/*
+apply - trade as disposal of sent coin and acquisition of received coin.
+Fee is added to cost of bought coin or subtracted from proceeds of sold coin,
+fee paid in other coin is its disposal at market price.
+Trade without price of its coins is skipped, fee without price is disposed with zero value,
+both are added to unpriced trades of period
+*/
+func (l *Ledger) apply(open *lots, e entry, p *PeriodReport) error {
+	t := e.trade
+	base, quote := coins(t.Symbol)
+	if base == "" {
+		return fmt.Errorf("Trade %s: unknown symbol %s", t.ID, t.Symbol)
+	}
+	s := side(t.Type)
+	if s != schemas.TypeBuy && s != schemas.TypeSell {
+		return fmt.Errorf("Trade %s: unknown type %s", t.ID, t.Type)
+	}
+	total := t.Price * t.Amount
+
+	var value float64
+	if price, err := l.price(quote, e.at); err == nil {
+		value = total * price
+	} else if price, err := l.price(base, e.at); err == nil {
+		value = t.Amount * price
+	} else {
+		p.UnpricedTrades = append(p.UnpricedTrades, UnpricedTrade{
+			Exchange: e.exchange,
+			Trade:    t,
+			Time:     e.at,
+			Coin:     quote,
+		})
+		return nil
+	}
+
+	feeCoin := t.FeeCoin
+	if feeCoin == "" {
+		feeCoin = quote
+	}
+	// fee in coin of symbol is valued by trade price
+	var fee float64
+	switch {
+	case t.Fee == 0:
+	case feeCoin == quote && total > 0:
+		fee = t.Fee * value / total
+	case feeCoin == base && t.Amount > 0:
+		fee = t.Fee * value / t.Amount
+	default:
+		price, err := l.price(feeCoin, e.at)
+		if err != nil {
+			p.UnpricedTrades = append(p.UnpricedTrades, UnpricedTrade{
+				Exchange: e.exchange,
+				Trade:    t,
+				Time:     e.at,
+				Coin:     feeCoin,
+				Fee:      true,
+			})
+		}
+		fee = t.Fee * price
+	}
+
+	sent, received := quote, base
+	sentAmount, receivedAmount := total, t.Amount
+	proceeds, cost := value, value+fee
+	if s == schemas.TypeSell {
+		sent, received = base, quote
+		sentAmount, receivedAmount = t.Amount, total
+		proceeds, cost = value-fee, value
+	}
+	if received != l.config.Currency {
+		open.acquire(Lot{
+			Coin:     received,
+			Amount:   receivedAmount,
+			Cost:     cost,
+			Time:     e.at,
+			Exchange: e.exchange,
+			TradeID:  t.ID,
+		})
+	}
+	if sent != l.config.Currency {
+		p.dispose(open.dispose(Disposal{
+			Coin:     sent,
+			Amount:   sentAmount,
+			Proceeds: proceeds,
+			Time:     e.at,
+			Exchange: e.exchange,
+			TradeID:  t.ID,
+		}))
+	}
+	if t.Fee != 0 && feeCoin != l.config.Currency {
+		p.dispose(open.dispose(Disposal{
+			Coin:     feeCoin,
+			Amount:   t.Fee,
+			Proceeds: fee,
+			Time:     e.at,
+			Exchange: e.exchange,
+			TradeID:  t.ID,
+			Fee:      true,
+		}))
+	}
+	p.Fees += fee
+	p.Trades++
+	return nil
+}
+
+// side - BUY or SELL, some exchanges send bid and ask
+func side(t string) string {
+	switch t = strings.ToUpper(t); t {
+	case "BID":
+		return schemas.TypeBuy
+	case "ASK":
+		return schemas.TypeSell
+	}
+	return t
+}
+
+// coins - base and quote coins of symbol
+func coins(symbol string) (base, quote string) {
+	parts := strings.Split(symbol, "-")
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
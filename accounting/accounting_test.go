@@ -0,0 +1,60 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+func TestAddDeduplicates(t *testing.T) {
+	l, err := New(Config{Currency: "USDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trades := []schemas.Trade{
+		{ID: "1", Symbol: "BTC-USDT", Type: schemas.TypeBuy, Price: 100, Amount: 1, Timestamp: 1514764800},
+		{OrderID: "7", Symbol: "BTC-USDT", Type: schemas.TypeBuy, Price: 100, Amount: 1, Timestamp: 1514764800},
+		{OrderID: "7", Symbol: "BTC-USDT", Type: schemas.TypeBuy, Price: 100, Amount: 2, Timestamp: 1514764800},
+	}
+	l.Add("binance", trades)
+	l.Add("binance", trades)
+	l.Add("kucoin", trades[1:2])
+	if len(l.entries) != 4 {
+		t.Errorf("%d entries, expected 4", len(l.entries))
+	}
+}
+
+func TestReportUnpriced(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	l, err := New(Config{Currency: "USDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Add("binance", []schemas.Trade{
+		{ID: "1", Symbol: "BTC-USDT", Type: schemas.TypeBuy, Price: 100, Amount: 1, Timestamp: start.Unix()},
+		// neither XRP nor ETH has price
+		{ID: "2", Symbol: "XRP-ETH", Type: schemas.TypeBuy, Price: 0.001, Amount: 10, Timestamp: start.Unix() + 60},
+		// fee in BNB without price
+		{ID: "3", Symbol: "BTC-USDT", Type: schemas.TypeSell, Price: 150, Amount: 1, Fee: 0.1, FeeCoin: "BNB", Timestamp: start.Unix() + 120},
+	})
+	r, err := l.Report("", start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("report error: %v", err)
+	}
+	if len(r.UnpricedTrades) != 2 {
+		t.Fatalf("%d unpriced trades, expected 2", len(r.UnpricedTrades))
+	}
+	if u := r.UnpricedTrades[0]; u.Trade.ID != "2" || u.Coin != "ETH" || u.Fee {
+		t.Errorf("unpriced trade %+v, expected trade 2 in ETH", u)
+	}
+	if u := r.UnpricedTrades[1]; u.Trade.ID != "3" || u.Coin != "BNB" || !u.Fee {
+		t.Errorf("unpriced trade %+v, expected fee of trade 3 in BNB", u)
+	}
+	if r.Periods[0].Trades != 2 || r.Realized != 50 {
+		t.Errorf("%d trades with realized %v, expected 2 with 50", r.Periods[0].Trades, r.Realized)
+	}
+	if r.Unmatched["BNB"] != 0.1 {
+		t.Errorf("unmatched BNB %v, expected 0.1", r.Unmatched["BNB"])
+	}
+}
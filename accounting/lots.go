@@ -0,0 +1,125 @@
+package accounting
+
+import (
+	"sort"
+	"time"
+)
+
+// dust - lot remainder which is removed, float rounding of fully disposed lot
+const dust = 1e-12
+
+// Lot - acquired amount of coin with its cost in report currency, fees included
+type Lot struct {
+	Coin     string
+	Amount   float64
+	Cost     float64
+	Time     time.Time
+	Exchange string
+	TradeID  string
+}
+
+// Disposal - sold or spent amount of coin matched with lots
+type Disposal struct {
+	Coin     string
+	Amount   float64
+	Proceeds float64 // in report currency, fees excluded
+	Cost     float64 // cost of matched lots
+	PnL      float64 // realized: proceeds - cost
+	Time     time.Time
+	Acquired time.Time // time of first matched lot
+	Exchange string
+	TradeID  string
+	Fee      bool // fee paid in coin
+
+	// amount without lots, i.e. coins acquired before imported history. Its cost is 0
+	Unmatched float64
+}
+
+// lots - open lots of coins by accounting method
+type lots struct {
+	method string
+	coins  map[string][]Lot
+}
+
+func newLots(method string) *lots {
+	return &lots{
+		method: method,
+		coins:  make(map[string][]Lot),
+	}
+}
+
+/*
+acquire - adding lot. Average cost method keeps one lot per coin:
+amounts and costs are summed, time of first lot is kept
+*/
+func (l *lots) acquire(lot Lot) {
+	open := l.coins[lot.Coin]
+	if l.method == Average && len(open) > 0 {
+		open[0].Amount += lot.Amount
+		open[0].Cost += lot.Cost
+		return
+	}
+	l.coins[lot.Coin] = append(open, lot)
+}
+
+// dispose - removing amount from lots: oldest first (FIFO), newest first (LIFO) or one average lot
+func (l *lots) dispose(d Disposal) Disposal {
+	open := l.coins[d.Coin]
+	rest := d.Amount
+	for rest > dust && len(open) > 0 {
+		i := 0
+		if l.method == LIFO {
+			i = len(open) - 1
+		}
+		lot := &open[i]
+		if d.Acquired.IsZero() || lot.Time.Before(d.Acquired) {
+			d.Acquired = lot.Time
+		}
+		if rest < lot.Amount-dust {
+			cost := lot.Cost * rest / lot.Amount
+			d.Cost += cost
+			lot.Cost -= cost
+			lot.Amount -= rest
+			rest = 0
+			break
+		}
+		d.Cost += lot.Cost
+		rest -= lot.Amount
+		if l.method == LIFO {
+			open = open[:i]
+		} else {
+			open = open[1:]
+		}
+	}
+	if len(open) == 0 {
+		delete(l.coins, d.Coin)
+	} else {
+		l.coins[d.Coin] = open
+	}
+	if rest > dust {
+		d.Unmatched = rest
+	}
+	d.PnL = d.Proceeds - d.Cost
+	return d
+}
+
+// positions - open lots of every coin, coins are sorted
+func (l *lots) positions() (positions []Position) {
+	coins := make([]string, 0, len(l.coins))
+	for coin := range l.coins {
+		coins = append(coins, coin)
+	}
+	sort.Strings(coins)
+	for _, coin := range coins {
+		p := Position{
+			Coin: coin,
+			Lots: append([]Lot(nil), l.coins[coin]...),
+		}
+		for _, lot := range p.Lots {
+			p.Amount += lot.Amount
+			p.Cost += lot.Cost
+		}
+		positions = append(positions, p)
+	}
+	return
+}
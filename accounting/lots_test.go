@@ -0,0 +1,110 @@
+package accounting
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLots(t *testing.T) {
+	first := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		method    string
+		amount    float64 // disposed amount with proceeds 300 per coin
+		cost      float64
+		unmatched float64
+		acquired  time.Time
+		left      []Lot // open lots after disposal
+	}{
+		{
+			name:     "FIFO takes oldest lot first",
+			method:   FIFO,
+			amount:   1.5,
+			cost:     100 + 0.5*200,
+			acquired: first,
+			left:     []Lot{{Amount: 0.5, Cost: 100, Time: second}},
+		},
+		{
+			name:     "LIFO takes newest lot first",
+			method:   LIFO,
+			amount:   1.5,
+			cost:     200 + 0.5*100,
+			acquired: first,
+			left:     []Lot{{Amount: 0.5, Cost: 50, Time: first}},
+		},
+		{
+			name:     "LIFO within newest lot",
+			method:   LIFO,
+			amount:   0.5,
+			cost:     100,
+			acquired: second,
+			left:     []Lot{{Amount: 1, Cost: 100, Time: first}, {Amount: 0.5, Cost: 100, Time: second}},
+		},
+		{
+			name:     "average cost of one lot",
+			method:   Average,
+			amount:   1.5,
+			cost:     1.5 * 150,
+			acquired: first,
+			left:     []Lot{{Amount: 0.5, Cost: 75, Time: first}},
+		},
+		{
+			name:      "amount without lots has zero cost",
+			method:    FIFO,
+			amount:    3,
+			cost:      300,
+			unmatched: 1,
+			acquired:  first,
+		},
+		{
+			name:     "whole lots",
+			method:   FIFO,
+			amount:   2,
+			cost:     300,
+			acquired: first,
+		},
+	}
+	for _, tt := range tests {
+		l := newLots(tt.method)
+		l.acquire(Lot{Coin: "BTC", Amount: 1, Cost: 100, Time: first})
+		l.acquire(Lot{Coin: "BTC", Amount: 1, Cost: 200, Time: second})
+		proceeds := tt.amount * 300
+		d := l.dispose(Disposal{Coin: "BTC", Amount: tt.amount, Proceeds: proceeds})
+		if math.Abs(d.Cost-tt.cost) > 1e-9 || math.Abs(d.PnL-(proceeds-tt.cost)) > 1e-9 {
+			t.Errorf("%s: cost %v, PnL %v, want %v, %v", tt.name, d.Cost, d.PnL, tt.cost, proceeds-tt.cost)
+		}
+		if math.Abs(d.Unmatched-tt.unmatched) > 1e-9 {
+			t.Errorf("%s: unmatched %v, want %v", tt.name, d.Unmatched, tt.unmatched)
+		}
+		if !d.Acquired.Equal(tt.acquired) {
+			t.Errorf("%s: acquired %v, want %v", tt.name, d.Acquired, tt.acquired)
+		}
+
+		positions := l.positions()
+		if len(tt.left) == 0 {
+			if len(positions) != 0 {
+				t.Errorf("%s: positions %+v, want none", tt.name, positions)
+			}
+			continue
+		}
+		if len(positions) != 1 || len(positions[0].Lots) != len(tt.left) {
+			t.Errorf("%s: positions %+v, want lots %+v", tt.name, positions, tt.left)
+			continue
+		}
+		var amount, cost float64
+		for i, lot := range positions[0].Lots {
+			want := tt.left[i]
+			if math.Abs(lot.Amount-want.Amount) > 1e-9 || math.Abs(lot.Cost-want.Cost) > 1e-9 || !lot.Time.Equal(want.Time) {
+				t.Errorf("%s: lot %d = %v %v %v, want %v %v %v", tt.name, i, lot.Amount, lot.Cost, lot.Time, want.Amount, want.Cost, want.Time)
+			}
+			amount += want.Amount
+			cost += want.Cost
+		}
+		if p := positions[0]; math.Abs(p.Amount-amount) > 1e-9 || math.Abs(p.Cost-cost) > 1e-9 {
+			t.Errorf("%s: position %v %v, want %v %v", tt.name, p.Amount, p.Cost, amount, cost)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package accounting
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// ErrNoPrice - coin has no price at time
+var ErrNoPrice = errors.New("Price not found")
+
+// Prices - historical prices of coins in report currency
+type Prices interface {
+	Price(coin string, at time.Time) (float64, error)
+}
+
+type pricePoint struct {
+	at    time.Time
+	price float64
+}
+
+// PriceTable - prices of coins by time, last known price at time is used
+type PriceTable struct {
+	prices map[string][]pricePoint
+	sorted map[string]bool
+
+	sync.Mutex
+}
+
+// NewPriceTable - PriceTable constructor
+func NewPriceTable() *PriceTable {
+	return &PriceTable{
+		prices: make(map[string][]pricePoint),
+		sorted: make(map[string]bool),
+	}
+}
+
+// Add - adding price of coin at time
+func (t *PriceTable) Add(coin string, at time.Time, price float64) {
+	t.Lock()
+	defer t.Unlock()
+	t.prices[coin] = append(t.prices[coin], pricePoint{at: at, price: price})
+	t.sorted[coin] = false
+}
+
+// AddCandles - adding close prices of coin candles, i.e. candles of COIN-USDT symbol
+func (t *PriceTable) AddCandles(coin string, candles []schemas.Candle) {
+	for _, c := range candles {
+		at := schemas.UnixTime(c.Timestamp).Add(time.Duration(c.Discretization) * time.Second)
+		t.Add(coin, at, c.Close)
+	}
+}
+
+// Price - last price of coin at or before time
+func (t *PriceTable) Price(coin string, at time.Time) (float64, error) {
+	t.Lock()
+	defer t.Unlock()
+	points := t.prices[coin]
+	if !t.sorted[coin] {
+		sort.SliceStable(points, func(i, j int) bool {
+			return points[i].at.Before(points[j].at)
+		})
+		t.sorted[coin] = true
+	}
+	i := sort.Search(len(points), func(i int) bool {
+		return points[i].at.After(at)
+	})
+	if i == 0 {
+		return 0, ErrNoPrice
+	}
+	return points[i-1].price, nil
+}
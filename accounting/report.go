@@ -0,0 +1,201 @@
+package accounting
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// Periods
+const (
+	Day     = "day"
+	Month   = "month"
+	Quarter = "quarter"
+	Year    = "year"
+)
+
+// Position - open lots of coin at time with market value
+type Position struct {
+	Coin       string
+	Amount     float64
+	Cost       float64
+	Price      float64 // 0 if coin has no price
+	Value      float64
+	Unrealized float64 // value - cost
+	Lots       []Lot
+}
+
+// UnpricedTrade - trade without price of coin in report currency at trade time
+type UnpricedTrade struct {
+	Exchange string
+	Trade    schemas.Trade
+	Time     time.Time
+	Coin     string // coin without price: quote coin of trade or fee coin
+	// fee coin has no price: trade is in lots, fee is disposed with zero value.
+	// Otherwise trade isn't in lots
+	Fee bool
+}
+
+// PeriodReport - disposals of period and positions at its end
+type PeriodReport struct {
+	Start, End time.Time
+	Trades     int
+	Disposals  []Disposal
+	Proceeds   float64
+	Cost       float64
+	Realized   float64
+	Fees       float64 // in report currency
+	Positions  []Position
+	Unrealized float64
+	Unpriced   []string // coins of positions without price, they aren't in unrealized PnL
+	// trades of period without price, they aren't in PnL or are without fee value
+	UnpricedTrades []UnpricedTrade
+}
+
+func (p *PeriodReport) dispose(d Disposal) {
+	p.Disposals = append(p.Disposals, d)
+	p.Proceeds += d.Proceeds
+	p.Cost += d.Cost
+	p.Realized += d.PnL
+}
+
+// Report - accounting report of periods
+type Report struct {
+	Currency   string
+	Method     string
+	Start, End time.Time
+	Periods    []PeriodReport
+	Realized   float64
+	Fees       float64
+	Unrealized float64    // at report end
+	Positions  []Position // at report end
+	Unpriced   []string
+	// trades without price of all periods and trades before start, lots are incomplete
+	UnpricedTrades []UnpricedTrade
+
+	// disposed amounts without lots by coin, history is incomplete
+	Unmatched map[string]float64
+}
+
+/*
+Report - report of periods from start till end, one period if period is empty.
+Lots are built from every trade, trades before start are not reported.
+Zero start is time of first trade, zero end is now
+*/
+func (l *Ledger) Report(period string, start, end time.Time) (r Report, err error) {
+	entries := l.sorted()
+	if start.IsZero() && len(entries) > 0 {
+		start = entries[0].at
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if !start.Before(end) {
+		return r, fmt.Errorf("Report start %s is not before end %s", start, end)
+	}
+	r = Report{
+		Currency:  l.config.Currency,
+		Method:    l.config.Method,
+		Start:     start,
+		End:       end,
+		Unmatched: make(map[string]float64),
+	}
+	open := newLots(l.config.Method)
+	var history PeriodReport // trades before start
+	current := PeriodReport{Start: start}
+	if current.End, err = l.periodEnd(period, start, end); err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !e.at.Before(end) {
+			break
+		}
+		if e.at.Before(start) {
+			if err = l.apply(open, e, &history); err != nil {
+				return
+			}
+			continue
+		}
+		for !e.at.Before(current.End) {
+			r.Periods = append(r.Periods, l.closePeriod(open, current))
+			current = PeriodReport{Start: current.End}
+			if current.End, err = l.periodEnd(period, current.Start, end); err != nil {
+				return
+			}
+		}
+		if err = l.apply(open, e, &current); err != nil {
+			return
+		}
+	}
+	for {
+		r.Periods = append(r.Periods, l.closePeriod(open, current))
+		if !current.End.Before(end) {
+			break
+		}
+		current = PeriodReport{Start: current.End}
+		if current.End, err = l.periodEnd(period, current.Start, end); err != nil {
+			return
+		}
+	}
+	r.UnpricedTrades = history.UnpricedTrades
+	for _, p := range r.Periods {
+		r.UnpricedTrades = append(r.UnpricedTrades, p.UnpricedTrades...)
+		r.Realized += p.Realized
+		r.Fees += p.Fees
+		for _, d := range p.Disposals {
+			if d.Unmatched > 0 {
+				r.Unmatched[d.Coin] += d.Unmatched
+			}
+		}
+	}
+	last := r.Periods[len(r.Periods)-1]
+	r.Positions = last.Positions
+	r.Unrealized = last.Unrealized
+	r.Unpriced = last.Unpriced
+	return
+}
+
+// closePeriod - positions of period end at market prices
+func (l *Ledger) closePeriod(open *lots, p PeriodReport) PeriodReport {
+	p.Positions = open.positions()
+	for i := range p.Positions {
+		pos := &p.Positions[i]
+		price, err := l.price(pos.Coin, p.End)
+		if err != nil {
+			p.Unpriced = append(p.Unpriced, pos.Coin)
+			continue
+		}
+		pos.Price = price
+		pos.Value = pos.Amount * price
+		pos.Unrealized = pos.Value - pos.Cost
+		p.Unrealized += pos.Unrealized
+	}
+	sort.Strings(p.Unpriced)
+	return p
+}
+
+// periodEnd - start of next period in configured time zone, not after end
+func (l *Ledger) periodEnd(period string, start, end time.Time) (time.Time, error) {
+	t := start.In(l.config.Location)
+	var next time.Time
+	switch period {
+	case "":
+		return end, nil
+	case Day:
+		next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+	case Month:
+		next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+	case Quarter:
+		next = time.Date(t.Year(), t.Month()-(t.Month()-1)%3+3, 1, 0, 0, 0, 0, t.Location())
+	case Year:
+		next = time.Date(t.Year()+1, 1, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return end, fmt.Errorf("Unknown period: %s", period)
+	}
+	if next.After(end) {
+		return end, nil
+	}
+	return next, nil
+}
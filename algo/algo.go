@@ -0,0 +1,579 @@
+package algo
+
+import (
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// Algorithm kinds
+const (
+	KindTWAP    = "TWAP"
+	KindVWAP    = "VWAP"
+	KindIceberg = "ICEBERG"
+)
+
+// Algorithm states
+const (
+	StateRunning = "RUNNING"
+	StatePaused  = "PAUSED"
+	StateDone    = "DONE"
+	StateAborted = "ABORTED"
+)
+
+const (
+	commandPause  = "pause"
+	commandResume = "resume"
+	commandAbort  = "abort"
+
+	// amountEpsilon - float tail ignored when comparing amounts
+	amountEpsilon = 1e-9
+
+	// retryDelay - first delay of sending child again after failure, doubled up to maxRetryDelay
+	retryDelay    = time.Second
+	maxRetryDelay = time.Minute
+	// cancelTimeout - waiting for cancel acknowledge before child is checked in open orders
+	cancelTimeout = 10 * time.Second
+)
+
+// Progress - state and fills of algorithm
+type Progress struct {
+	Kind         string
+	State        string
+	Amount       float64
+	Filled       float64
+	AveragePrice float64 // average price of fills
+	Children     int     // sent children count
+	// last error of sending or cancelling child, it's kept until next child is sent
+	Error error
+}
+
+// Remaining - amount which is not filled yet
+func (p Progress) Remaining() float64 {
+	return p.Amount - p.Filled
+}
+
+// slice - cumulative amount which has to be sent by time
+type slice struct {
+	at     time.Time
+	target float64
+}
+
+// child - sent child order and its fills
+type child struct {
+	order  schemas.Order
+	trades map[string]schemas.Trade
+	// filled amount from order updates, used when exchange trades have no order ID
+	reported float64
+	open     bool
+	// time of cancel request, child is open until cancel acknowledge or final fill
+	cancelled time.Time
+}
+
+func (c *child) filled() (amount, cost float64) {
+	for _, t := range c.trades {
+		amount += t.Amount
+		cost += t.Amount * t.Price
+	}
+	if c.reported > amount {
+		cost += (c.reported - amount) * c.order.Price
+		amount = c.reported
+	}
+	return
+}
+
+// Algo - running execution algorithm
+type Algo struct {
+	engine  *Engine
+	kind    string
+	order   Order
+	slices  []slice
+	next    int
+	visible float64
+
+	state    string
+	current  *child
+	children map[string]*child
+	count    int
+	err      error
+
+	// child which has to be sent once open children are closed,
+	// used by run loop only
+	due     float64 // cumulative amount due by schedule
+	pending bool
+	wake    time.Time
+	retryAt time.Time
+	retry   time.Duration
+
+	events   chan event
+	commands chan string
+	updates  chan Progress
+	done     chan struct{}
+
+	sync.Mutex
+}
+
+func newAlgo(e *Engine, kind string, order Order) *Algo {
+	order.Side = strings.ToUpper(order.Side)
+	return &Algo{
+		engine:   e,
+		kind:     kind,
+		order:    order,
+		state:    StateRunning,
+		children: make(map[string]*child),
+		events:   make(chan event, eventsBufferSize),
+		commands: make(chan string),
+		updates:  make(chan Progress, eventsBufferSize),
+		done:     make(chan struct{}),
+	}
+}
+
+// Progress - current state and fills
+func (a *Algo) Progress() Progress {
+	a.Lock()
+	defer a.Unlock()
+	return a.progress()
+}
+
+// Updates - progress after every change, closed when algorithm is finished.
+// Updates are skipped while channel buffer is full
+func (a *Algo) Updates() chan Progress {
+	return a.updates
+}
+
+// Done - closed when algorithm is finished
+func (a *Algo) Done() chan struct{} {
+	return a.done
+}
+
+// Pause - cancelling open child and stopping sending new ones
+func (a *Algo) Pause() {
+	a.command(commandPause)
+}
+
+// Resume - continuing paused algorithm, slices which were due while paused are sent at once
+func (a *Algo) Resume() {
+	a.command(commandResume)
+}
+
+// Abort - cancelling open child and finishing algorithm
+func (a *Algo) Abort() {
+	a.command(commandAbort)
+}
+
+func (a *Algo) command(cmd string) {
+	select {
+	case a.commands <- cmd:
+	case <-a.done:
+	}
+}
+
+func (a *Algo) send(e event) {
+	select {
+	case a.events <- e:
+	case <-a.done:
+	}
+}
+
+// run - algorithm loop: sending slices by schedule, applying fills, executing commands
+func (a *Algo) run() {
+	defer func() {
+		a.engine.remove(a)
+		close(a.done)
+		close(a.updates)
+	}()
+
+	if a.kind == KindIceberg {
+		a.pending = true
+		a.flush()
+	}
+	for !a.finished() {
+		select {
+		case <-a.timer():
+			a.onSlice()
+		case <-a.wakeTimer():
+			a.flush()
+		case e := <-a.events:
+			a.onEvent(e)
+		case cmd := <-a.commands:
+			a.onCommand(cmd)
+		}
+		a.publish()
+	}
+}
+
+// timer - time of next slice, nil channel if there are no slices or algorithm is paused
+func (a *Algo) timer() <-chan time.Time {
+	if a.getState() != StateRunning || a.next >= len(a.slices) {
+		return nil
+	}
+	return time.After(time.Until(a.slices[a.next].at))
+}
+
+// wakeTimer - time of checking cancelled children or retrying failed child, nil channel if nothing is pending
+func (a *Algo) wakeTimer() <-chan time.Time {
+	if !a.pending || a.wake.IsZero() || a.getState() != StateRunning {
+		return nil
+	}
+	return time.After(time.Until(a.wake))
+}
+
+// onSlice - cancelling open child, replacement of amount due by now is sent when it's closed
+func (a *Algo) onSlice() {
+	now := time.Now()
+	for a.next < len(a.slices) && !a.slices[a.next].at.After(now) {
+		a.due = a.slices[a.next].target
+		a.next++
+	}
+	a.cancel()
+	a.pending = true
+	a.retryAt = time.Time{}
+	a.flush()
+}
+
+func (a *Algo) onEvent(e event) {
+	a.Lock()
+	var c *child
+	if e.trade != nil {
+		c = a.children[e.trade.OrderID]
+		if c != nil {
+			c.trades[tradeKey(*e.trade)] = *e.trade
+		}
+	}
+	if e.order != nil {
+		c = a.children[e.order.ID]
+		if c != nil {
+			c.reported = math.Max(c.reported, e.order.AmountFilled)
+			if e.order.Status == schemas.StatusCancelled || e.order.Status == schemas.StatusRejected {
+				c.open = false
+			}
+		}
+	}
+	if c != nil {
+		if amount, _ := c.filled(); amount >= c.order.Amount-amountEpsilon {
+			c.open = false
+		}
+	}
+	closed := a.current != nil && !a.current.open
+	if closed {
+		a.current = nil
+		if a.kind == KindIceberg {
+			a.pending = true
+		}
+	}
+	a.Unlock()
+
+	a.flush()
+}
+
+func (a *Algo) onCommand(cmd string) {
+	switch cmd {
+	case commandPause:
+		if a.getState() != StateRunning {
+			return
+		}
+		a.cancel()
+		a.setState(StatePaused)
+	case commandResume:
+		if a.getState() != StatePaused {
+			return
+		}
+		a.setState(StateRunning)
+		a.pending = true
+		a.retryAt = time.Time{}
+		a.flush()
+	case commandAbort:
+		a.cancel()
+		a.setState(StateAborted)
+	}
+}
+
+/*
+flush - sending pending child when there are no open children,
+so replacement is sized by final fills of cancelled ones.
+Iceberg child has visible amount, scheduled child has amount due minus filled.
+Amount less than symbol min is carried to next slice, other failures are retried with backoff
+*/
+func (a *Algo) flush() {
+	if !a.pending || a.getState() != StateRunning {
+		return
+	}
+	now := time.Now()
+	if a.waiting(now) {
+		if !a.wake.After(now) {
+			a.wake = now.Add(cancelTimeout)
+		}
+		return
+	}
+	if now.Before(a.retryAt) {
+		a.wake = a.retryAt
+		return
+	}
+	amount := a.visible
+	if a.kind != KindIceberg {
+		a.Lock()
+		filled, open := a.amounts()
+		a.Unlock()
+		amount = a.due - filled - open
+	}
+	// trades of new child can close it and make next child pending
+	a.pending = false
+	err := a.place(amount)
+	if verr, ok := err.(schemas.ValidationError); ok && verr.Reason == schemas.ReasonMinAmount {
+		err = nil
+	}
+	if err != nil {
+		a.pending = true
+		if a.retry *= 2; a.retry < retryDelay {
+			a.retry = retryDelay
+		} else if a.retry > maxRetryDelay {
+			a.retry = maxRetryDelay
+		}
+		a.retryAt = now.Add(a.retry)
+		a.wake = a.retryAt
+		return
+	}
+	a.retry = 0
+	a.retryAt = time.Time{}
+	if !a.pending {
+		a.wake = time.Time{}
+	}
+}
+
+/*
+waiting - there are open children to be closed before next one is sent.
+Child without cancel acknowledge after cancelTimeout, cancel failed or not,
+is checked in open orders: it's closed if exchange doesn't have it, cancel is sent again otherwise
+*/
+func (a *Algo) waiting(now time.Time) bool {
+	a.cancel()
+	a.Lock()
+	var expired []*child
+	for _, c := range a.children {
+		if c.open && !c.cancelled.IsZero() && now.Sub(c.cancelled) >= cancelTimeout {
+			expired = append(expired, c)
+		}
+	}
+	a.Unlock()
+	if len(expired) > 0 {
+		orders, err := a.engine.trading.Orders([]schemas.Symbol{a.order.Symbol})
+		if err != nil {
+			log.Println("[ALGO] Error loading open orders:", err)
+			a.setError(err)
+		} else {
+			open := make(map[string]bool)
+			for _, o := range orders {
+				open[o.ID] = true
+			}
+			a.Lock()
+			for _, c := range expired {
+				if open[c.order.ID] {
+					// cancel is sent again
+					c.cancelled = time.Time{}
+				} else {
+					c.open = false
+				}
+			}
+			a.Unlock()
+		}
+	}
+	a.Lock()
+	defer a.Unlock()
+	for _, c := range a.children {
+		if c.open {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+place - sending child of amount, capped by amount which is not filled and not in open children.
+Amount is rounded by symbol step
+*/
+func (a *Algo) place(amount float64) error {
+	a.Lock()
+	filled, open := a.amounts()
+	a.Unlock()
+	amount = math.Min(amount, a.order.Amount-filled-open)
+	if amount <= amountEpsilon {
+		return nil
+	}
+	price := a.order.Price
+	if a.order.PriceFunc != nil {
+		p, err := a.order.PriceFunc()
+		if err != nil {
+			a.setError(err)
+			return err
+		}
+		if price == 0 || !schemas.WorsePrice(a.order.Side, p, price) {
+			price = p
+		}
+	}
+	order, err := a.order.Symbol.ValidateOrder(schemas.Order{
+		Symbol: a.order.Symbol.Name,
+		Type:   a.order.Side,
+		Price:  price,
+		Amount: amount,
+	})
+	if err != nil {
+		log.Println("[ALGO] Child is not sent:", err)
+		a.setError(err)
+		return err
+	}
+	result, err := a.engine.trading.Create(order)
+	if err != nil {
+		log.Println("[ALGO] Error creating child:", err)
+		a.setError(err)
+		return err
+	}
+	order.ID = result.ID
+	c := &child{
+		order:  order,
+		trades: make(map[string]schemas.Trade),
+		open:   true,
+	}
+	trades := a.engine.register(order.ID, a)
+
+	a.Lock()
+	a.children[order.ID] = c
+	a.current = c
+	a.count++
+	a.err = nil
+	a.Unlock()
+
+	for i := range trades {
+		a.onEvent(event{trade: &trades[i]})
+	}
+	return nil
+}
+
+/*
+cancel - sending cancel of open children. Child stays open until cancel acknowledge
+or final fill, its fills coming later are still counted.
+Failed cancel is marked too: exchange rejects cancel of closed order, so child is checked
+in open orders after cancelTimeout and cancel is sent again only if it's still open
+*/
+func (a *Algo) cancel() {
+	a.Lock()
+	a.current = nil
+	var open []*child
+	for _, c := range a.children {
+		if c.open && c.cancelled.IsZero() {
+			open = append(open, c)
+		}
+	}
+	a.Unlock()
+	for _, c := range open {
+		if err := a.engine.trading.Cancel(c.order); err != nil {
+			log.Println("[ALGO] Error cancelling child:", err)
+			a.setError(err)
+		}
+		a.Lock()
+		c.cancelled = time.Now()
+		a.Unlock()
+	}
+}
+
+/*
+finished - order is filled, aborted or there is nothing to send anymore:
+schedule is over (iceberg has no schedule), no child is pending and there are no open children
+*/
+func (a *Algo) finished() bool {
+	a.Lock()
+	defer a.Unlock()
+	switch a.state {
+	case StateAborted, StateDone:
+		return true
+	case StatePaused:
+		return false
+	}
+	filled, open := a.amounts()
+	if filled >= a.order.Amount-amountEpsilon ||
+		(!a.pending && a.next >= len(a.slices) && open == 0) {
+		a.state = StateDone
+		return true
+	}
+	return false
+}
+
+func (a *Algo) publish() {
+	select {
+	case a.updates <- a.Progress():
+	default:
+	}
+}
+
+// progress - has to be called under lock
+func (a *Algo) progress() Progress {
+	filled, cost := a.fills()
+	p := Progress{
+		Kind:     a.kind,
+		State:    a.state,
+		Amount:   a.order.Amount,
+		Filled:   filled,
+		Children: a.count,
+		Error:    a.err,
+	}
+	if filled > 0 {
+		p.AveragePrice = cost / filled
+	}
+	return p
+}
+
+// fills - has to be called under lock
+func (a *Algo) fills() (amount, cost float64) {
+	for _, c := range a.children {
+		f, fc := c.filled()
+		amount += f
+		cost += fc
+	}
+	return
+}
+
+// amounts - filled amount and not filled amount of open children, has to be called under lock
+func (a *Algo) amounts() (filled, open float64) {
+	for _, c := range a.children {
+		f, _ := c.filled()
+		filled += f
+		if c.open {
+			open += math.Max(c.order.Amount-f, 0)
+		}
+	}
+	return
+}
+
+func (a *Algo) getState() string {
+	a.Lock()
+	defer a.Unlock()
+	return a.state
+}
+
+func (a *Algo) setState(state string) {
+	a.Lock()
+	defer a.Unlock()
+	a.state = state
+}
+
+func (a *Algo) setError(err error) {
+	a.Lock()
+	defer a.Unlock()
+	a.err = err
+}
+
+// tradeKey - trade ID, trades without ID are compared by fields
+func tradeKey(t schemas.Trade) string {
+	if t.ID != "" {
+		return t.ID
+	}
+	return strings.Join([]string{
+		t.OrderID,
+		schemas.DecimalFromFloat(t.Price).String(),
+		schemas.DecimalFromFloat(t.Amount).String(),
+		schemas.DecimalFromFloat(float64(t.Timestamp)).String(),
+	}, ":")
+}
@@ -0,0 +1,195 @@
+package algo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// fakeTrading - trading provider recording children, Create fails while err is set, Cancel while cancelErr is set
+type fakeTrading struct {
+	created   []schemas.Order
+	cancelled []string
+	open      []schemas.Order
+	err       error
+	cancelErr error
+}
+
+func (f *fakeTrading) Info() (schemas.UserInfo, error) { return schemas.UserInfo{}, nil }
+func (f *fakeTrading) Orders(symbols []schemas.Symbol) ([]schemas.Order, error) {
+	return f.open, nil
+}
+func (f *fakeTrading) Trades(schemas.FilterOptions) ([]schemas.Trade, schemas.Paging, error) {
+	return nil, schemas.Paging{}, nil
+}
+func (f *fakeTrading) ImportTrades(schemas.FilterOptions) chan schemas.UserTradesChannel {
+	return nil
+}
+func (f *fakeTrading) Subscribe(time.Duration) (chan schemas.UserInfoChannel, chan schemas.UserOrdersChannel, chan schemas.UserTradesChannel) {
+	return nil, nil, nil
+}
+func (f *fakeTrading) Create(order schemas.Order) (schemas.Order, error) {
+	if f.err != nil {
+		return order, f.err
+	}
+	order.ID = strconv.Itoa(len(f.created) + 1)
+	f.created = append(f.created, order)
+	return order, nil
+}
+func (f *fakeTrading) Cancel(order schemas.Order) error {
+	f.cancelled = append(f.cancelled, order.ID)
+	return f.cancelErr
+}
+func (f *fakeTrading) CancelAll() error { return nil }
+func (f *fakeTrading) CancelAllBy(symbols []schemas.Symbol, side string) ([]schemas.Order, error) {
+	return nil, nil
+}
+
+// step - action on algorithm and amount of last created child after it
+type step struct {
+	name    string
+	do      func(a *Algo, f *fakeTrading)
+	created int     // children created so far
+	amount  float64 // amount of last child
+}
+
+// nextSlice - making next slice due
+func nextSlice(a *Algo, f *fakeTrading) {
+	a.slices[a.next].at = time.Now()
+	a.onSlice()
+}
+
+// fill - trade of child
+func fill(id string, amount float64) func(a *Algo, f *fakeTrading) {
+	return func(a *Algo, f *fakeTrading) {
+		a.onEvent(event{trade: &schemas.Trade{ID: id + ":" + strconv.FormatFloat(amount, 'f', -1, 64), OrderID: id, Amount: amount, Price: 100}})
+	}
+}
+
+// cancelled - cancel acknowledge of child with its final filled amount
+func cancelled(id string, filled float64) func(a *Algo, f *fakeTrading) {
+	return func(a *Algo, f *fakeTrading) {
+		a.onEvent(event{order: &schemas.Order{ID: id, AmountFilled: filled, Status: schemas.StatusCancelled}})
+	}
+}
+
+func TestSlices(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   string
+		slices []float64 // cumulative targets
+		steps  []step
+	}{
+		{
+			name: "iceberg sends visible amount after fill",
+			kind: KindIceberg,
+			steps: []step{
+				{name: "start", do: func(a *Algo, f *fakeTrading) {
+					a.pending = true
+					a.flush()
+				}, created: 1, amount: 1},
+				{name: "partial fill", do: fill("1", 0.5), created: 1, amount: 1},
+				{name: "fill", do: fill("1", 1), created: 2, amount: 1},
+				{name: "last part", do: fill("2", 1), created: 3, amount: 0.5},
+			},
+		},
+		{
+			name:   "replacement waits for cancel acknowledge",
+			slices: []float64{1, 2, 3},
+			steps: []step{
+				{name: "first slice", do: nextSlice, created: 1, amount: 1},
+				{name: "partial fill", do: fill("1", 0.4), created: 1, amount: 1},
+				{name: "second slice cancels child", do: nextSlice, created: 1, amount: 1},
+				{name: "late fill", do: fill("1", 0.2), created: 1, amount: 1},
+				{name: "cancel acknowledge", do: cancelled("1", 0.6), created: 2, amount: 1.4},
+				{name: "third slice", do: nextSlice, created: 2, amount: 1.4},
+				{name: "final fill closes child", do: cancelled("2", 1.4), created: 3, amount: 1},
+			},
+		},
+		{
+			name:   "replacement is capped by parent amount",
+			slices: []float64{2, 3},
+			steps: []step{
+				{name: "first slice", do: nextSlice, created: 1, amount: 2},
+				{name: "second slice", do: nextSlice, created: 1, amount: 2},
+				{name: "filled while cancelling", do: fill("1", 2), created: 2, amount: 1},
+			},
+		},
+		{
+			name:   "failed child is retried",
+			slices: []float64{1},
+			steps: []step{
+				{name: "create fails", do: func(a *Algo, f *fakeTrading) {
+					f.err = errors.New("Exchange is down")
+					nextSlice(a, f)
+				}, created: 0},
+				{name: "retry after backoff", do: func(a *Algo, f *fakeTrading) {
+					if a.finished() || !a.pending || a.Progress().Error == nil {
+						t.Errorf("failed child: finished %v, pending %v, error %v", a.finished(), a.pending, a.Progress().Error)
+					}
+					f.err = nil
+					a.retryAt = time.Now()
+					a.flush()
+				}, created: 1, amount: 1},
+			},
+		},
+		{
+			name:   "child without acknowledge is checked in open orders",
+			slices: []float64{1, 2},
+			steps: []step{
+				{name: "first slice", do: nextSlice, created: 1, amount: 1},
+				{name: "second slice", do: nextSlice, created: 1, amount: 1},
+				{name: "cancel timeout", do: func(a *Algo, f *fakeTrading) {
+					a.children["1"].cancelled = time.Now().Add(-cancelTimeout)
+					a.flush()
+				}, created: 2, amount: 2},
+			},
+		},
+		{
+			name:   "child with failed cancel is checked in open orders",
+			slices: []float64{1, 2},
+			steps: []step{
+				{name: "first slice", do: nextSlice, created: 1, amount: 1},
+				{name: "cancel of closed child fails", do: func(a *Algo, f *fakeTrading) {
+					f.cancelErr = errors.New("Order not found")
+					nextSlice(a, f)
+					if a.children["1"].cancelled.IsZero() {
+						t.Error("failed cancel isn't marked")
+					}
+				}, created: 1, amount: 1},
+				{name: "cancel timeout", do: func(a *Algo, f *fakeTrading) {
+					a.children["1"].cancelled = time.Now().Add(-cancelTimeout)
+					a.flush()
+				}, created: 2, amount: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		f := &fakeTrading{}
+		kind := tt.kind
+		if kind == "" {
+			kind = KindTWAP
+		}
+		a := newAlgo(NewEngine(f, 0), kind, Order{Symbol: schemas.Symbol{Name: "ETH-BTC"}, Side: schemas.TypeBuy, Amount: 3, Price: 100})
+		a.visible = 1
+		for _, target := range tt.slices {
+			a.slices = append(a.slices, slice{at: time.Now().Add(time.Hour), target: target})
+		}
+		for _, s := range tt.steps {
+			s.do(a, f)
+			if len(f.created) != s.created {
+				t.Errorf("%s, %s: created %d children, want %d", tt.name, s.name, len(f.created), s.created)
+				break
+			}
+			if s.created > 0 {
+				if last := f.created[s.created-1]; math.Abs(last.Amount-s.amount) > amountEpsilon {
+					t.Errorf("%s, %s: child amount %v, want %v", tt.name, s.name, last.Amount, s.amount)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Package algo runs execution algorithms on top of exchange TradingProvider:
+parent order is sent as limit children with Create and Cancel,
+fills are observed by trading Subscribe channels
+*/
+package algo
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+const (
+	// unmatchedLimit - trades of unknown orders kept until child order ID is known
+	unmatchedLimit   = 1000
+	eventsBufferSize = 100
+)
+
+// Order - parent order
+type Order struct {
+	Symbol schemas.Symbol // exchange symbol
+	Side   string         // schemas.TypeBuy or schemas.TypeSell
+	Amount float64
+	// limit price of children
+	Price float64
+	// optional price of every child, i.e. best bid or ask. Result is capped by Price if it's set
+	PriceFunc func() (float64, error)
+}
+
+// event - order update or trade of child
+type event struct {
+	order *schemas.Order
+	trade *schemas.Trade
+}
+
+/*
+Engine - running algorithms of one exchange account.
+Trading provider is subscribed once and updates are dispatched to algorithms by order ID
+*/
+type Engine struct {
+	trading schemas.TradingProvider
+	d       time.Duration
+
+	orders    map[string]*Algo           // child order ID to algorithm
+	unmatched map[string][]schemas.Trade // trades of orders not known yet
+	started   bool
+
+	sync.Mutex
+}
+
+// NewEngine - Engine constructor, d is trading Subscribe interval for polling exchanges
+func NewEngine(trading schemas.TradingProvider, d time.Duration) *Engine {
+	return &Engine{
+		trading:   trading,
+		d:         d,
+		orders:    make(map[string]*Algo),
+		unmatched: make(map[string][]schemas.Trade),
+	}
+}
+
+// TWAP - sending equal parts of order with equal intervals during d
+func (e *Engine) TWAP(order Order, d time.Duration, slices int) (*Algo, error) {
+	if slices <= 0 {
+		return nil, fmt.Errorf("Invalid slices count: %d", slices)
+	}
+	profile := make([]float64, slices)
+	for i := range profile {
+		profile[i] = 1
+	}
+	return e.scheduled(KindTWAP, order, d, profile)
+}
+
+/*
+VWAP - sending parts of order by volume profile with equal intervals during d.
+Profile has weight of every slice, i.e. from CandlesProfile or TradesProfile
+*/
+func (e *Engine) VWAP(order Order, d time.Duration, profile []float64) (*Algo, error) {
+	if len(profile) == 0 {
+		return nil, errors.New("Empty volume profile")
+	}
+	return e.scheduled(KindVWAP, order, d, profile)
+}
+
+/*
+Iceberg - showing visible amount of order only:
+next child is sent when previous one is filled or closed
+*/
+func (e *Engine) Iceberg(order Order, visible float64) (*Algo, error) {
+	if visible <= 0 {
+		return nil, fmt.Errorf("Invalid visible amount: %v", visible)
+	}
+	if err := validate(order); err != nil {
+		return nil, err
+	}
+	a := newAlgo(e, KindIceberg, order)
+	a.visible = visible
+	e.start(a)
+	return a, nil
+}
+
+// scheduled - algorithm with slices by profile weights, first slice is sent now
+func (e *Engine) scheduled(kind string, order Order, d time.Duration, profile []float64) (*Algo, error) {
+	if err := validate(order); err != nil {
+		return nil, err
+	}
+	var total float64
+	for _, w := range profile {
+		if w < 0 {
+			return nil, fmt.Errorf("Negative profile weight: %v", w)
+		}
+		total += w
+	}
+	if total == 0 {
+		return nil, errors.New("Empty volume profile")
+	}
+	a := newAlgo(e, kind, order)
+	start := time.Now()
+	interval := d / time.Duration(len(profile))
+	var cumulative float64
+	for i, w := range profile {
+		cumulative += w
+		a.slices = append(a.slices, slice{
+			at:     start.Add(time.Duration(i) * interval),
+			target: order.Amount * cumulative / total,
+		})
+	}
+	e.start(a)
+	return a, nil
+}
+
+func (e *Engine) start(a *Algo) {
+	e.Lock()
+	if !e.started {
+		e.started = true
+		uic, uoc, utc := e.trading.Subscribe(e.d)
+		go e.listen(uic, uoc, utc)
+	}
+	e.Unlock()
+	go a.run()
+}
+
+/*
+listen - dispatching children updates and trades to algorithms.
+Closed channels are not read anymore, provider is subscribed again by next algorithm when all are closed
+*/
+func (e *Engine) listen(uic chan schemas.UserInfoChannel, uoc chan schemas.UserOrdersChannel, utc chan schemas.UserTradesChannel) {
+	for uic != nil || uoc != nil || utc != nil {
+		select {
+		case _, ok := <-uic:
+			// balances are not used, channel is drained to not block provider
+			if !ok {
+				uic = nil
+			}
+		case msg, ok := <-uoc:
+			if !ok {
+				uoc = nil
+				continue
+			}
+			for i := range msg.Data {
+				if a := e.algo(msg.Data[i].ID); a != nil {
+					a.send(event{order: &msg.Data[i]})
+				}
+			}
+		case msg, ok := <-utc:
+			if !ok {
+				utc = nil
+				continue
+			}
+			for i := range msg.Data {
+				t := msg.Data[i]
+				if a := e.algo(t.OrderID); a != nil {
+					a.send(event{trade: &t})
+					continue
+				}
+				e.keepUnmatched(t)
+			}
+		}
+	}
+	log.Println("[ALGO] Trading channels are closed")
+	e.Lock()
+	e.started = false
+	e.Unlock()
+}
+
+// register - routing child updates to algorithm, returns trades which came before order ID was known
+func (e *Engine) register(id string, a *Algo) []schemas.Trade {
+	e.Lock()
+	defer e.Unlock()
+	e.orders[id] = a
+	trades := e.unmatched[id]
+	delete(e.unmatched, id)
+	return trades
+}
+
+// remove - stopping routing of finished algorithm children
+func (e *Engine) remove(a *Algo) {
+	e.Lock()
+	defer e.Unlock()
+	for id, algo := range e.orders {
+		if algo == a {
+			delete(e.orders, id)
+		}
+	}
+}
+
+func (e *Engine) algo(id string) *Algo {
+	if id == "" {
+		return nil
+	}
+	e.Lock()
+	defer e.Unlock()
+	return e.orders[id]
+}
+
+func (e *Engine) keepUnmatched(t schemas.Trade) {
+	if t.OrderID == "" {
+		return
+	}
+	e.Lock()
+	defer e.Unlock()
+	if len(e.unmatched) >= unmatchedLimit {
+		e.unmatched = make(map[string][]schemas.Trade)
+	}
+	e.unmatched[t.OrderID] = append(e.unmatched[t.OrderID], t)
+}
+
+func validate(order Order) error {
+	side := strings.ToUpper(order.Side)
+	if side != schemas.TypeBuy && side != schemas.TypeSell {
+		return fmt.Errorf("Invalid order side: %s", order.Side)
+	}
+	if order.Amount <= 0 {
+		return fmt.Errorf("Invalid order amount: %v", order.Amount)
+	}
+	if order.Price <= 0 && order.PriceFunc == nil {
+		return errors.New("Order price or price function is required")
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package algo
+
+import (
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+const day = 24 * time.Hour
+
+// volumePoint - traded volume by time
+type volumePoint struct {
+	at     time.Time
+	volume float64
+}
+
+/*
+CandlesProfile - VWAP slices weights by candles volume at same time of day as slices:
+period from start to start + d (up to a day) is split into slices,
+volume of every candle is added to slice its time of day falls in
+*/
+func CandlesProfile(candles []schemas.Candle, start time.Time, d time.Duration, slices int) []float64 {
+	points := make([]volumePoint, len(candles))
+	for i, c := range candles {
+		points[i] = volumePoint{at: schemas.UnixTime(c.Timestamp), volume: c.Volume}
+	}
+	return profile(points, start, d, slices)
+}
+
+// TradesProfile - VWAP slices weights by public trades amounts at same time of day as slices
+func TradesProfile(trades []schemas.Trade, start time.Time, d time.Duration, slices int) []float64 {
+	points := make([]volumePoint, len(trades))
+	for i, t := range trades {
+		points[i] = volumePoint{at: schemas.UnixTime(t.Timestamp), volume: t.Amount}
+	}
+	return profile(points, start, d, slices)
+}
+
+// LoadCandlesProfile - loading candles snapshot of symbol and building profile by it
+func LoadCandlesProfile(provider schemas.CandlesProvider, symbol schemas.Symbol, start time.Time, d time.Duration, slices int) ([]float64, error) {
+	candles, err := provider.Get(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return CandlesProfile(candles, start, d, slices), nil
+}
+
+// LoadTradesProfile - loading public trades snapshot of symbol and building profile by it
+func LoadTradesProfile(provider schemas.TradesProvider, symbol schemas.Symbol, start time.Time, d time.Duration, slices int) ([]float64, error) {
+	trades, err := provider.Get(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return TradesProfile(trades, start, d, slices), nil
+}
+
+/*
+profile - volumes by slices of time of day.
+Slices without history get average volume, so short history doesn't skip them.
+Equal weights are returned if there is no volume at all
+*/
+func profile(points []volumePoint, start time.Time, d time.Duration, slices int) []float64 {
+	if slices <= 0 {
+		return nil
+	}
+	weights := make([]float64, slices)
+	if d > day {
+		d = day
+	}
+	interval := d / time.Duration(slices)
+	if interval <= 0 {
+		interval = 1
+	}
+	startOffset := timeOfDay(start)
+	seen := make([]bool, slices)
+	for _, p := range points {
+		offset := (timeOfDay(p.at) - startOffset + day) % day
+		if offset >= d {
+			continue
+		}
+		i := int(offset / interval)
+		if i >= slices {
+			i = slices - 1
+		}
+		weights[i] += p.volume
+		seen[i] = true
+	}
+
+	var total float64
+	var count int
+	for i, w := range weights {
+		if seen[i] {
+			total += w
+			count++
+		}
+	}
+	for i := range weights {
+		if total == 0 {
+			weights[i] = 1
+			continue
+		}
+		if !seen[i] {
+			weights[i] = total / float64(count)
+		}
+	}
+	return weights
+}
+
+func timeOfDay(t time.Time) time.Duration {
+	t = t.UTC()
+	return t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,250 @@
+/*
+Package backtest runs strategy written against goex API on replayed market data:
+orders are simulated by paper trading provider of every replayed exchange,
+balances are sampled by replay time to build report
+*/
+package backtest
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/syndicatedb/goex"
+	"github.com/syndicatedb/goex/recorder"
+	"github.com/syndicatedb/goex/replay"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+const defaultSampleInterval = time.Minute
+
+// Strategy - tested code. It's started with replayed exchanges before replay,
+// runs in its own goroutines and ends when subscriptions are closed.
+// As fast as possible replay waits for it to receive (or Ack with Replay.Acknowledge) every record
+type Strategy interface {
+	Start(exchanges map[string]goex.API) error
+}
+
+// StrategyFunc - function as Strategy
+type StrategyFunc func(exchanges map[string]goex.API) error
+
+// Start - calling function
+func (f StrategyFunc) Start(exchanges map[string]goex.API) error {
+	return f(exchanges)
+}
+
+// Config - backtest config
+type Config struct {
+	// replay options: speed (as fast as possible by default), period,
+	// symbols with fees and filters, paper trading balances and latency by exchange
+	Replay replay.Options
+	// exchanges strategy gets
+	Exchanges []string
+	// coin report is calculated in, i.e. USDT
+	Currency string
+	// balances sampling interval by replay time, 1 minute by default
+	SampleInterval time.Duration
+}
+
+// Backtester - running strategies on journal
+type Backtester struct {
+	source replay.Source
+	config Config
+}
+
+// New - Backtester constructor, source is read once, so it's one run
+func New(source replay.Source, config Config) *Backtester {
+	if config.SampleInterval <= 0 {
+		config.SampleInterval = defaultSampleInterval
+	}
+	config.Currency = strings.ToUpper(config.Currency)
+	return &Backtester{
+		source: source,
+		config: config,
+	}
+}
+
+/*
+Run - starting strategy and replaying journal to the end.
+Report is built by balances of exchanges and their paper trading orders and trades
+*/
+func (b *Backtester) Run(strategy Strategy) (report Report, err error) {
+	if len(b.config.Exchanges) == 0 {
+		err = errors.New("No exchanges to replay")
+		return
+	}
+	if b.config.Currency == "" {
+		err = errors.New("Report currency is required")
+		return
+	}
+	player := replay.NewPlayer(b.source, b.config.Replay)
+	apis := make(map[string]goex.API)
+	exchanges := make(map[string]*replay.Exchange)
+	for _, name := range b.config.Exchanges {
+		ex := player.Exchange(name)
+		exchanges[name] = ex
+		apis[name] = ex
+	}
+
+	s := newSampler(b.config, exchanges)
+	player.OnRecord(s.onRecord)
+	if err = strategy.Start(apis); err != nil {
+		return
+	}
+	if err = player.Run(); err != nil {
+		return
+	}
+	s.sample(player.Now())
+	return s.report(), nil
+}
+
+// sampler - marking prices by records and sampling equity
+type sampler struct {
+	config    Config
+	exchanges map[string]*replay.Exchange
+	// symbol names as they are in records by exchange and common name
+	names map[string]map[string]string
+	// last trade or quote price by exchange and common name
+	marks map[string]map[string]float64
+
+	start, next time.Time
+	equity      []EquityPoint
+	unpriced    map[string]bool
+}
+
+func newSampler(config Config, exchanges map[string]*replay.Exchange) *sampler {
+	s := &sampler{
+		config:    config,
+		exchanges: exchanges,
+		names:     make(map[string]map[string]string),
+		marks:     make(map[string]map[string]float64),
+	}
+	for name := range exchanges {
+		s.names[name] = make(map[string]string)
+		s.marks[name] = make(map[string]float64)
+	}
+	return s
+}
+
+func (s *sampler) onRecord(rec recorder.Record) {
+	if _, ok := s.exchanges[rec.Exchange]; !ok {
+		return
+	}
+	at := rec.At()
+	if s.start.IsZero() {
+		s.start = at
+	}
+	name := s.commonName(rec.Exchange, rec.Symbol)
+	s.names[rec.Exchange][name] = rec.Symbol
+	switch {
+	case len(rec.Trades) > 0:
+		s.marks[rec.Exchange][name] = rec.Trades[len(rec.Trades)-1].Price
+	case rec.Quote != nil && rec.Quote.Price > 0:
+		s.marks[rec.Exchange][name] = rec.Quote.Price
+	}
+	if !at.Before(s.next) {
+		s.sample(at)
+	}
+}
+
+/*
+sample - adding equity point: total balances of all exchanges in currency.
+Sampling starts when every coin with balance has price
+*/
+func (s *sampler) sample(at time.Time) {
+	equity, unpriced := s.value()
+	s.unpriced = unpriced
+	if len(unpriced) > 0 && len(s.equity) == 0 {
+		return
+	}
+	s.next = at.Add(s.config.SampleInterval)
+	if n := len(s.equity); n > 0 && s.equity[n-1].Time.Equal(at) {
+		s.equity[n-1].Equity = equity
+		return
+	}
+	s.equity = append(s.equity, EquityPoint{
+		Time:   at,
+		Equity: equity,
+	})
+}
+
+// value - total balances in currency and coins without price
+func (s *sampler) value() (equity float64, unpriced map[string]bool) {
+	unpriced = make(map[string]bool)
+	for _, name := range s.config.Exchanges {
+		info, _ := s.exchanges[name].Paper().Info()
+		coins := make([]string, 0, len(info.Balances))
+		for coin := range info.Balances {
+			coins = append(coins, coin)
+		}
+		// sorted, so sum is the same every run
+		sort.Strings(coins)
+		for _, coin := range coins {
+			balance := info.Balances[coin]
+			if balance.Total == 0 {
+				continue
+			}
+			price, ok := s.price(name, coin)
+			if !ok {
+				unpriced[coin] = true
+				continue
+			}
+			equity += balance.Total * price
+		}
+	}
+	return
+}
+
+// price - price of coin in currency: by exchange symbol first, then by other exchanges
+func (s *sampler) price(exchange, coin string) (float64, bool) {
+	if coin == s.config.Currency {
+		return 1, true
+	}
+	if price, ok := s.exchangePrice(exchange, coin); ok {
+		return price, true
+	}
+	for _, name := range s.config.Exchanges {
+		if name == exchange {
+			continue
+		}
+		if price, ok := s.exchangePrice(name, coin); ok {
+			return price, true
+		}
+	}
+	return 0, false
+}
+
+// exchangePrice - middle of book or last price of COIN-CURRENCY symbol, or inverse of CURRENCY-COIN one
+func (s *sampler) exchangePrice(exchange, coin string) (float64, bool) {
+	if price, ok := s.symbolPrice(exchange, coin+"-"+s.config.Currency); ok {
+		return price, true
+	}
+	if price, ok := s.symbolPrice(exchange, s.config.Currency+"-"+coin); ok {
+		return 1 / price, true
+	}
+	return 0, false
+}
+
+func (s *sampler) symbolPrice(exchange, name string) (float64, bool) {
+	original, ok := s.names[exchange][name]
+	if !ok {
+		return 0, false
+	}
+	book, err := s.exchanges[exchange].OrdersProvider().Get(schemas.Symbol{Name: name, OriginalName: original})
+	if err == nil && len(book.Buy) > 0 && len(book.Sell) > 0 {
+		return (book.Buy[0].Price + book.Sell[0].Price) / 2, true
+	}
+	price := s.marks[exchange][name]
+	return price, price > 0
+}
+
+// commonName - configured symbol name by record symbol, record symbol if it's not configured
+func (s *sampler) commonName(exchange, symbol string) string {
+	for _, sym := range s.config.Replay.Symbols[exchange] {
+		if sym.Name == symbol || sym.OriginalName == symbol {
+			return sym.Name
+		}
+	}
+	return symbol
+}
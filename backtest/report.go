@@ -0,0 +1,110 @@
+package backtest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// EquityPoint - total balances in report currency at replay time
+type EquityPoint struct {
+	Time   time.Time
+	Equity float64
+}
+
+// Report - backtest results, amounts are in report currency
+type Report struct {
+	Currency string
+	Start    time.Time // replay time of first record
+	End      time.Time // replay time of last record
+
+	InitialEquity float64 // first equity sample
+	FinalEquity   float64
+	PnL           float64
+	Return        float64 // PnL relative to initial equity
+
+	MaxDrawdown     float64 // biggest equity drop from previous peak
+	MaxDrawdownRate float64 // biggest equity drop relative to previous peak
+
+	// traded notional and fees, converted by last prices
+	Turnover float64
+	Fees     float64
+
+	Orders        int // created orders
+	FilledOrders  int // orders filled at least partially
+	Trades        int
+	OrderedAmount float64 // base coin amount of created orders
+	FilledAmount  float64
+	FillRatio     float64 // filled amount relative to ordered amount
+
+	Equity []EquityPoint
+	// coins without price in report currency, they are not counted in equity
+	Unpriced []string
+}
+
+// report - results by equity samples, orders history and trades of paper trading providers
+func (s *sampler) report() Report {
+	r := Report{
+		Currency: s.config.Currency,
+		Start:    s.start,
+		Equity:   s.equity,
+	}
+	if n := len(s.equity); n > 0 {
+		r.End = s.equity[n-1].Time
+		r.InitialEquity = s.equity[0].Equity
+		r.FinalEquity = s.equity[n-1].Equity
+		r.PnL = r.FinalEquity - r.InitialEquity
+		if r.InitialEquity != 0 {
+			r.Return = r.PnL / r.InitialEquity
+		}
+	}
+	r.MaxDrawdown, r.MaxDrawdownRate = drawdown(s.equity)
+
+	for _, name := range s.config.Exchanges {
+		paper := s.exchanges[name].Paper()
+		for _, o := range paper.History() {
+			r.Orders++
+			r.OrderedAmount += o.Amount
+			r.FilledAmount += o.AmountFilled
+			if o.AmountFilled > 0 {
+				r.FilledOrders++
+			}
+		}
+		trades, _, _ := paper.Trades(schemas.FilterOptions{})
+		for _, t := range trades {
+			r.Trades++
+			// fees are charged in quote coin
+			price, _ := s.price(name, schemas.QuoteCoinOf(t.Symbol))
+			r.Turnover += t.Amount * t.Price * price
+			r.Fees += t.Fee * price
+		}
+	}
+	if r.OrderedAmount > 0 {
+		r.FillRatio = r.FilledAmount / r.OrderedAmount
+	}
+	for coin := range s.unpriced {
+		r.Unpriced = append(r.Unpriced, coin)
+	}
+	sort.Strings(r.Unpriced)
+	return r
+}
+
+// drawdown - biggest drop of equity from its previous peak, absolute and relative to peak
+func drawdown(equity []EquityPoint) (max, rate float64) {
+	var peak float64
+	for i, p := range equity {
+		if i == 0 || p.Equity > peak {
+			peak = p.Equity
+			continue
+		}
+		drop := peak - p.Equity
+		if drop > max {
+			max = drop
+		}
+		if peak > 0 && drop/peak > rate {
+			rate = drop / peak
+		}
+	}
+	return
+}
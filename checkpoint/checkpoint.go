@@ -0,0 +1,119 @@
+/*
+Package checkpoint stores trades import checkpoints, see schemas.Checkpoints.
+File store keeps checkpoints in JSON file which is rewritten on every change
+*/
+package checkpoint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// Memory - checkpoints of current process
+type Memory struct {
+	checkpoints map[string]schemas.Checkpoint
+
+	sync.Mutex
+}
+
+// NewMemory - Memory constructor
+func NewMemory() *Memory {
+	return &Memory{
+		checkpoints: make(map[string]schemas.Checkpoint),
+	}
+}
+
+// Get - checkpoint by key
+func (m *Memory) Get(key string) (c schemas.Checkpoint, ok bool, err error) {
+	m.Lock()
+	defer m.Unlock()
+	c, ok = m.checkpoints[key]
+	return
+}
+
+// Set - saving checkpoint
+func (m *Memory) Set(key string, c schemas.Checkpoint) error {
+	m.Lock()
+	defer m.Unlock()
+	m.checkpoints[key] = c
+	return nil
+}
+
+// File - checkpoints saved to file
+type File struct {
+	path string
+	mem  *Memory
+}
+
+// Open - loading checkpoints file, it's created on first Set if it doesn't exist
+func Open(path string) (*File, error) {
+	f := &File{
+		path: path,
+		mem:  NewMemory(),
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > 0 {
+		if err = json.Unmarshal(b, &f.mem.checkpoints); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Get - checkpoint by key
+func (f *File) Get(key string) (schemas.Checkpoint, bool, error) {
+	return f.mem.Get(key)
+}
+
+// Set - saving checkpoint. File is replaced by renaming, so it's never written partially
+func (f *File) Set(key string, c schemas.Checkpoint) error {
+	f.mem.Lock()
+	defer f.mem.Unlock()
+	prev, existed := f.mem.checkpoints[key]
+	f.mem.checkpoints[key] = c
+	if err := f.save(); err != nil {
+		if existed {
+			f.mem.checkpoints[key] = prev
+		} else {
+			delete(f.mem.checkpoints, key)
+		}
+		return err
+	}
+	return nil
+}
+
+func (f *File) save() error {
+	b, err := json.MarshalIndent(f.mem.checkpoints, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(b); err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = os.Rename(tmp.Name(), f.path); err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
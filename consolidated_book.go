@@ -0,0 +1,278 @@
+package goex
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// BookLevel - price level of one exchange
+type BookLevel struct {
+	Exchange string  `json:"exchange"`
+	Price    float64 `json:"price"`
+	Amount   float64 `json:"amount"`
+	Fee      float64 `json:"fee"` // exchange symbol fee rate
+	// price after fee: lower for bids (seller gets less), higher for asks (buyer pays more)
+	EffectivePrice float64 `json:"effectivePrice"`
+}
+
+// IsEmpty - level is not set, i.e. there are no bids on any exchange
+func (l BookLevel) IsEmpty() bool {
+	return l.Exchange == ""
+}
+
+// ConsolidatedOrderBook - levels of all exchanges, best effective price first
+type ConsolidatedOrderBook struct {
+	Symbol string      `json:"symbol"`
+	Buy    []BookLevel `json:"buy"`
+	Sell   []BookLevel `json:"sell"`
+}
+
+// BestPrice - best bid and offer across exchanges by effective price
+type BestPrice struct {
+	Symbol string    `json:"symbol"`
+	Bid    BookLevel `json:"bid"`
+	Ask    BookLevel `json:"ask"`
+}
+
+// Spread - effective ask minus effective bid, 0 if any side is empty
+func (bp BestPrice) Spread() float64 {
+	if bp.Bid.IsEmpty() || bp.Ask.IsEmpty() {
+		return 0
+	}
+	return bp.Ask.EffectivePrice - bp.Bid.EffectivePrice
+}
+
+// Crossed - best bid is higher than best ask after fees, i.e. buying on one exchange
+// and selling on another is profitable
+func (bp BestPrice) Crossed() bool {
+	return !bp.Bid.IsEmpty() && !bp.Ask.IsEmpty() && bp.Bid.EffectivePrice > bp.Ask.EffectivePrice
+}
+
+// BestPriceChannel - best price changes, exchange is set for errors
+type BestPriceChannel struct {
+	Exchange string
+	Data     BestPrice
+	Error    error
+}
+
+// venueBook - order book of one exchange with its best levels
+type venueBook struct {
+	buy     map[float64]float64
+	sell    map[float64]float64
+	bestBid BookLevel
+	bestAsk BookLevel
+}
+
+/*
+ConsolidatedBook - order books of several exchanges merged by common symbol name.
+Levels keep exchange and are compared by price after symbol fee (schemas.Symbol.Fee)
+*/
+type ConsolidatedBook struct {
+	manager *Manager
+	fees    map[string]map[string]float64 // fee rate by exchange and common symbol
+	books   map[string]map[string]*venueBook
+	best    map[string]BestPrice
+
+	sync.RWMutex
+}
+
+// NewConsolidatedBook - ConsolidatedBook constructor, fees are taken from manager symbols
+func NewConsolidatedBook(manager *Manager) *ConsolidatedBook {
+	cb := &ConsolidatedBook{
+		manager: manager,
+		fees:    make(map[string]map[string]float64),
+		books:   make(map[string]map[string]*venueBook),
+		best:    make(map[string]BestPrice),
+	}
+	for _, exchange := range manager.Exchanges() {
+		for _, s := range manager.Symbols(exchange) {
+			cb.SetFee(exchange, manager.CommonName(exchange, s.Name), s.Fee)
+		}
+	}
+	return cb
+}
+
+/*
+Subscribe - subscribing to order books of symbols (common names) on every exchange listing them,
+all symbols if empty. Best price is sent every time it changes
+*/
+func (cb *ConsolidatedBook) Subscribe(symbols []string, d time.Duration) chan BestPriceChannel {
+	books := cb.manager.SubscribeBooks(symbols, d)
+	ch := make(chan BestPriceChannel, cap(books))
+	go func() {
+		for msg := range books {
+			if msg.Error != nil {
+				ch <- BestPriceChannel{
+					Exchange: msg.Exchange,
+					Error:    msg.Error,
+				}
+			}
+			for _, bp := range cb.Apply(msg.Exchange, msg.ResultChannel) {
+				ch <- BestPriceChannel{
+					Data: bp,
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// SetFee - setting fee rate of exchange symbol (common name)
+func (cb *ConsolidatedBook) SetFee(exchange, symbol string, fee float64) {
+	cb.Lock()
+	defer cb.Unlock()
+	if cb.fees[exchange] == nil {
+		cb.fees[exchange] = make(map[string]float64)
+	}
+	cb.fees[exchange][symbol] = fee
+}
+
+/*
+Apply - applying order book message of exchange: snapshot replaces exchange book,
+update changes levels, levels with zero amount or Remove flag are removed.
+Book symbols have to be common names. Returns best prices that have changed
+*/
+func (cb *ConsolidatedBook) Apply(exchange string, msg schemas.ResultChannel) (changed []BestPrice) {
+	var books []schemas.OrderBook
+	switch v := msg.Data.(type) {
+	case schemas.OrderBook:
+		books = []schemas.OrderBook{v}
+	case []schemas.OrderBook:
+		books = v
+	default:
+		return
+	}
+
+	cb.Lock()
+	defer cb.Unlock()
+	for _, book := range books {
+		if book.Symbol == "" {
+			continue
+		}
+		venue := cb.venue(exchange, book.Symbol, msg.DataType == schemas.DataTypeSnapshot)
+		schemas.ApplyLevels(venue.buy, book.Buy)
+		schemas.ApplyLevels(venue.sell, book.Sell)
+		cb.updateVenueBest(exchange, book.Symbol, venue)
+		if bp, ok := cb.updateBest(book.Symbol); ok {
+			changed = append(changed, bp)
+		}
+	}
+	return
+}
+
+// Reset - removing books of exchange, i.e. when it's disconnected. Returns best prices that have changed
+func (cb *ConsolidatedBook) Reset(exchange string) (changed []BestPrice) {
+	cb.Lock()
+	defer cb.Unlock()
+	for symbol, venues := range cb.books {
+		if _, ok := venues[exchange]; !ok {
+			continue
+		}
+		delete(venues, exchange)
+		if bp, ok := cb.updateBest(symbol); ok {
+			changed = append(changed, bp)
+		}
+	}
+	return
+}
+
+// Best - best bid and offer of symbol (common name)
+func (cb *ConsolidatedBook) Best(symbol string) (bp BestPrice, ok bool) {
+	cb.RLock()
+	defer cb.RUnlock()
+	bp, ok = cb.best[symbol]
+	return
+}
+
+// Book - all levels of symbol (common name) by effective price: highest bids and lowest asks first
+func (cb *ConsolidatedBook) Book(symbol string) ConsolidatedOrderBook {
+	cb.RLock()
+	defer cb.RUnlock()
+	book := ConsolidatedOrderBook{
+		Symbol: symbol,
+	}
+	for exchange, venue := range cb.books[symbol] {
+		fee := cb.fees[exchange][symbol]
+		for price, amount := range venue.buy {
+			book.Buy = append(book.Buy, newBookLevel(exchange, price, amount, fee, true))
+		}
+		for price, amount := range venue.sell {
+			book.Sell = append(book.Sell, newBookLevel(exchange, price, amount, fee, false))
+		}
+	}
+	sort.Slice(book.Buy, func(i, j int) bool {
+		return book.Buy[i].EffectivePrice > book.Buy[j].EffectivePrice
+	})
+	sort.Slice(book.Sell, func(i, j int) bool {
+		return book.Sell[i].EffectivePrice < book.Sell[j].EffectivePrice
+	})
+	return book
+}
+
+// venue - exchange book of symbol, created empty if it's missing or reset is requested
+func (cb *ConsolidatedBook) venue(exchange, symbol string, reset bool) *venueBook {
+	if cb.books[symbol] == nil {
+		cb.books[symbol] = make(map[string]*venueBook)
+	}
+	venue, ok := cb.books[symbol][exchange]
+	if !ok || reset {
+		venue = &venueBook{
+			buy:  make(map[float64]float64),
+			sell: make(map[float64]float64),
+		}
+		cb.books[symbol][exchange] = venue
+	}
+	return venue
+}
+
+func (cb *ConsolidatedBook) updateVenueBest(exchange, symbol string, venue *venueBook) {
+	fee := cb.fees[exchange][symbol]
+	venue.bestBid, venue.bestAsk = BookLevel{}, BookLevel{}
+	for price, amount := range venue.buy {
+		if venue.bestBid.IsEmpty() || price > venue.bestBid.Price {
+			venue.bestBid = newBookLevel(exchange, price, amount, fee, true)
+		}
+	}
+	for price, amount := range venue.sell {
+		if venue.bestAsk.IsEmpty() || price < venue.bestAsk.Price {
+			venue.bestAsk = newBookLevel(exchange, price, amount, fee, false)
+		}
+	}
+}
+
+// updateBest - choosing best levels of exchanges, returns true if they have changed
+func (cb *ConsolidatedBook) updateBest(symbol string) (BestPrice, bool) {
+	bp := BestPrice{
+		Symbol: symbol,
+	}
+	for _, venue := range cb.books[symbol] {
+		if b := venue.bestBid; !b.IsEmpty() && (bp.Bid.IsEmpty() || b.EffectivePrice > bp.Bid.EffectivePrice) {
+			bp.Bid = b
+		}
+		if a := venue.bestAsk; !a.IsEmpty() && (bp.Ask.IsEmpty() || a.EffectivePrice < bp.Ask.EffectivePrice) {
+			bp.Ask = a
+		}
+	}
+	if prev, ok := cb.best[symbol]; ok && prev == bp {
+		return bp, false
+	}
+	cb.best[symbol] = bp
+	return bp, true
+}
+
+func newBookLevel(exchange string, price, amount, fee float64, bid bool) BookLevel {
+	effective := price * (1 + fee)
+	if bid {
+		effective = price * (1 - fee)
+	}
+	return BookLevel{
+		Exchange:       exchange,
+		Price:          price,
+		Amount:         amount,
+		Fee:            fee,
+		EffectivePrice: effective,
+	}
+}
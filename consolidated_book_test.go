@@ -0,0 +1,117 @@
+package goex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+func bookMsg(dataType string, buy, sell [][2]float64) schemas.ResultChannel {
+	book := schemas.OrderBook{Symbol: "ETH-BTC"}
+	for _, l := range buy {
+		book.Buy = append(book.Buy, schemas.Order{Symbol: "ETH-BTC", Price: l[0], Amount: l[1]})
+	}
+	for _, l := range sell {
+		book.Sell = append(book.Sell, schemas.Order{Symbol: "ETH-BTC", Price: l[0], Amount: l[1]})
+	}
+	return schemas.ResultChannel{DataType: dataType, Data: book}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestConsolidatedBookFees(t *testing.T) {
+	manager, err := NewManager(ManagerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb := NewConsolidatedBook(manager)
+	cb.SetFee("a", "ETH-BTC", 0.001)
+	cb.SetFee("b", "ETH-BTC", 0.002)
+
+	tests := []struct {
+		name     string
+		exchange string
+		msg      schemas.ResultChannel
+		bid, ask string // best exchanges
+		bidPrice float64
+		askPrice float64
+		crossed  bool
+	}{
+		{
+			name:     "one exchange",
+			exchange: "a",
+			msg:      bookMsg(schemas.DataTypeSnapshot, [][2]float64{{100, 1}, {99, 2}}, [][2]float64{{101, 1}}),
+			bid:      "a", ask: "a",
+			bidPrice: 100 * 0.999, askPrice: 101 * 1.001,
+		},
+		{
+			// higher raw bid loses after higher fee: 100.05 * 0.998 < 100 * 0.999
+			name:     "higher fee makes better raw price worse",
+			exchange: "b",
+			msg:      bookMsg(schemas.DataTypeSnapshot, [][2]float64{{100.05, 1}}, [][2]float64{{100.95, 1}}),
+			bid:      "a", ask: "a",
+			bidPrice: 100 * 0.999, askPrice: 101 * 1.001,
+		},
+		{
+			name:     "better price after fee wins",
+			exchange: "b",
+			msg:      bookMsg(schemas.DataTypeUpdate, [][2]float64{{100.2, 1}}, [][2]float64{{100.5, 1}}),
+			bid:      "b", ask: "b",
+			bidPrice: 100.2 * 0.998, askPrice: 100.5 * 1.002,
+		},
+		{
+			// remaining levels of b are worse after fee
+			name:     "removed levels",
+			exchange: "b",
+			msg:      bookMsg(schemas.DataTypeUpdate, [][2]float64{{100.2, 0}}, [][2]float64{{100.5, 0}}),
+			bid:      "a", ask: "a",
+			bidPrice: 100 * 0.999, askPrice: 101 * 1.001,
+		},
+		{
+			name:     "crossed after fees",
+			exchange: "b",
+			msg:      bookMsg(schemas.DataTypeSnapshot, nil, [][2]float64{{99, 1}}),
+			bid:      "a", ask: "b",
+			bidPrice: 100 * 0.999, askPrice: 99 * 1.002,
+			crossed: true,
+		},
+	}
+	for _, tt := range tests {
+		cb.Apply(tt.exchange, tt.msg)
+		bp, ok := cb.Best("ETH-BTC")
+		if !ok {
+			t.Fatalf("%s: no best price", tt.name)
+		}
+		if bp.Bid.Exchange != tt.bid || !almostEqual(bp.Bid.EffectivePrice, tt.bidPrice) {
+			t.Errorf("%s: bid = %s %v, want %s %v", tt.name, bp.Bid.Exchange, bp.Bid.EffectivePrice, tt.bid, tt.bidPrice)
+		}
+		if bp.Ask.Exchange != tt.ask || !almostEqual(bp.Ask.EffectivePrice, tt.askPrice) {
+			t.Errorf("%s: ask = %s %v, want %s %v", tt.name, bp.Ask.Exchange, bp.Ask.EffectivePrice, tt.ask, tt.askPrice)
+		}
+		if bp.Crossed() != tt.crossed {
+			t.Errorf("%s: crossed = %v, want %v", tt.name, bp.Crossed(), tt.crossed)
+		}
+		if !almostEqual(bp.Spread(), tt.askPrice-tt.bidPrice) {
+			t.Errorf("%s: spread = %v, want %v", tt.name, bp.Spread(), tt.askPrice-tt.bidPrice)
+		}
+	}
+
+	book := cb.Book("ETH-BTC")
+	if len(book.Buy) != 2 || book.Buy[0].Price != 100 || book.Buy[1].Price != 99 {
+		t.Errorf("book bids = %+v", book.Buy)
+	}
+	if len(book.Sell) != 2 || book.Sell[0].Exchange != "b" || book.Sell[1].Exchange != "a" {
+		t.Errorf("book asks = %+v", book.Sell)
+	}
+
+	changed := cb.Reset("b")
+	if len(changed) != 1 || changed[0].Ask.Exchange != "a" {
+		t.Errorf("best after reset = %+v", changed)
+	}
+	if changed := cb.Apply("a", bookMsg(schemas.DataTypeUpdate, [][2]float64{{98, 1}}, nil)); len(changed) != 0 {
+		t.Errorf("best changed by level below best: %+v", changed)
+	}
+}
@@ -1,10 +1,14 @@
 package goex
 
 import (
+	"log"
+	"time"
+
 	"github.com/syndicatedb/goex/exchanges/binance"
 	"github.com/syndicatedb/goex/exchanges/bitfinex"
 	"github.com/syndicatedb/goex/exchanges/idax"
 	"github.com/syndicatedb/goex/exchanges/kucoin"
+	"github.com/syndicatedb/goex/exchanges/paper"
 	"github.com/syndicatedb/goex/exchanges/poloniex"
 	"github.com/syndicatedb/goex/exchanges/tidex"
 
@@ -31,8 +35,16 @@ type API interface {
 	CandlesProvider() schemas.CandlesProvider
 }
 
-// New - exchange constructor
+// New - exchange constructor, TradingProvider is simulated if paper options are set
 func New(opts schemas.Options) API {
+	api := newExchange(opts)
+	if api == nil || opts.Paper == nil {
+		return api
+	}
+	return newPaperExchange(api, *opts.Paper)
+}
+
+func newExchange(opts schemas.Options) API {
 	if opts.Name == Tidex {
 		return tidex.New(opts)
 	}
@@ -53,3 +65,51 @@ func New(opts schemas.Options) API {
 	}
 	return nil
 }
+
+// paperExchange - exchange with paper TradingProvider filled by its market data
+type paperExchange struct {
+	API
+	trading *paper.TradingProvider
+}
+
+func newPaperExchange(api API, opts schemas.PaperOptions) API {
+	symbols, err := api.SymbolProvider().Get()
+	if err != nil {
+		log.Println("Error getting symbols", err)
+	}
+	feed := paper.NewLiveFeed(api.OrdersProvider(), api.TradesProvider())
+	return &paperExchange{
+		API:     api,
+		trading: paper.NewTradingProvider(opts, feed).SetSymbols(symbols),
+	}
+}
+
+// TradingProvider - paper trading provider
+func (pe *paperExchange) TradingProvider() schemas.TradingProvider {
+	return pe.trading
+}
+
+// FollowSymbols - subscribing to exchange symbols changes and applying them
+// to order book, quotes and trades subscriptions: new listings are subscribed,
+// delisted and halted symbols are removed. Snapshot (first load) is skipped,
+// providers keep symbols set with SetSymbols
+func FollowSymbols(api API, d time.Duration) {
+	events := api.SymbolProvider().SubscribeChanges(d)
+	go func() {
+		for msg := range events {
+			if msg.Error != nil {
+				log.Println("Error loading symbols changes:", msg.Error)
+				continue
+			}
+			if msg.DataType == schemas.DataTypeSnapshot {
+				continue
+			}
+			schemas.ApplySymbolEvents(
+				msg.Data,
+				api.OrdersProvider(),
+				api.QuotesProvider(),
+				api.TradesProvider(),
+			)
+		}
+	}()
+}
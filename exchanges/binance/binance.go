@@ -28,8 +28,9 @@ const (
 
 	apiCreateOrder = "https://api.binance.com/api/v3/order"
 	apiCancelOrder = "https://api.binance.com/api/v3/order"
+	apiCancelAll   = "https://api.binance.com/api/v3/openOrders"
 
-	wsURL = "wss://stream.binance.com:9443/stream?streams="
+	wsURL = "wss://stream.binance.com:9443/stream"
 )
 
 const (
@@ -37,6 +38,17 @@ const (
 	orderBookSymbolsLimit = 100
 	tradesSymbolsLimit    = 10
 	quotesSymbolsLimit    = 10
+
+	// binance limit of streams by one connection
+	streamsPerConnection = 1024
+	// buffer of group messages, groups symbols are changing at runtime
+	streamBufferSize = 200
+)
+
+// Binance default (VIP 0) fees, exchangeInfo doesn't contain fees
+const (
+	defaultMakerFee = 0.001
+	defaultTakerFee = 0.001
 )
 
 const (
@@ -94,6 +106,31 @@ func unparseSymbol(s string) (symbol string) {
 	return strings.Replace(s, "-", "", 1)
 }
 
+// removeSymbols - splitting list into kept symbols and removed ones by name
+func removeSymbols(list, symbols []schemas.Symbol) (kept, removed []schemas.Symbol) {
+	names := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		names[s.Name] = true
+	}
+	for _, s := range list {
+		if names[s.Name] {
+			removed = append(removed, s)
+			continue
+		}
+		kept = append(kept, s)
+	}
+	return
+}
+
+func hasSymbol(list []schemas.Symbol, symbol schemas.Symbol) bool {
+	for _, s := range list {
+		if s.Name == symbol.Name {
+			return true
+		}
+	}
+	return false
+}
+
 // sign - signing request
 func sign(key, secret string, req *http.Request) *http.Request {
 	req.Header.Set("X-MBX-APIKEY", key)
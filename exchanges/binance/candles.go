@@ -14,6 +14,7 @@ type CandlesProvider struct {
 	httpProxy proxy.Provider
 	symbols   []schemas.Symbol
 	groups    []*CandlesGroup
+	resultCh  chan schemas.ResultChannel
 
 	sync.Mutex
 }
@@ -25,27 +26,48 @@ func NewCandlesProvider(httpProxy proxy.Provider) *CandlesProvider {
 	}
 }
 
-// SetSymbols - stub method for binance candles provider
+// SetSymbols - replacing symbols and creating groups by symbols chunks.
+// Symbols of running subscription are changed by AddSymbols and RemoveSymbols
 func (cp *CandlesProvider) SetSymbols(symbols []schemas.Symbol) schemas.CandlesProvider {
+	cp.Lock()
+	defer cp.Unlock()
+	cp.symbols = append([]schemas.Symbol(nil), symbols...)
+	cp.groups = nil
+	cp.addToGroups(symbols)
+	return cp
+}
+
+// addToGroups - adding symbols to groups having room, new groups are created for the rest.
+// Returns created groups
+func (cp *CandlesProvider) addToGroups(symbols []schemas.Symbol) (created []*CandlesGroup) {
 	slice := make([]schemas.Symbol, len(symbols))
 	copy(slice, symbols)
 	capacity := orderBookSymbolsLimit
-	for {
-		if len(slice) <= capacity {
-			cp.groups = append(
-				cp.groups,
-				NewCandlesGroup(slice, cp.httpProxy),
-			)
-			break
+	for _, group := range cp.groups {
+		if len(slice) == 0 {
+			return
 		}
-		cp.groups = append(
-			cp.groups,
-			NewCandlesGroup(slice[0:capacity], cp.httpProxy),
-		)
-
-		slice = slice[capacity:]
+		room := capacity - group.Len()
+		if room <= 0 {
+			continue
+		}
+		if room > len(slice) {
+			room = len(slice)
+		}
+		group.AddSymbols(slice[0:room])
+		slice = slice[room:]
 	}
-	return cp
+	for len(slice) > 0 {
+		size := capacity
+		if size > len(slice) {
+			size = len(slice)
+		}
+		group := NewCandlesGroup(slice[0:size], cp.httpProxy)
+		cp.groups = append(cp.groups, group)
+		created = append(created, group)
+		slice = slice[size:]
+	}
+	return
 }
 
 // Get - stub method for binance candles provider
@@ -72,8 +94,11 @@ func (cp *CandlesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) cha
 
 // SubscribeAll - stub method for binance candles provider
 func (cp *CandlesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	cp.Lock()
+	defer cp.Unlock()
 	bufLength := len(cp.symbols)
 	ch := make(chan schemas.ResultChannel, 2*bufLength)
+	cp.resultCh = ch
 
 	for _, group := range cp.groups {
 		go group.Start(ch)
@@ -81,3 +106,37 @@ func (cp *CandlesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChan
 	}
 	return ch
 }
+
+// AddSymbols - subscribing to candles of new symbols on connected groups,
+// new connections are opened only when groups are full
+func (cp *CandlesProvider) AddSymbols(symbols []schemas.Symbol) {
+	cp.Lock()
+	defer cp.Unlock()
+	var fresh []schemas.Symbol
+	for _, s := range symbols {
+		if !hasSymbol(cp.symbols, s) {
+			fresh = append(fresh, s)
+		}
+	}
+	if len(fresh) == 0 {
+		return
+	}
+	cp.symbols = append(cp.symbols, fresh...)
+	created := cp.addToGroups(fresh)
+	if cp.resultCh == nil {
+		return
+	}
+	for _, group := range created {
+		go group.Start(cp.resultCh)
+	}
+}
+
+// RemoveSymbols - unsubscribing from candles of symbols without reconnect
+func (cp *CandlesProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	cp.Lock()
+	defer cp.Unlock()
+	cp.symbols, _ = removeSymbols(cp.symbols, symbols)
+	for _, group := range cp.groups {
+		group.RemoveSymbols(symbols)
+	}
+}
@@ -5,10 +5,10 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
-	"github.com/syndicatedb/goex/internal/websocket"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -17,14 +17,14 @@ import (
 type CandlesGroup struct {
 	symbols []schemas.Symbol
 
-	wsClient   *websocket.Client
+	stream     *stream
 	httpClient *httpclient.Client
 	httpProxy  proxy.Provider
 
-	dataCh  chan []byte
-	errorCh chan error
+	dataCh chan []byte
 
 	resultCh chan schemas.ResultChannel
+	sync.Mutex
 }
 
 /*
@@ -63,21 +63,26 @@ type klinesStream struct {
 func NewCandlesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *CandlesGroup {
 	proxyClient := httpProxy.NewClient(exchangeName)
 
-	return &CandlesGroup{
-		symbols:    symbols,
+	cg := &CandlesGroup{
 		httpProxy:  httpProxy,
 		httpClient: httpclient.New(proxyClient),
-		dataCh:     make(chan []byte, 2*len(symbols)),
-		errorCh:    make(chan error, 2*len(symbols)),
+		dataCh:     make(chan []byte, streamBufferSize),
 	}
+	cg.stream = newStream(cg.dataCh, httpProxy)
+	cg.AddSymbols(symbols)
+	return cg
 }
 
-// Get - loading candles snapshot by symbol
+// Get - loading candles snapshot by group symbols
 func (cg *CandlesGroup) Get() (candles [][]schemas.Candle, err error) {
+	return cg.get(cg.getSymbols())
+}
+
+func (cg *CandlesGroup) get(symbols []schemas.Symbol) (candles [][]schemas.Candle, err error) {
 	var b []byte
 	var resp []interface{}
 
-	for _, symbol := range cg.symbols {
+	for _, symbol := range symbols {
 		url := apiKlines + "?" + "symbol=" + strings.ToUpper(symbol.OriginalName) + "&interval=1m&limit=400"
 
 		if b, err = cg.httpClient.Get(url, httpclient.Params(), false); err != nil {
@@ -98,48 +103,87 @@ func (cg *CandlesGroup) Get() (candles [][]schemas.Candle, err error) {
 	return
 }
 
-// Start - starting updates
+// Start - starting updates, snapshots are sent after every connection and each 5 minutes
 func (cg *CandlesGroup) Start(ch chan schemas.ResultChannel) {
-	log.Println("[BINANCE] Orderbook starting")
+	log.Println("[BINANCE] Candles starting")
+	cg.Lock()
 	cg.resultCh = ch
+	cg.Unlock()
 
+	cg.stream.onConnect = func() {
+		go cg.publishSnapshot(cg.getSymbols())
+	}
+	cg.stream.onError = func(err error) {
+		cg.resultCh <- schemas.ResultChannel{
+			Error: err,
+		}
+	}
 	go func() {
 		for {
-			result, err := cg.Get()
-			cg.resultCh <- schemas.ResultChannel{
-				DataType: "s",
-				Data:     result,
-				Error:    err,
-			}
 			time.Sleep(5 * time.Minute)
+			cg.publishSnapshot(cg.getSymbols())
 		}
 	}()
 	cg.listen()
-	cg.connect()
+	cg.stream.Start()
+}
+
+// Len - count of group symbols
+func (cg *CandlesGroup) Len() int {
+	cg.Lock()
+	defer cg.Unlock()
+	return len(cg.symbols)
+}
+
+// AddSymbols - subscribing to symbols klines without reconnect, snapshots are sent for running group
+func (cg *CandlesGroup) AddSymbols(symbols []schemas.Symbol) {
+	cg.Lock()
+	cg.symbols = append(cg.symbols, symbols...)
+	running := cg.resultCh != nil
+	cg.Unlock()
+
+	if err := cg.stream.Subscribe(cg.streams(symbols)); err != nil {
+		log.Println("[BINANCE] Error subscribing to candles: ", err)
+	}
+	if running {
+		go cg.publishSnapshot(symbols)
+	}
 }
 
-func (cg *CandlesGroup) restart() {
-	time.Sleep(5 * time.Second)
-	if err := cg.wsClient.Exit(); err != nil {
-		log.Println("[BINANCE] Error destroying connection: ", err)
+// RemoveSymbols - unsubscribing from symbols klines, unknown symbols are skipped
+func (cg *CandlesGroup) RemoveSymbols(symbols []schemas.Symbol) {
+	var removed []schemas.Symbol
+	cg.Lock()
+	cg.symbols, removed = removeSymbols(cg.symbols, symbols)
+	cg.Unlock()
+
+	if err := cg.stream.Unsubscribe(cg.streams(removed)); err != nil {
+		log.Println("[BINANCE] Error unsubscribing from candles: ", err)
 	}
-	cg.Start(cg.resultCh)
 }
 
-// connect - creating new WS client and establishing connection
-func (cg *CandlesGroup) connect() {
-	var smbls []string
-	for _, s := range cg.symbols {
-		smbls = append(smbls, s.OriginalName)
+func (cg *CandlesGroup) streams(symbols []schemas.Symbol) (names []string) {
+	for _, s := range symbols {
+		names = append(names, strings.ToLower(s.OriginalName)+"@kline_1m")
 	}
+	return
+}
 
-	ws := websocket.NewClient(wsURL+strings.ToLower(strings.Join(smbls, "@kline_1m/")+"@kline_1m"), cg.httpProxy)
-	cg.wsClient = ws
-	if err := cg.wsClient.Connect(); err != nil {
-		log.Println("[BINANCE] Error connecting to binance API: ", err)
-		cg.restart()
+func (cg *CandlesGroup) publishSnapshot(symbols []schemas.Symbol) {
+	result, err := cg.get(symbols)
+	cg.resultCh <- schemas.ResultChannel{
+		DataType: "s",
+		Data:     result,
+		Error:    err,
 	}
-	cg.wsClient.Listen(cg.dataCh, cg.errorCh)
+}
+
+func (cg *CandlesGroup) getSymbols() []schemas.Symbol {
+	cg.Lock()
+	defer cg.Unlock()
+	symbols := make([]schemas.Symbol, len(cg.symbols))
+	copy(symbols, cg.symbols)
+	return symbols
 }
 
 // listen - listening to updates from WS
@@ -155,15 +199,6 @@ func (cg *CandlesGroup) listen() {
 			}
 		}
 	}()
-	go func() {
-		for err := range cg.errorCh {
-			cg.resultCh <- schemas.ResultChannel{
-				Error: err,
-			}
-			log.Println("[BINANCE] Error listening:", err)
-			cg.restart()
-		}
-	}()
 }
 
 func (cg *CandlesGroup) handleUpdates(b []byte) (candles []schemas.Candle, dataType string) {
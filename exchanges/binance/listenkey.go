@@ -4,15 +4,31 @@ import (
 	httpclient "github.com/syndicatedb/goex/internal/http"
 
 	"encoding/json"
+	"errors"
 	"log"
+	"time"
+
+	"github.com/syndicatedb/goex/internal/websocket"
+	"github.com/syndicatedb/goex/schemas"
 )
 
 const url = "https://api.binance.com/api/v1/userDataStream"
 
+const (
+	// listen key is valid for 60 minutes without keepalive
+	listenKeyRefresh = 30 * time.Minute
+	// binance closes user data connection after 24 hours, reconnecting before it
+	userStreamLifetime = 23 * time.Hour
+	userStreamRetry    = 5 * time.Second
+
+	listenKeyExpiredType = "listenKeyExpired"
+)
+
 type response struct {
 	ListenKey string `json:"listenKey"`
 }
 
+// CreateListenkey - creating user data stream key and keeping it for Ping and Delete
 func (trading *TradingProvider) CreateListenkey(apiKey string) (string, error) {
 	var resp response
 	params := httpclient.Params()
@@ -27,12 +43,26 @@ func (trading *TradingProvider) CreateListenkey(apiKey string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if resp.ListenKey == "" {
+		return "", errors.New("[BINANCE] Empty listen key: " + string(b))
+	}
+	trading.setListenKey(resp.ListenKey)
 	return resp.ListenKey, nil
 }
 
+// Ping - extending listen key validity for 60 minutes
 func (trading *TradingProvider) Ping() error {
+	return trading.pingKey(trading.getListenKey())
+}
+
+// Delete - closing user data stream
+func (trading *TradingProvider) Delete() error {
+	return trading.deleteKey(trading.getListenKey())
+}
+
+func (trading *TradingProvider) pingKey(key string) error {
 	params := httpclient.Params()
-	params.Set("listenKey", trading.listenKey)
+	params.Set("listenKey", key)
 
 	_, err := trading.httpClient.Request("PUT", url, params, httpclient.KeyValue{}, true)
 	if err != nil {
@@ -41,9 +71,9 @@ func (trading *TradingProvider) Ping() error {
 	return nil
 }
 
-func (trading *TradingProvider) Delete() error {
+func (trading *TradingProvider) deleteKey(key string) error {
 	params := httpclient.Params()
-	params.Set("listenKey", trading.listenKey)
+	params.Set("listenKey", key)
 
 	_, err := trading.httpClient.Request("DELETE", url, params, httpclient.KeyValue{}, true)
 	if err != nil {
@@ -51,3 +81,157 @@ func (trading *TradingProvider) Delete() error {
 	}
 	return nil
 }
+
+func (trading *TradingProvider) getListenKey() string {
+	trading.Lock()
+	defer trading.Unlock()
+	return trading.listenKey
+}
+
+func (trading *TradingProvider) setListenKey(key string) {
+	trading.Lock()
+	defer trading.Unlock()
+	trading.listenKey = key
+}
+
+/*
+runUserStream - user data stream lifecycle:
+listen key is created, websocket is connected and REST snapshots are sent,
+then key is refreshed until connection error, key expiration or stop.
+After any gap everything starts again with new key and snapshots,
+so updates missed while reconnecting are reconciled by snapshots.
+Key of iteration is kept, so stopped stream never refreshes or deletes key of next Subscribe
+*/
+func (trading *TradingProvider) runUserStream(stop chan struct{}) {
+	for {
+		key, err := trading.CreateListenkey(trading.credentials.APIKey)
+		if err != nil {
+			log.Println("[BINANCE] Error creating listen key:", err)
+			trading.publishErr(err)
+			if trading.wait(stop, userStreamRetry) {
+				return
+			}
+			continue
+		}
+
+		wsClient := websocket.NewClient(userDataStreamURL+key, trading.httpProxy)
+		if err := wsClient.Connect(); err != nil {
+			log.Println("[BINANCE] Error connecting user data stream:", err)
+			trading.publishErr(err)
+			if trading.wait(stop, userStreamRetry) {
+				return
+			}
+			continue
+		}
+		wsClient.ChangeKeepAlive(false)
+		// errors channel by connection: closed connection errors don't restart new one
+		ech := make(chan error, 2)
+		wsClient.Listen(trading.ch, ech)
+		trading.Lock()
+		trading.wsClient = wsClient
+		trading.Unlock()
+
+		// expiration of previous key isn't related to new one
+		select {
+		case <-trading.expired:
+		default:
+		}
+		go trading.snapshot()
+
+		stopped := trading.keepUserStream(stop, ech, key)
+		if err := wsClient.Exit(); err != nil {
+			log.Println("[BINANCE] Error closing user data stream:", err)
+		}
+		if stopped {
+			if err := trading.deleteKey(key); err != nil {
+				log.Println("[BINANCE] Error deleting listen key:", err)
+			}
+			return
+		}
+		if trading.wait(stop, userStreamRetry) {
+			return
+		}
+	}
+}
+
+// keepUserStream - refreshing listen key while stream is alive, returns true on stop
+func (trading *TradingProvider) keepUserStream(stop chan struct{}, ech chan error, key string) bool {
+	refresh := time.NewTicker(listenKeyRefresh)
+	defer refresh.Stop()
+	lifetime := time.NewTimer(userStreamLifetime)
+	defer lifetime.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return true
+		case err := <-ech:
+			log.Println("[BINANCE] User data stream error:", err)
+			trading.publishErr(err)
+			return false
+		case <-trading.expired:
+			log.Println("[BINANCE] Listen key expired")
+			return false
+		case <-lifetime.C:
+			return false
+		case <-refresh.C:
+			if err := trading.pingKey(key); err != nil {
+				log.Println("[BINANCE] Error refreshing listen key:", err)
+				return false
+			}
+		}
+	}
+}
+
+// wait - sleeping before retry, returns true on stop
+func (trading *TradingProvider) wait(stop chan struct{}, d time.Duration) bool {
+	select {
+	case <-stop:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+// snapshot - sending REST snapshots of balances, orders and trades
+func (trading *TradingProvider) snapshot() {
+	ui, err := trading.Info()
+	if err != nil {
+		log.Println("[BINANCE] Balances snapshot error:", err)
+	}
+	trading.uic <- schemas.UserInfoChannel{
+		Data:     ui,
+		DataType: "s",
+		Error:    err,
+	}
+
+	o, err := trading.Orders(trading.symbols)
+	if err != nil {
+		log.Println("[BINANCE] Orders snapshot error:", err)
+	}
+	trading.uoc <- schemas.UserOrdersChannel{
+		Data:     o,
+		DataType: "s",
+		Error:    err,
+	}
+
+	t, _, err := trading.Trades(schemas.FilterOptions{Symbols: trading.symbols})
+	if err != nil {
+		log.Println("[BINANCE] Trades snapshot error:", err)
+	}
+	trading.utc <- schemas.UserTradesChannel{
+		Data:     t,
+		DataType: "s",
+		Error:    err,
+	}
+}
+
+func (trading *TradingProvider) publishErr(err error) {
+	go func() {
+		trading.uic <- schemas.UserInfoChannel{
+			Data:     schemas.UserInfo{},
+			DataType: "u",
+			Error:    err,
+		}
+	}()
+}
@@ -39,15 +39,23 @@ func (ubr *UserBalanceResponse) Map(prices map[string]float64) schemas.UserInfo
 			log.Println("Error parsing locked", err)
 		}
 		balances[b.Asset] = schemas.Balance{
-			Coin:      b.Asset,
-			Available: free,
-			InOrders:  locked,
-			Total:     free + locked,
+			Coin:             b.Asset,
+			Available:        free,
+			InOrders:         locked,
+			Total:            free + locked,
+			AvailableDecimal: schemas.Decimal(b.Free),
+			InOrdersDecimal:  schemas.Decimal(b.Locked),
+			TotalDecimal:     schemas.Decimal(b.Free).Add(schemas.Decimal(b.Locked)),
 		}
 	}
 	return schemas.UserInfo{
 		Balances: balances,
 		Prices:   prices,
+		// commissions are in basis points, 10 is 0.1%
+		Fees: &schemas.Fees{
+			Maker: float64(ubr.MakerCommission) / 10000,
+			Taker: float64(ubr.TakerCommission) / 10000,
+		},
 	}
 }
 
@@ -96,6 +104,10 @@ func (uor *UserOrdersResponse) Map() (orders []schemas.Order) {
 			Remove:       0,
 			CreatedAt:    o.Time,
 			Status:       o.Status,
+
+			PriceDecimal:        schemas.Decimal(o.Price),
+			AmountDecimal:       schemas.Decimal(o.OriginalQuantity),
+			AmountFilledDecimal: schemas.Decimal(o.ExecQuantity),
 		}
 
 		if o.Status == "TRADE" {
@@ -154,7 +166,12 @@ func (utr *UserTradesResponse) Map() (trades []schemas.Trade) {
 			Price:     price,
 			Amount:    amount,
 			Fee:       commission,
+			FeeCoin:   t.CommissionAsset,
 			Timestamp: t.Time,
+
+			PriceDecimal:  schemas.Decimal(t.Price),
+			AmountDecimal: schemas.Decimal(t.Quantity),
+			FeeDecimal:    schemas.Decimal(t.Commission),
 		})
 	}
 	return trades
@@ -211,10 +228,13 @@ func (bm *balanceMessage) Map() schemas.UserInfo {
 			log.Println("Error parsing locked", err)
 		}
 		balances[b.Asset] = schemas.Balance{
-			Coin:      b.Asset,
-			Available: free,
-			InOrders:  locked,
-			Total:     free + locked,
+			Coin:             b.Asset,
+			Available:        free,
+			InOrders:         locked,
+			Total:            free + locked,
+			AvailableDecimal: schemas.Decimal(b.Free),
+			InOrdersDecimal:  schemas.Decimal(b.Locked),
+			TotalDecimal:     schemas.Decimal(b.Free).Add(schemas.Decimal(b.Locked)),
 		}
 	}
 	return schemas.UserInfo{
@@ -237,19 +257,28 @@ type tradesMessage struct {
 	TransactionTime      int64  `json:"T"`
 	Ignore               int    `json:"O"` // ignore this
 	TradeID              int64  `json:"t"`
+	LastQuantity         string `json:"l"` // last executed quantity
+	LastPrice            string `json:"L"` // last executed price
+	Commission           string `json:"n"`
+	CommissionAsset      string `json:"N"`
 }
 
 func (tm *tradesMessage) Map() (trades []schemas.Trade) {
 	symbol, _, _ := parseSymbol(tm.Symbol)
 
-	price, err := strconv.ParseFloat(tm.OrderPrice, 64)
+	// trade is last fill of order, order price and quantity are not its ones
+	price, err := strconv.ParseFloat(tm.LastPrice, 64)
 	if err != nil {
 		log.Println("Error mapping price in private trades. Binance:", err)
 	}
-	amount, err := strconv.ParseFloat(tm.Quantity, 64)
+	amount, err := strconv.ParseFloat(tm.LastQuantity, 64)
 	if err != nil {
 		log.Println("Error mapping qty in private trades. Binance:", err)
 	}
+	fee, err := strconv.ParseFloat(tm.Commission, 64)
+	if err != nil {
+		log.Println("Error mapping commission in private trades. Binance:", err)
+	}
 	trades = append(trades, schemas.Trade{
 		ID:        fmt.Sprintf("%d", tm.TradeID),
 		OrderID:   strconv.FormatInt(tm.OrderID, 10),
@@ -257,7 +286,13 @@ func (tm *tradesMessage) Map() (trades []schemas.Trade) {
 		Type:      strings.ToUpper(tm.Side),
 		Price:     price,
 		Amount:    amount,
+		Fee:       fee,
+		FeeCoin:   tm.CommissionAsset,
 		Timestamp: tm.TransactionTime,
+
+		PriceDecimal:  schemas.Decimal(tm.LastPrice),
+		AmountDecimal: schemas.Decimal(tm.LastQuantity),
+		FeeDecimal:    schemas.Decimal(tm.Commission),
 	})
 	return trades
 }
@@ -283,6 +318,9 @@ func (tm *tradesMessage) MapOrder() (orders []schemas.Order) {
 		Remove:    0,
 		CreatedAt: tm.TransactionTime,
 		Status:    tm.CurrentExecutionType,
+
+		PriceDecimal:  schemas.Decimal(tm.OrderPrice),
+		AmountDecimal: schemas.Decimal(tm.Quantity),
 	}
 
 	if strings.Contains(strings.ToUpper(o.Status), "CANCEL") {
@@ -2,9 +2,9 @@ package binance
 
 import (
 	"errors"
-	"sync"
 	"time"
 
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -12,42 +12,48 @@ import (
 // OrdersProvider - order book provider structure
 type OrdersProvider struct {
 	httpProxy proxy.Provider
-	symbols   []schemas.Symbol
-	books     []*OrderBookGroup
-
-	sync.Mutex
+	groups    *listing.Groups
 }
 
 // NewOrdersProvider - OrdersProvider constructor
 func NewOrdersProvider(httpProxy proxy.Provider) *OrdersProvider {
-	return &OrdersProvider{
+	ob := &OrdersProvider{
 		httpProxy: httpProxy,
 	}
+	ob.groups = listing.NewGroups(orderBookSymbolsLimit, ob.startGroup)
+	return ob
 }
 
-// SetSymbols - setting symbols and creating groups by symbols chunks
+// SetSymbols - replacing symbols, they are split into groups by symbols chunks on subscription.
+// Symbols of running subscription are changed by AddSymbols and RemoveSymbols
 func (ob *OrdersProvider) SetSymbols(symbols []schemas.Symbol) schemas.OrdersProvider {
-	slice := make([]schemas.Symbol, len(symbols))
-	copy(slice, symbols)
-	capacity := orderBookSymbolsLimit
-	for {
-		if len(slice) <= capacity {
-			ob.books = append(
-				ob.books,
-				NewOrderBookGroup(slice, ob.httpProxy),
-			)
-			break
-		}
-		ob.books = append(
-			ob.books,
-			NewOrderBookGroup(slice[0:capacity], ob.httpProxy),
-		)
-
-		slice = slice[capacity:]
-	}
+	ob.groups.Reset(symbols)
 	return ob
 }
 
+// SubscribeAll - subscribing all groups
+func (ob *OrdersProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return ob.groups.Subscribe(d)
+}
+
+// AddSymbols - adding symbols to running subscription, i.e. new listings
+func (ob *OrdersProvider) AddSymbols(symbols []schemas.Symbol) {
+	ob.groups.Add(symbols)
+}
+
+// RemoveSymbols - removing symbols from subscription, i.e. delistings.
+// Symbols are unsubscribed without reconnect, groups without symbols are stopped
+func (ob *OrdersProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	ob.groups.Remove(symbols)
+}
+
+// startGroup - creating and starting group of symbols
+func (ob *OrdersProvider) startGroup(symbols []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) listing.Group {
+	group := NewOrderBookGroup(symbols, ob.httpProxy)
+	go group.Start(ch)
+	return group
+}
+
 // Subscribe - subscribing to quote by one symbol
 func (ob *OrdersProvider) Subscribe(symbol schemas.Symbol, d time.Duration) (r chan schemas.ResultChannel) {
 	ch := make(chan schemas.ResultChannel)
@@ -56,18 +62,6 @@ func (ob *OrdersProvider) Subscribe(symbol schemas.Symbol, d time.Duration) (r c
 	return ch
 }
 
-// SubscribeAll - subscribing all groups
-func (ob *OrdersProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
-	bufLength := len(ob.symbols)
-	ch := make(chan schemas.ResultChannel, 2*bufLength)
-
-	for _, orderBook := range ob.books {
-		go orderBook.Start(ch)
-		time.Sleep(100 * time.Millisecond)
-	}
-	return ch
-}
-
 // Get - getting orderbook snapshot by symbol
 func (ob *OrdersProvider) Get(symbol schemas.Symbol) (book schemas.OrderBook, err error) {
 	group := NewOrderBookGroup([]schemas.Symbol{symbol}, ob.httpProxy)
@@ -5,10 +5,10 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
-	"github.com/syndicatedb/goex/internal/websocket"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -38,36 +38,41 @@ type orderBookSnapshot struct {
 type OrderBookGroup struct {
 	symbols []schemas.Symbol
 
-	wsClient     *websocket.Client
+	stream       *stream
 	httpClient   *httpclient.Client
 	httpProxy    proxy.Provider
 	lastUpdateID map[string]int64
 
-	dataCh  chan []byte
-	errorCh chan error
+	dataCh chan []byte
 
 	resultCh chan schemas.ResultChannel
+	sync.Mutex
 }
 
 // NewOrderBookGroup - OrderBookGroup constructor
 func NewOrderBookGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *OrderBookGroup {
 	proxyClient := httpProxy.NewClient(exchangeName)
 
-	return &OrderBookGroup{
-		symbols:      symbols,
+	ob := &OrderBookGroup{
 		httpProxy:    httpProxy,
 		httpClient:   httpclient.New(proxyClient),
 		lastUpdateID: make(map[string]int64),
-		dataCh:       make(chan []byte, 2*len(symbols)),
-		errorCh:      make(chan error, 2*len(symbols)),
+		dataCh:       make(chan []byte, streamBufferSize),
 	}
+	ob.stream = newStream(ob.dataCh, httpProxy)
+	ob.AddSymbols(symbols)
+	return ob
 }
 
-// Get - loading order books snapshot by one symbol
+// Get - loading order books snapshot by group symbols
 func (ob *OrderBookGroup) Get() (book []schemas.OrderBook, err error) {
+	return ob.get(ob.getSymbols())
+}
+
+func (ob *OrderBookGroup) get(symbols []schemas.Symbol) (book []schemas.OrderBook, err error) {
 	var b []byte
-	var resp orderBookSnapshot
-	for _, symbol := range ob.symbols {
+	for _, symbol := range symbols {
+		var resp orderBookSnapshot
 		query := httpclient.Params()
 		query.Set("symbol", unparseSymbol(symbol.Name))
 		query.Set("limit", "100")
@@ -80,6 +85,7 @@ func (ob *OrderBookGroup) Get() (book []schemas.OrderBook, err error) {
 		if err = json.Unmarshal(b, &resp); err != nil {
 			log.Println("[BINANCE] Error unmarshaling orderbook snapshot", err)
 		}
+		ob.setLastUpdateID(strings.ToUpper(unparseSymbol(symbol.Name)), resp.LastUpdateID)
 
 		result := ob.mapSnapshot(resp, symbol.OriginalName)
 		if err != nil {
@@ -92,50 +98,113 @@ func (ob *OrderBookGroup) Get() (book []schemas.OrderBook, err error) {
 	return
 }
 
-// Start - starting updates
+// Start - starting updates, snapshots are sent after every connection and each 5 minutes
 func (ob *OrderBookGroup) Start(ch chan schemas.ResultChannel) {
 	log.Println("[BINANCE] Orderbook starting")
+	ob.Lock()
 	ob.resultCh = ch
+	ob.Unlock()
 
+	ob.stream.onConnect = func() {
+		go ob.publishSnapshot(ob.getSymbols())
+	}
+	ob.stream.onError = func(err error) {
+		ob.resultCh <- schemas.ResultChannel{
+			Error: err,
+		}
+	}
 	go func() {
 		for {
-			result, err := ob.Get()
-			for _, book := range result {
-				ob.resultCh <- schemas.ResultChannel{
-					DataType: "s",
-					Data:     book,
-					Error:    err,
-				}
+			select {
+			case <-ob.stream.stop:
+				return
+			case <-time.After(5 * time.Minute):
 			}
-			time.Sleep(5 * time.Minute)
+			ob.publishSnapshot(ob.getSymbols())
 		}
 	}()
 	ob.listen()
-	ob.connect()
+	ob.stream.Start()
+}
+
+// Stop - closing group connection
+func (ob *OrderBookGroup) Stop() {
+	ob.stream.Stop()
+}
+
+// Len - count of group symbols
+func (ob *OrderBookGroup) Len() int {
+	ob.Lock()
+	defer ob.Unlock()
+	return len(ob.symbols)
 }
 
-func (ob *OrderBookGroup) restart() {
-	time.Sleep(5 * time.Second)
-	if err := ob.wsClient.Exit(); err != nil {
-		log.Println("[BINANCE] Error destroying connection: ", err)
+// AddSymbols - subscribing to symbols depth without reconnect, snapshots are sent for running group
+func (ob *OrderBookGroup) AddSymbols(symbols []schemas.Symbol) {
+	ob.Lock()
+	ob.symbols = append(ob.symbols, symbols...)
+	running := ob.resultCh != nil
+	ob.Unlock()
+
+	if err := ob.stream.Subscribe(ob.streams(symbols)); err != nil {
+		log.Println("[BINANCE] Error subscribing to orderbooks: ", err)
+	}
+	if running {
+		go ob.publishSnapshot(symbols)
 	}
-	ob.Start(ob.resultCh)
 }
 
-// connect - creating new WS client and establishing connection
-func (ob *OrderBookGroup) connect() {
-	var smbls []string
-	for _, s := range ob.symbols {
-		smbls = append(smbls, unparseSymbol(s.Name))
+// RemoveSymbols - unsubscribing from symbols depth, unknown symbols are skipped
+func (ob *OrderBookGroup) RemoveSymbols(symbols []schemas.Symbol) {
+	var removed []schemas.Symbol
+	ob.Lock()
+	ob.symbols, removed = removeSymbols(ob.symbols, symbols)
+	for _, s := range removed {
+		delete(ob.lastUpdateID, strings.ToUpper(unparseSymbol(s.Name)))
 	}
+	ob.Unlock()
 
-	ws := websocket.NewClient(wsURL+strings.ToLower(strings.Join(smbls, "@depth/")+"@depth"), ob.httpProxy)
-	ob.wsClient = ws
-	if err := ob.wsClient.Connect(); err != nil {
-		log.Println("[BINANCE] Error connecting to binance API: ", err)
-		ob.restart()
+	if err := ob.stream.Unsubscribe(ob.streams(removed)); err != nil {
+		log.Println("[BINANCE] Error unsubscribing from orderbooks: ", err)
 	}
-	ob.wsClient.Listen(ob.dataCh, ob.errorCh)
+}
+
+func (ob *OrderBookGroup) streams(symbols []schemas.Symbol) (names []string) {
+	for _, s := range symbols {
+		names = append(names, strings.ToLower(unparseSymbol(s.Name))+"@depth")
+	}
+	return
+}
+
+func (ob *OrderBookGroup) publishSnapshot(symbols []schemas.Symbol) {
+	result, err := ob.get(symbols)
+	for _, book := range result {
+		ob.resultCh <- schemas.ResultChannel{
+			DataType: "s",
+			Data:     book,
+			Error:    err,
+		}
+	}
+}
+
+func (ob *OrderBookGroup) getSymbols() []schemas.Symbol {
+	ob.Lock()
+	defer ob.Unlock()
+	symbols := make([]schemas.Symbol, len(ob.symbols))
+	copy(symbols, ob.symbols)
+	return symbols
+}
+
+func (ob *OrderBookGroup) setLastUpdateID(symbol string, id int64) {
+	ob.Lock()
+	defer ob.Unlock()
+	ob.lastUpdateID[symbol] = id
+}
+
+func (ob *OrderBookGroup) getLastUpdateID(symbol string) int64 {
+	ob.Lock()
+	defer ob.Unlock()
+	return ob.lastUpdateID[symbol]
 }
 
 // listen - listening to updates from WS
@@ -151,15 +220,6 @@ func (ob *OrderBookGroup) listen() {
 			}
 		}
 	}()
-	go func() {
-		for err := range ob.errorCh {
-			ob.resultCh <- schemas.ResultChannel{
-				Error: err,
-			}
-			log.Println("[BINANCE] Error listening:", err)
-			ob.restart()
-		}
-	}()
 }
 
 // handleMessage - handling message from WS
@@ -170,7 +230,7 @@ func (ob *OrderBookGroup) handleUpdates(data []byte) (orders schemas.OrderBook,
 		log.Println("[BINANCE] Error handling updates", err)
 	}
 
-	if msg.Data.FinalUpdateID <= ob.lastUpdateID[msg.Data.Symbol] {
+	if msg.Data.FinalUpdateID <= ob.getLastUpdateID(msg.Data.Symbol) {
 		return
 	}
 
@@ -5,10 +5,9 @@ import (
 	"log"
 	"strconv"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/syndicatedb/goex/internal/http"
-	"github.com/syndicatedb/goex/internal/websocket"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -53,57 +52,85 @@ type QuotesStream struct {
 // QuotesGroup - quotes group strcutre
 type QuotesGroup struct {
 	symbols    []schemas.Symbol
-	wsClient   *websocket.Client
+	stream     *stream
 	httpClient *httpclient.Client
 	httpProxy  proxy.Provider
 
-	dataCh  chan []byte
-	errorCh chan error
+	dataCh chan []byte
 
 	resultCh chan schemas.ResultChannel
+	sync.Mutex
 }
 
 // NewQuotesGroup - QuotesGroup constructor
 func NewQuotesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *QuotesGroup {
 	proxyClient := httpProxy.NewClient(exchangeName)
 
-	return &QuotesGroup{
-		symbols:    symbols,
+	q := &QuotesGroup{
 		httpProxy:  httpProxy,
 		httpClient: httpclient.New(proxyClient),
-		dataCh:     make(chan []byte, 2*len(symbols)),
-		errorCh:    make(chan error, 2*len(symbols)),
+		dataCh:     make(chan []byte, streamBufferSize),
 	}
+	q.stream = newStream(q.dataCh, httpProxy)
+	q.AddSymbols(symbols)
+	return q
 }
 
 // Start - starting updates
 func (q *QuotesGroup) Start(ch chan schemas.ResultChannel) {
+	q.Lock()
 	q.resultCh = ch
+	q.Unlock()
+
+	q.stream.onError = func(err error) {
+		q.resultCh <- schemas.ResultChannel{
+			Error: err,
+		}
+	}
 	q.listen()
-	q.connect()
+	q.stream.Start()
+}
+
+// Stop - closing group connection
+func (q *QuotesGroup) Stop() {
+	q.stream.Stop()
+}
+
+// Len - count of group symbols
+func (q *QuotesGroup) Len() int {
+	q.Lock()
+	defer q.Unlock()
+	return len(q.symbols)
 }
 
-func (q *QuotesGroup) restart() {
-	time.Sleep(5 * time.Second)
-	if err := q.wsClient.Exit(); err != nil {
-		log.Println("[BINANCE] Error destroying connection: ", err)
+// AddSymbols - subscribing to symbols tickers without reconnect
+func (q *QuotesGroup) AddSymbols(symbols []schemas.Symbol) {
+	q.Lock()
+	q.symbols = append(q.symbols, symbols...)
+	q.Unlock()
+
+	if err := q.stream.Subscribe(q.streams(symbols)); err != nil {
+		log.Println("[BINANCE] Error subscribing to quotes: ", err)
 	}
-	q.Start(q.resultCh)
 }
 
-// connect - creating new WS client and establishing connection
-func (q *QuotesGroup) connect() {
-	var smbls []string
-	for _, s := range q.symbols {
-		smbls = append(smbls, strings.ToLower(s.OriginalName))
+// RemoveSymbols - unsubscribing from symbols tickers, unknown symbols are skipped
+func (q *QuotesGroup) RemoveSymbols(symbols []schemas.Symbol) {
+	var removed []schemas.Symbol
+	q.Lock()
+	q.symbols, removed = removeSymbols(q.symbols, symbols)
+	q.Unlock()
+
+	if err := q.stream.Unsubscribe(q.streams(removed)); err != nil {
+		log.Println("[BINANCE] Error unsubscribing from quotes: ", err)
 	}
+}
 
-	q.wsClient = websocket.NewClient(wsURL+strings.Join(smbls, "@ticker/")+"@ticker", q.httpProxy)
-	if err := q.wsClient.Connect(); err != nil {
-		log.Println("[BINANCE] Error connecting to binance API: ", err)
-		q.restart()
+func (q *QuotesGroup) streams(symbols []schemas.Symbol) (names []string) {
+	for _, s := range symbols {
+		names = append(names, strings.ToLower(s.OriginalName)+"@ticker")
 	}
-	q.wsClient.Listen(q.dataCh, q.errorCh)
+	return
 }
 
 // listen - listening to updates from WS
@@ -117,15 +144,6 @@ func (q *QuotesGroup) listen() {
 			}
 		}
 	}()
-	go func() {
-		for err := range q.errorCh {
-			q.resultCh <- schemas.ResultChannel{
-				Error: err,
-			}
-			log.Println("[BINANCE] Error listening:", err)
-			q.restart()
-		}
-	}()
 }
 
 // Get - getting quote by one symbol
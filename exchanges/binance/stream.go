@@ -0,0 +1,269 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/internal/websocket"
+	"github.com/syndicatedb/goproxy/proxy"
+)
+
+const (
+	methodSubscribe   = "SUBSCRIBE"
+	methodUnsubscribe = "UNSUBSCRIBE"
+
+	// binance closes connection after more than 5 incoming messages per second
+	streamRequestInterval = 250 * time.Millisecond
+	// delay before reconnect, it's doubled after every failure
+	streamRetry    = 5 * time.Second
+	maxStreamRetry = 2 * time.Minute
+)
+
+/*
+streamRequest - live subscription message
+
+	{"method": "SUBSCRIBE", "params": ["btcusdt@aggTrade", "btcusdt@depth"], "id": 1}
+*/
+type streamRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	ID     int64    `json:"id"`
+}
+
+/*
+streamReply - combined stream message or reply to request:
+
+	{"stream": "btcusdt@depth", "data": {...}}
+	{"result": null, "id": 1}
+	{"error": {"code": 2, "msg": "Invalid request"}, "id": 1}
+*/
+type streamReply struct {
+	Stream string `json:"stream"`
+	ID     int64  `json:"id"`
+	Error  *struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	} `json:"error"`
+}
+
+/*
+stream - combined stream connection with streams changed at runtime
+by SUBSCRIBE and UNSUBSCRIBE methods, so adding or removing symbol
+doesn't reconnect. Current streams are subscribed again after reconnect
+*/
+type stream struct {
+	httpProxy proxy.Provider
+	wsClient  *websocket.Client
+	streams   map[string]struct{}
+	requestID int64
+	lastSent  time.Time
+
+	// onConnect - called after every connection, i.e. to load snapshots
+	onConnect func()
+	// onError - called on connection errors before reconnect
+	onError func(err error)
+
+	dch  chan []byte
+	out  chan []byte
+	stop chan struct{}
+
+	sync.Mutex
+}
+
+// newStream - stream constructor, streams data is sent to out
+func newStream(out chan []byte, httpProxy proxy.Provider) *stream {
+	return &stream{
+		httpProxy: httpProxy,
+		streams:   make(map[string]struct{}),
+		dch:       make(chan []byte, streamBufferSize),
+		out:       out,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start - connecting and subscribing to added streams, blocks while stream is running
+func (s *stream) Start() {
+	s.listen()
+	s.run()
+}
+
+// Stop - closing connection, stream isn't reconnected
+func (s *stream) Stop() {
+	close(s.stop)
+}
+
+// Len - count of subscribed streams
+func (s *stream) Len() int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.streams)
+}
+
+// Subscribe - adding streams, they are sent to exchange if connection is established
+func (s *stream) Subscribe(names []string) (err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var fresh []string
+	for _, name := range names {
+		if _, ok := s.streams[name]; !ok {
+			fresh = append(fresh, name)
+		}
+	}
+	if len(fresh) == 0 {
+		return
+	}
+	if len(s.streams)+len(fresh) > streamsPerConnection {
+		return fmt.Errorf("[BINANCE] Streams limit %d of connection exceeded", streamsPerConnection)
+	}
+	for _, name := range fresh {
+		s.streams[name] = struct{}{}
+	}
+	return s.send(methodSubscribe, fresh)
+}
+
+// Unsubscribe - removing streams
+func (s *stream) Unsubscribe(names []string) (err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var removed []string
+	for _, name := range names {
+		if _, ok := s.streams[name]; ok {
+			delete(s.streams, name)
+			removed = append(removed, name)
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+	return s.send(methodUnsubscribe, removed)
+}
+
+// send - writing request to connection, not faster than binance limit.
+// Has to be called under lock
+func (s *stream) send(method string, names []string) error {
+	if s.wsClient == nil {
+		// not connected yet, streams are subscribed on connect
+		return nil
+	}
+	if wait := streamRequestInterval - time.Since(s.lastSent); wait > 0 {
+		time.Sleep(wait)
+	}
+	s.lastSent = time.Now()
+	s.requestID++
+	return s.wsClient.Write(streamRequest{
+		Method: method,
+		Params: names,
+		ID:     s.requestID,
+	})
+}
+
+/*
+run - reconnect loop: connecting and waiting for connection error.
+Delay before reconnect is doubled after every failure up to maxStreamRetry
+and reset when connection has been working longer than maxStreamRetry
+*/
+func (s *stream) run() {
+	wait := streamRetry
+	for {
+		started := time.Now()
+		ws, ech, err := s.connect()
+		if err == nil {
+			select {
+			case err = <-ech:
+			case <-s.stop:
+				s.disconnect(ws)
+				return
+			}
+		}
+		log.Printf("[BINANCE] Stream error, reconnecting in %v: %v\n", wait, err)
+		s.publishErr(err)
+		s.disconnect(ws)
+		if time.Since(started) > maxStreamRetry {
+			wait = streamRetry
+		}
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(wait):
+		}
+		if wait *= 2; wait > maxStreamRetry {
+			wait = maxStreamRetry
+		}
+	}
+}
+
+/*
+connect - connecting and subscribing to current streams.
+Returns errors channel of connection, first error means connection is broken
+*/
+func (s *stream) connect() (ws *websocket.Client, ech chan error, err error) {
+	ws = websocket.NewClient(wsURL, s.httpProxy)
+	if err = ws.Connect(); err != nil {
+		return nil, nil, err
+	}
+	// errors channel by connection: closed connection errors don't restart new one
+	ech = make(chan error, 2)
+	ws.Listen(s.dch, ech)
+
+	s.Lock()
+	s.wsClient = ws
+	var names []string
+	for name := range s.streams {
+		names = append(names, name)
+	}
+	if len(names) > 0 {
+		err = s.send(methodSubscribe, names)
+	}
+	s.Unlock()
+	if err != nil {
+		return ws, nil, fmt.Errorf("subscribing to streams: %v", err)
+	}
+	if s.onConnect != nil {
+		s.onConnect()
+	}
+	return ws, ech, nil
+}
+
+// disconnect - closing connection, requests aren't sent until next connection
+func (s *stream) disconnect(ws *websocket.Client) {
+	s.Lock()
+	s.wsClient = nil
+	s.Unlock()
+	if ws == nil {
+		return
+	}
+	if err := ws.Exit(); err != nil {
+		log.Println("[BINANCE] Error destroying connection: ", err)
+	}
+}
+
+func (s *stream) publishErr(err error) {
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
+
+// listen - sending streams data, replies to requests are only checked for errors
+func (s *stream) listen() {
+	go func() {
+		for b := range s.dch {
+			var msg streamReply
+			if err := json.Unmarshal(b, &msg); err != nil {
+				log.Println("[BINANCE] Error parsing message: ", err)
+				continue
+			}
+			if msg.Stream != "" {
+				s.out <- b
+				continue
+			}
+			if msg.Error != nil {
+				log.Println("[BINANCE] Error of request", msg.ID, ":", msg.Error.Code, msg.Error.Msg)
+			}
+		}
+	}()
+}
@@ -2,11 +2,11 @@ package binance
 
 import (
 	"encoding/json"
-	"log"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -17,15 +17,27 @@ type SymbolsProvider struct {
 }
 
 type symbol struct {
-	Symbol             string        `json:"symbol"`
-	Status             string        `json:"status"`
-	BaseAsset          string        `json:"baseAsset"`
-	BaseAssetPrecision int           `json:"baseAssetPrecision"`
-	QuoteAsset         string        `json:"quoteAsset"`
-	QuotePrecision     int           `json:"quotePrecision"`
-	OrderTypes         []string      `json:"orderTypes"`
-	IcebergAllowed     bool          `json:"icebergAllowed"`
-	Filters            []interface{} `json:"filters"`
+	Symbol             string         `json:"symbol"`
+	Status             string         `json:"status"`
+	BaseAsset          string         `json:"baseAsset"`
+	BaseAssetPrecision int            `json:"baseAssetPrecision"`
+	QuoteAsset         string         `json:"quoteAsset"`
+	QuotePrecision     int            `json:"quotePrecision"`
+	OrderTypes         []string       `json:"orderTypes"`
+	IcebergAllowed     bool           `json:"icebergAllowed"`
+	Filters            []symbolFilter `json:"filters"`
+}
+
+// symbolFilter - exchangeInfo filter, fields are filled depending on filter type
+type symbolFilter struct {
+	FilterType  string `json:"filterType"`
+	MinPrice    string `json:"minPrice"`
+	MaxPrice    string `json:"maxPrice"`
+	TickSize    string `json:"tickSize"`
+	MinQty      string `json:"minQty"`
+	MaxQty      string `json:"maxQty"`
+	StepSize    string `json:"stepSize"`
+	MinNotional string `json:"minNotional"`
 }
 
 type infoMessage struct {
@@ -59,66 +71,36 @@ func (sp *SymbolsProvider) Get() (symbols []schemas.Symbol, err error) {
 		return
 	}
 
-	var minPrice float64
-	var maxPrice float64
-	var minAmount float64
-	var maxAmount float64
-
 	for _, smb := range resp.Symbols {
 		name, baseCoin, quoteCoin := parseSymbol(smb.Symbol)
-		for _, filter := range smb.Filters {
-			if f, ok := filter.(map[string]interface{}); ok {
-
-				if filterType, ok := f["filterType"].(string); ok && filterType == "PRICE_FILTER" {
-					if min, ok := f["minPrice"].(string); ok {
-						minPrice, err = strconv.ParseFloat(min, 64)
-						if err != nil {
-							log.Println("[BINANCE] Error parsing symbols data:", err)
-						}
-					}
-					if max, ok := f["maxPrice"].(string); ok {
-						maxPrice, err = strconv.ParseFloat(max, 64)
-						if err != nil {
-							log.Println("[BINANCE] Error parsing symbols data:", err)
-						}
-					}
-				}
-
-				if filterType, ok := f["filterType"].(string); ok && filterType == "LOT_SIZE" {
-					if min, ok := f["minQty"].(string); ok {
-						minAmount, err = strconv.ParseFloat(min, 64)
-						if err != nil {
-							log.Println("[BINANCE] Error parsing symbols data:", err)
-						}
-					}
-					if max, ok := f["maxQty"].(string); ok {
-						maxAmount, err = strconv.ParseFloat(max, 64)
-						if err != nil {
-							log.Println("[BINANCE] Error parsing symbols data:", err)
-						}
-					}
-				}
-			}
-		}
-
 		s := schemas.Symbol{
 			Name:         name,
 			OriginalName: smb.Symbol,
 			Coin:         quoteCoin,
 			BaseCoin:     baseCoin,
-			MinPrice:     minPrice,
-			MaxPrice:     maxPrice,
-			MinAmount:    minAmount,
-			MaxAmount:    maxAmount,
+			Status:       mapSymbolStatus(smb.Status),
+			OrderTypes:   smb.OrderTypes,
+			Fee:          defaultTakerFee,
+			MakerFee:     defaultMakerFee,
+			TakerFee:     defaultTakerFee,
+			DefaultFees:  true,
 		}
-		if smb.QuotePrecision > smb.BaseAssetPrecision {
-			s.PricePrecision = smb.BaseAssetPrecision
-		} else if smb.QuotePrecision < smb.BaseAssetPrecision {
-			s.PricePrecision = smb.QuotePrecision
-		} else {
-			s.PricePrecision = smb.BaseAssetPrecision
+		for _, f := range smb.Filters {
+			switch f.FilterType {
+			case "PRICE_FILTER":
+				s.MinPrice = parseFloat(f.MinPrice)
+				s.MaxPrice = parseFloat(f.MaxPrice)
+				s.TickSize = parseFloat(f.TickSize)
+				s.PricePrecision = stepPrecision(f.TickSize)
+			case "LOT_SIZE":
+				s.MinAmount = parseFloat(f.MinQty)
+				s.MaxAmount = parseFloat(f.MaxQty)
+				s.LotStep = parseFloat(f.StepSize)
+				s.AmountPrecision = stepPrecision(f.StepSize)
+			case "MIN_NOTIONAL":
+				s.MinNotional = parseFloat(f.MinNotional)
+			}
 		}
-		s.AmountPrecision = s.PricePrecision
 
 		symbols = append(symbols, s)
 	}
@@ -126,6 +108,23 @@ func (sp *SymbolsProvider) Get() (symbols []schemas.Symbol, err error) {
 	return
 }
 
+// mapSymbolStatus - Binance has several non trading statuses, all of them mean trading halt
+func mapSymbolStatus(status string) string {
+	if status == "TRADING" {
+		return schemas.SymbolStatusTrading
+	}
+	return schemas.SymbolStatusHalted
+}
+
+// stepPrecision - count of decimals in step, e.g. "0.00100000" is 3
+func stepPrecision(step string) int {
+	step = strings.TrimRight(step, "0")
+	if i := strings.IndexByte(step, '.'); i >= 0 {
+		return len(step) - i - 1
+	}
+	return 0
+}
+
 // Subscribe - getting all symbols from Exchange
 func (sp *SymbolsProvider) Subscribe(d time.Duration) chan schemas.ResultChannel {
 	ch := make(chan schemas.ResultChannel)
@@ -142,3 +141,8 @@ func (sp *SymbolsProvider) Subscribe(d time.Duration) chan schemas.ResultChannel
 	}()
 	return ch
 }
+
+// SubscribeChanges - subscribing to symbols changes: listings, delistings, status and filters changes
+func (sp *SymbolsProvider) SubscribeChanges(d time.Duration) chan schemas.SymbolEventsChannel {
+	return listing.Watch(sp.Get, d)
+}
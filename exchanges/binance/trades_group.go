@@ -5,11 +5,10 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
-
-	"github.com/syndicatedb/goex/internal/websocket"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -48,60 +47,130 @@ type recentTradesStream struct {
 type TradesGroup struct {
 	symbols []schemas.Symbol
 
-	wsClient   *websocket.Client
+	stream     *stream
 	httpClient *httpclient.Client
 	httpProxy  proxy.Provider
 
-	dataCh  chan []byte
-	errorCh chan error
+	dataCh chan []byte
 
 	resultCh chan schemas.ResultChannel
+	sync.Mutex
 }
 
 // NewTradesGroup - TradesGroup constructor
 func NewTradesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *TradesGroup {
 	proxyClient := httpProxy.NewClient(exchangeName)
 
-	return &TradesGroup{
-		symbols:    symbols,
+	tg := &TradesGroup{
 		httpProxy:  httpProxy,
 		httpClient: httpclient.New(proxyClient),
-		dataCh:     make(chan []byte, 2*len(symbols)),
-		errorCh:    make(chan error, 2*len(symbols)),
+		dataCh:     make(chan []byte, streamBufferSize),
 	}
+	tg.stream = newStream(tg.dataCh, httpProxy)
+	tg.AddSymbols(symbols)
+	return tg
 }
 
-// Start - starting updates
+// Start - starting updates, snapshots are sent after every connection and each 5 minutes
 func (tg *TradesGroup) Start(ch chan schemas.ResultChannel) {
+	tg.Lock()
 	tg.resultCh = ch
-	tg.listen()
+	tg.Unlock()
+
+	tg.stream.onConnect = func() {
+		go tg.publishSnapshot(tg.getSymbols())
+	}
+	tg.stream.onError = func(err error) {
+		tg.resultCh <- schemas.ResultChannel{
+			Error: err,
+		}
+	}
 	go func() {
 		for {
-			result, err := tg.Get()
-			tg.resultCh <- schemas.ResultChannel{
-				DataType: "s",
-				Data:     result,
-				Error:    err,
+			select {
+			case <-tg.stream.stop:
+				return
+			case <-time.After(5 * time.Minute):
 			}
-			time.Sleep(5 * time.Minute)
+			tg.publishSnapshot(tg.getSymbols())
 		}
 	}()
-	tg.connect()
+	tg.listen()
+	tg.stream.Start()
+}
+
+// Stop - closing group connection
+func (tg *TradesGroup) Stop() {
+	tg.stream.Stop()
+}
+
+// Len - count of group symbols
+func (tg *TradesGroup) Len() int {
+	tg.Lock()
+	defer tg.Unlock()
+	return len(tg.symbols)
+}
+
+// AddSymbols - subscribing to symbols trades without reconnect, snapshots are sent for running group
+func (tg *TradesGroup) AddSymbols(symbols []schemas.Symbol) {
+	tg.Lock()
+	tg.symbols = append(tg.symbols, symbols...)
+	running := tg.resultCh != nil
+	tg.Unlock()
+
+	if err := tg.stream.Subscribe(tg.streams(symbols)); err != nil {
+		log.Println("[BINANCE] Error subscribing to trades: ", err)
+	}
+	if running {
+		go tg.publishSnapshot(symbols)
+	}
+}
+
+// RemoveSymbols - unsubscribing from symbols trades, unknown symbols are skipped
+func (tg *TradesGroup) RemoveSymbols(symbols []schemas.Symbol) {
+	var removed []schemas.Symbol
+	tg.Lock()
+	tg.symbols, removed = removeSymbols(tg.symbols, symbols)
+	tg.Unlock()
+
+	if err := tg.stream.Unsubscribe(tg.streams(removed)); err != nil {
+		log.Println("[BINANCE] Error unsubscribing from trades: ", err)
+	}
 }
 
-func (tg *TradesGroup) restart() {
-	time.Sleep(5 * time.Second)
-	if err := tg.wsClient.Exit(); err != nil {
-		log.Println("[BINANCE] Error destroying connection: ", err)
+func (tg *TradesGroup) streams(symbols []schemas.Symbol) (names []string) {
+	for _, s := range symbols {
+		names = append(names, strings.ToLower(s.OriginalName)+"@aggTrade")
 	}
-	tg.Start(tg.resultCh)
+	return
 }
 
-// Get - getting trades snapshot by symbol
+func (tg *TradesGroup) publishSnapshot(symbols []schemas.Symbol) {
+	result, err := tg.get(symbols)
+	tg.resultCh <- schemas.ResultChannel{
+		DataType: "s",
+		Data:     result,
+		Error:    err,
+	}
+}
+
+func (tg *TradesGroup) getSymbols() []schemas.Symbol {
+	tg.Lock()
+	defer tg.Unlock()
+	symbols := make([]schemas.Symbol, len(tg.symbols))
+	copy(symbols, tg.symbols)
+	return symbols
+}
+
+// Get - getting trades snapshot by group symbols
 func (tg *TradesGroup) Get() (result [][]schemas.Trade, err error) {
+	return tg.get(tg.getSymbols())
+}
+
+func (tg *TradesGroup) get(symbols []schemas.Symbol) (result [][]schemas.Trade, err error) {
 	var b []byte
 	var trades []schemas.Trade
-	for _, symbol := range tg.symbols {
+	for _, symbol := range symbols {
 		var resp []recentTrade
 
 		url := apiTrades + "?" + "symbol=" + strings.ToUpper(symbol.OriginalName) + "&limit=200"
@@ -156,21 +225,6 @@ func (tg *TradesGroup) mapSnapshot(data []recentTrade, symbol string) (trades []
 	return
 }
 
-// connect - creating new WS client and establishing connection
-func (tg *TradesGroup) connect() {
-	var smbls []string
-	for _, s := range tg.symbols {
-		smbls = append(smbls, strings.ToLower(s.OriginalName))
-	}
-	ws := websocket.NewClient(wsURL+strings.Join(smbls, "@aggTrade/")+"@aggTrade", tg.httpProxy)
-	tg.wsClient = ws
-	if err := tg.wsClient.Connect(); err != nil {
-		log.Println("[BINANCE] Error connecting to binance API: ", err)
-		tg.restart()
-	}
-	tg.wsClient.Listen(tg.dataCh, tg.errorCh)
-}
-
 // listen - listening to updates from WS
 func (tg *TradesGroup) listen() {
 	go func() {
@@ -185,15 +239,6 @@ func (tg *TradesGroup) listen() {
 			}
 		}
 	}()
-	go func() {
-		for err := range tg.errorCh {
-			tg.resultCh <- schemas.ResultChannel{
-				Error: err,
-			}
-			log.Println("[BINANCE] Error listening:", err)
-			tg.restart()
-		}
-	}()
 }
 
 func (tg *TradesGroup) handleUpdates(data []byte) (trades []schemas.Trade, dataType string, err error) {
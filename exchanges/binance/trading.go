@@ -7,9 +7,11 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/importer"
 	"github.com/syndicatedb/goex/internal/websocket"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
@@ -21,6 +23,11 @@ const (
 
 	balanceType   = "outboundAccountInfo"
 	executionType = "executionReport"
+
+	importLimit = 1000 // max trades in page
+
+	// codeUnknownOrder - error code of cancelling symbol without open orders
+	codeUnknownOrder = -2011
 )
 
 // TradingProvider - provides quotes/ticker
@@ -35,7 +42,10 @@ type TradingProvider struct {
 	uoc         chan schemas.UserOrdersChannel
 	utc         chan schemas.UserTradesChannel
 	ch          chan []byte
-	ech         chan error
+	expired     chan struct{}
+	stop        chan struct{}
+
+	sync.Mutex
 }
 
 // NewTradingProvider - TradingProvider constructor
@@ -49,22 +59,13 @@ func NewTradingProvider(credentials schemas.Credentials, httpProxy proxy.Provide
 		uoc:         make(chan schemas.UserOrdersChannel),
 		utc:         make(chan schemas.UserTradesChannel),
 		ch:          make(chan []byte, 400),
-		ech:         make(chan error, 400),
-	}
-	lk, err := trading.CreateListenkey(credentials.APIKey)
-	if err != nil {
-		log.Println("[BINANCE] Error creating key", err)
+		expired:     make(chan struct{}, 1),
 	}
-	trading.listenKey = lk
-
 	go func() {
-		for {
-			trading.Ping()
-			time.Sleep(30 * time.Minute)
+		for data := range trading.ch {
+			trading.handleUpdates(data)
 		}
 	}()
-	trading.wsClient = websocket.NewClient(userDataStreamURL+trading.listenKey, httpProxy)
-	// ws updates of trading data
 
 	return &trading
 }
@@ -92,72 +93,29 @@ Subscribe - subscribing to user info
 — user info
 - orders
 - trades
+Listen key is created on first call, REST snapshots are sent after every (re)connection
 */
 func (trading *TradingProvider) Subscribe(interval time.Duration) (chan schemas.UserInfoChannel, chan schemas.UserOrdersChannel, chan schemas.UserTradesChannel) {
-	// http snapshots of trading data
-	go func() {
-		ui, err := trading.Info()
-		if err != nil {
-			log.Println("[BINANCE] Balances snapshot error:", err)
-		}
-		trading.uic <- schemas.UserInfoChannel{
-			Data:     ui,
-			DataType: "s",
-			Error:    err,
-		}
-	}()
-
-	go func() {
-		o, err := trading.Orders(trading.symbols)
-		if err != nil {
-			log.Println("[BINANCE] Orders snapshot error:", err)
-		}
-		trading.uoc <- schemas.UserOrdersChannel{
-			Data:     o,
-			DataType: "s",
-			Error:    err,
-		}
-	}()
-
-	go func() {
-		t, _, err := trading.Trades(schemas.FilterOptions{Symbols: trading.symbols})
-		if err != nil {
-			log.Println("[BINANCE] Trades snapshot error:", err)
-		}
-		trading.utc <- schemas.UserTradesChannel{
-			Data:     t,
-			DataType: "s",
-			Error:    err,
-		}
-	}()
-
-	trading.wsClient.Connect()
-	trading.wsClient.ChangeKeepAlive(false)
-	trading.wsClient.Listen(trading.ch, trading.ech)
-
-	// handling ws input data
-	go func() {
-		for {
-			select {
-			case data := <-trading.ch:
-				trading.handleUpdates(data)
-			case err := <-trading.ech:
-				log.Println("[BINANCE] Error handling", err)
-				trading.uic <- schemas.UserInfoChannel{
-					Data:     schemas.UserInfo{},
-					DataType: "u",
-					Error:    err,
-				}
-			}
-		}
-	}()
+	trading.Lock()
+	defer trading.Unlock()
+	if trading.stop == nil {
+		trading.stop = make(chan struct{})
+		go trading.runUserStream(trading.stop)
+	}
 
 	return trading.uic, trading.uoc, trading.utc
 }
 
-// Unsubscribe from trading data
+// Unsubscribe from trading data, listen key is deleted
 func (trading *TradingProvider) Unsubscribe() error {
-	return trading.wsClient.Exit()
+	trading.Lock()
+	defer trading.Unlock()
+	if trading.stop == nil {
+		return errors.New("[BINANCE] Not subscribed")
+	}
+	close(trading.stop)
+	trading.stop = nil
+	return nil
 }
 
 // Info - provides user info: Keys access, balances
@@ -284,6 +242,14 @@ func (trading *TradingProvider) handleUpdates(data []byte) {
 		log.Println("[BINANCE] Unmarshalling error:", err)
 	}
 
+	if msg.EventType == listenKeyExpiredType {
+		select {
+		case trading.expired <- struct{}{}:
+		default:
+		}
+		return
+	}
+
 	if msg.EventType == balanceType {
 		var balanceMsg balanceMessage
 		err = json.Unmarshal(data, &balanceMsg)
@@ -292,8 +258,9 @@ func (trading *TradingProvider) handleUpdates(data []byte) {
 		}
 		ui := balanceMsg.Map()
 		trading.uic <- schemas.UserInfoChannel{
-			Data:  ui,
-			Error: err,
+			Data:     ui,
+			DataType: "u",
+			Error:    err,
 		}
 	}
 
@@ -307,66 +274,118 @@ func (trading *TradingProvider) handleUpdates(data []byte) {
 		if tradesMsg.CurrentExecutionType == "TRADE" {
 			t := tradesMsg.Map()
 			trading.utc <- schemas.UserTradesChannel{
-				Data:  t,
-				Error: err,
+				Data:     t,
+				DataType: "u",
+				Error:    err,
 			}
 		}
 
 		o := tradesMsg.MapOrder()
 		trading.uoc <- schemas.UserOrdersChannel{
-			Data:  o,
-			Error: err,
+			Data:     o,
+			DataType: "u",
+			Error:    err,
 		}
 	}
 }
 
-// ImportTrades importing trades
+/*
+ImportTrades - importing trades of symbols, all symbols if empty.
+Trades are paged by ID from first trade or checkpoint. Last message has DataTypeDone
+*/
 func (trading *TradingProvider) ImportTrades(opts schemas.FilterOptions) chan schemas.UserTradesChannel {
-	ch := make(chan schemas.UserTradesChannel)
 	if len(fmt.Sprintf("%d", opts.Before)) < 12 {
 		opts.Before = opts.Before * 1000
 	}
 	if len(fmt.Sprintf("%d", opts.Since)) < 12 {
 		opts.Since = opts.Since * 1000
 	}
+	symbols := opts.Symbols
+	if len(symbols) == 0 {
+		symbols = trading.symbols
+	}
+	if len(symbols) == 0 {
+		return importer.Fail(schemas.ErrEmptySymbols)
+	}
+	var tasks []importer.Task
+	for _, s := range symbols {
+		tasks = append(tasks, importer.Task{
+			Symbol: s.Name,
+			Page:   trading.tradesPage(s, opts),
+		})
+	}
+	return importer.Importer{
+		Exchange:    exchangeName,
+		APIKey:      trading.credentials.APIKey,
+		Checkpoints: opts.Checkpoints,
+		Acknowledge: opts.Acknowledge,
+		Delay:       time.Second,
+	}.Run(tasks)
+}
 
-	trades, paging, err := trading.Trades(opts)
-	opts.Page = int(paging.Pages)
-	go func() {
-		for {
-			trades, _, err := trading.Trades(opts)
-			if err != nil {
-				log.Println("[BINANCE] Error loading trades: ", err)
-				continue
+/*
+tradesPage - loading trades of symbol by ID: cursor is ID of next trade.
+Binance time filter is limited to 24 hours, so trades before Since are skipped
+*/
+func (trading *TradingProvider) tradesPage(symbol schemas.Symbol, opts schemas.FilterOptions) importer.Page {
+	return func(cursor schemas.Checkpoint) (trades []schemas.Trade, next schemas.Checkpoint, done bool, err error) {
+		var b []byte
+		var resp []UserTrade
+		var eMsg errorMsg
+
+		next = cursor
+		params := httpclient.Params()
+		params.Set("symbol", symbol.OriginalName)
+		params.Set("fromId", "0")
+		if cursor.Cursor != "" {
+			params.Set("fromId", cursor.Cursor)
+		}
+		params.Set("limit", strconv.Itoa(importLimit))
+		params.Set("timestamp", strconv.FormatInt(time.Now().UTC().UnixNano(), 10)[:13])
+
+		if b, err = trading.httpClient.Get(apiUserTrades, params, true); err != nil {
+			if e := json.Unmarshal(b, &eMsg); e == nil && eMsg.Message != "" {
+				err = errors.New(eMsg.Message)
 			}
-			ch <- schemas.UserTradesChannel{
-				Data:  trades,
-				Error: err,
+			return
+		}
+		if err = json.Unmarshal(b, &resp); err != nil {
+			return
+		}
+		var page []UserTrade
+		for _, t := range resp {
+			if opts.Before != 0 && t.Time >= opts.Before {
+				done = true
+				break
 			}
-			opts.Page = opts.Page - 1
-			if opts.Page < 1 {
-				return
+			next.Cursor = strconv.FormatInt(t.ID+1, 10)
+			next.Timestamp = t.Time
+			if t.Time >= opts.Since {
+				page = append(page, t)
 			}
-			time.Sleep(1 * time.Second)
 		}
-	}()
-	log.Printf("paging: %d, %+v, %v", len(trades), paging, err)
-
-	return ch
+		r := UserTradesResponse{
+			Trades: page,
+		}
+		return r.Map(), next, done || len(resp) < importLimit, nil
+	}
 }
 
 // Create - creating order
 func (trading *TradingProvider) Create(order schemas.Order) (result schemas.Order, err error) {
 	var b []byte
 	var eMsg errorMsg
+	if order, err = schemas.PrepareOrder(trading.symbols, order); err != nil {
+		return
+	}
 	query := httpclient.Params()
 
 	query.Set("symbol", unparseSymbol(order.Symbol))
 	query.Set("type", "LIMIT")
 	query.Set("timeInForce", "GTC")
 	query.Set("side", strings.ToUpper(order.Type))
-	query.Set("price", strconv.FormatFloat(order.Price, 'f', -1, 64))
-	query.Set("quantity", strconv.FormatFloat(order.Amount, 'f', -1, 64))
+	query.Set("price", order.PriceValue())
+	query.Set("quantity", order.AmountValue())
 	query.Set("timestamp", strconv.FormatInt(time.Now().UnixNano(), 10)[:13])
 
 	b, err = trading.httpClient.Post(apiCreateOrder, query, httpclient.KeyValue{}, true)
@@ -403,6 +422,10 @@ func (trading *TradingProvider) Create(order schemas.Order) (result schemas.Orde
 		Count:        1,
 		CreatedAt:    resp.Time,
 		Remove:       0,
+
+		PriceDecimal:        schemas.Decimal(resp.Price),
+		AmountDecimal:       schemas.Decimal(resp.OriginalQuantity),
+		AmountFilledDecimal: schemas.Decimal(resp.ExecQuantity),
 	}
 	return
 }
@@ -444,3 +467,116 @@ func (trading *TradingProvider) CancelAll() (err error) {
 	}
 	return
 }
+
+/*
+CancelAllBy - cancelling orders by symbols and side.
+Without side native cancel of all symbol open orders is used,
+otherwise symbol orders are loaded and cancelled one by one.
+Every symbol is cancelled, errors are returned as schemas.SymbolsError
+*/
+func (trading *TradingProvider) CancelAllBy(symbols []schemas.Symbol, side string) (cancelled []schemas.Order, err error) {
+	if len(symbols) == 0 {
+		return nil, schemas.ErrEmptySymbols
+	}
+	errs := make(schemas.SymbolsError)
+	for _, s := range symbols {
+		var orders []schemas.Order
+		var serr error
+		if side == "" {
+			orders, serr = trading.cancelBySymbol(s)
+		} else {
+			orders, serr = trading.cancelBySide(s, side)
+		}
+		cancelled = append(cancelled, orders...)
+		if serr != nil {
+			errs[s.Name] = serr
+		}
+	}
+	if len(errs) > 0 {
+		err = errs
+	}
+	return
+}
+
+func (trading *TradingProvider) cancelBySymbol(symbol schemas.Symbol) (orders []schemas.Order, err error) {
+	var b []byte
+	var resp []activeOrder
+	var eMsg errorMsg
+
+	query := httpclient.Params()
+	query.Set("symbol", symbol.OriginalName)
+	query.Set("timestamp", strconv.FormatInt(time.Now().UnixNano(), 10)[:13])
+
+	b, err = trading.httpClient.Request("DELETE", apiCancelAll, query, httpclient.Params(), true)
+	if err != nil {
+		if e := json.Unmarshal(b, &eMsg); e != nil {
+			return
+		}
+		// symbol without open orders
+		if eMsg.Code == codeUnknownOrder {
+			return nil, nil
+		}
+		err = errors.New(eMsg.Message)
+		return
+	}
+	if err = json.Unmarshal(b, &resp); err != nil {
+		return
+	}
+	r := UserOrdersResponse{
+		Orders: resp,
+	}
+	for _, o := range r.Map() {
+		o.Symbol = symbol.Name
+		o.Status = schemas.StatusCancelled
+		orders = append(orders, o)
+	}
+	return
+}
+
+func (trading *TradingProvider) cancelBySide(symbol schemas.Symbol, side string) (orders []schemas.Order, err error) {
+	var open []schemas.Order
+	if open, err = trading.ordersBySymbol(symbol); err != nil {
+		return
+	}
+	for _, o := range open {
+		if !o.IsSide(side) {
+			continue
+		}
+		if err = trading.Cancel(o); err != nil {
+			return
+		}
+		o.Status = schemas.StatusCancelled
+		orders = append(orders, o)
+	}
+	return
+}
+
+// ordersBySymbol - getting user active orders of one symbol
+func (trading *TradingProvider) ordersBySymbol(symbol schemas.Symbol) (orders []schemas.Order, err error) {
+	var b []byte
+	var resp []activeOrder
+	var eMsg errorMsg
+	params := httpclient.Params()
+	params.Set("symbol", symbol.OriginalName)
+	params.Set("timestamp", strconv.FormatInt(time.Now().UTC().UnixNano(), 10)[:13])
+
+	b, err = trading.httpClient.Get(apiActiveOrders, params, true)
+	if err != nil {
+		if e := json.Unmarshal(b, &eMsg); e != nil {
+			return
+		}
+		err = errors.New(eMsg.Message)
+		return
+	}
+	if err = json.Unmarshal(b, &resp); err != nil {
+		return
+	}
+	r := UserOrdersResponse{
+		Orders: resp,
+	}
+	for _, o := range r.Map() {
+		o.Symbol = symbol.Name
+		orders = append(orders, o)
+	}
+	return
+}
@@ -28,6 +28,7 @@ const (
 	apiNewOrder    = "https://api.bitfinex.com/v1/order/new"
 	apiCancelOrder = "https://api.bitfinex.com/v1/order/cancel"
 	apiCancelAll   = "https://api.bitfinex.com/v1/order/cancel/all"
+	apiCancelMulti = "https://api.bitfinex.com/v1/order/cancel/multi"
 
 	apiURL = "https://api.bitfinex.com"
 	wsURL  = "wss://api.bitfinex.com/ws/2"
@@ -41,6 +42,13 @@ const (
 	quotesSymbolsLimit    = 10
 )
 
+// Bitfinex base fees and amount precision, symbols_details doesn't contain them
+const (
+	defaultMakerFee = 0.001
+	defaultTakerFee = 0.002
+	amountPrecision = 8
+)
+
 const (
 	dataTypeSnapshot = "s"
 	dataTypeUpdate   = "u"
@@ -59,7 +67,7 @@ func New(opts schemas.Options) *Bitfinex {
 	}
 
 	opts.Credentials.Sign = signV1
-	return &Bitfinex{
+	bitfinex := &Bitfinex{
 		Exchange: schemas.Exchange{
 			Credentials:   opts.Credentials,
 			ProxyProvider: proxyProvider,
@@ -68,9 +76,14 @@ func New(opts schemas.Options) *Bitfinex {
 			Trades:        NewTradesProvider(proxyProvider),
 			Quotes:        NewQuotesProvider(proxyProvider),
 			Candles:       NewCandlesProvider(proxyProvider),
-			Trading:       NewTradingProvider(opts.Credentials, proxyProvider),
 		},
 	}
+	symbols, err := bitfinex.SymbolProvider().Get()
+	if err != nil {
+		log.Println("Error getting symbols", err)
+	}
+	bitfinex.Trading = NewTradingProvider(opts.Credentials, proxyProvider).SetSymbols(symbols)
+	return bitfinex
 }
 
 func parseSymbol(smb string) (name, basecoin, quoteCoin string) {
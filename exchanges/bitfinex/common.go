@@ -138,6 +138,11 @@ type cancelAllResponse struct {
 	Result string `json:"result"`
 }
 
+// cancelMultiResponse represents response model on cancelling orders by IDs
+type cancelMultiResponse struct {
+	Result string `json:"result"`
+}
+
 func int64Value(v interface{}) int64 {
 	if f, ok := v.(float64); ok {
 		return int64(f)
@@ -147,3 +152,10 @@ func int64Value(v interface{}) int64 {
 	}
 	return 0
 }
+
+func floatValue(v interface{}) float64 {
+	if f, ok := v.(float64); ok {
+		return f
+	}
+	return 0
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -12,64 +13,87 @@ import (
 // OrdersProvider - order book provider structure
 type OrdersProvider struct {
 	httpProxy proxy.Provider
-	symbols   []schemas.Symbol
-	books     []*OrderBookGroup
-
+	options   BookOptions
 	sync.Mutex
+	groups *listing.Groups
 }
 
 // NewOrdersProvider - OrdersProvider constructor
 func NewOrdersProvider(httpProxy proxy.Provider) *OrdersProvider {
-	return &OrdersProvider{
+	ob := &OrdersProvider{
 		httpProxy: httpProxy,
+		options:   DefaultBookOptions,
 	}
+	ob.groups = listing.NewGroups(orderBookSymbolsLimit, ob.startGroup)
+	return ob
 }
 
-// SetSymbols - setting symbols and creating groups by symbols chunks
+// SetSymbols - replacing symbols, they are split into groups by symbols chunks on subscription.
+// Symbols of running subscription are changed by AddSymbols and RemoveSymbols
 func (ob *OrdersProvider) SetSymbols(symbols []schemas.Symbol) schemas.OrdersProvider {
-	slice := make([]schemas.Symbol, len(symbols))
-	copy(slice, symbols)
-	capacity := orderBookSymbolsLimit
-	for {
-		if len(slice) <= capacity {
-			ob.books = append(
-				ob.books,
-				NewOrderBookGroup(slice, ob.httpProxy),
-			)
-			break
-		}
-		ob.books = append(
-			ob.books,
-			NewOrderBookGroup(slice[0:capacity], ob.httpProxy),
-		)
+	ob.groups.Reset(symbols)
+	return ob
+}
+
+// SubscribeAll - subscribing all groups
+func (ob *OrdersProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return ob.groups.Subscribe(d)
+}
+
+// AddSymbols - adding symbols to running subscription, i.e. new listings
+func (ob *OrdersProvider) AddSymbols(symbols []schemas.Symbol) {
+	ob.groups.Add(symbols)
+}
+
+// RemoveSymbols - removing symbols from subscription, i.e. delistings.
+// Groups subscribed to them are reconnected without them
+func (ob *OrdersProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	ob.groups.Remove(symbols)
+}
 
-		slice = slice[capacity:]
+// startGroup - creating and starting group of symbols
+func (ob *OrdersProvider) startGroup(symbols []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) listing.Group {
+	ob.Lock()
+	options := ob.options
+	ob.Unlock()
+	group := NewOrderBookGroupWithOptions(symbols, options, ob.httpProxy)
+	go group.Start(ch)
+	return group
+}
+
+// SetBookOptions - setting book options of groups, has to be called before SubscribeAll
+func (ob *OrdersProvider) SetBookOptions(options BookOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
 	}
-	return ob
+	ob.Lock()
+	defer ob.Unlock()
+	ob.options = options
+	return nil
 }
 
 // Subscribe - subscribing to quote by one symbol
 func (ob *OrdersProvider) Subscribe(symbol schemas.Symbol, d time.Duration) (r chan schemas.ResultChannel) {
 	ch := make(chan schemas.ResultChannel)
-	group := NewOrderBookGroup([]schemas.Symbol{symbol}, ob.httpProxy)
+	group := NewOrderBookGroupWithOptions([]schemas.Symbol{symbol}, ob.options, ob.httpProxy)
 	go group.Start(ch)
 	return ch
 }
 
-// SubscribeAll - subscribing all groups
-func (ob *OrdersProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
-	ch := make(chan schemas.ResultChannel)
-
-	for _, orderBook := range ob.books {
-		go orderBook.Start(ch)
-		time.Sleep(100 * time.Millisecond)
+// SubscribeWithOptions - subscribing to one symbol book with own options, i.e. raw book
+func (ob *OrdersProvider) SubscribeWithOptions(symbol schemas.Symbol, options BookOptions) (chan schemas.ResultChannel, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
 	}
-	return ch
+	ch := make(chan schemas.ResultChannel)
+	group := NewOrderBookGroupWithOptions([]schemas.Symbol{symbol}, options, ob.httpProxy)
+	go group.Start(ch)
+	return ch, nil
 }
 
 // Get - getting orderbook snapshot by symbol
 func (ob *OrdersProvider) Get(symbol schemas.Symbol) (book schemas.OrderBook, err error) {
-	group := NewOrderBookGroup([]schemas.Symbol{symbol}, ob.httpProxy)
+	group := NewOrderBookGroupWithOptions([]schemas.Symbol{symbol}, ob.options, ob.httpProxy)
 	d, err := group.Get()
 	if err != nil {
 		return
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -18,28 +20,98 @@ import (
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
+// Book precisions: P0-P4 are aggregated price levels (from 5 to 1 significant digits), R0 is raw orders book
+const (
+	PrecisionP0  = "P0"
+	PrecisionP1  = "P1"
+	PrecisionP2  = "P2"
+	PrecisionP3  = "P3"
+	PrecisionP4  = "P4"
+	PrecisionRaw = "R0"
+)
+
+// Book update frequencies: realtime or every 2 seconds
+const (
+	FrequencyRealtime = "F0"
+	FrequencySlow     = "F1"
+)
+
+// Book lengths: price levels (or orders for raw book) by side
+const (
+	Length25  = "25"
+	Length100 = "100"
+)
+
+// BookOptions - order book subscription options
+type BookOptions struct {
+	Precision string
+	Frequency string
+	Length    string
+}
+
+// DefaultBookOptions - aggregated realtime book of 100 levels
+var DefaultBookOptions = BookOptions{
+	Precision: PrecisionP0,
+	Frequency: FrequencyRealtime,
+	Length:    Length100,
+}
+
+// Validate - checking options are supported by bitfinex
+func (o BookOptions) Validate() error {
+	switch o.Precision {
+	case PrecisionP0, PrecisionP1, PrecisionP2, PrecisionP3, PrecisionP4, PrecisionRaw:
+	default:
+		return fmt.Errorf("[BITFINEX] Unsupported book precision: %s", o.Precision)
+	}
+	if o.Frequency != FrequencyRealtime && o.Frequency != FrequencySlow {
+		return fmt.Errorf("[BITFINEX] Unsupported book frequency: %s", o.Frequency)
+	}
+	if o.Length != Length25 && o.Length != Length100 {
+		return fmt.Errorf("[BITFINEX] Unsupported book length: %s", o.Length)
+	}
+	return nil
+}
+
+// IsRaw - checking book contains individual orders
+func (o BookOptions) IsRaw() bool {
+	return o.Precision == PrecisionRaw
+}
+
 // OrderBookGroup - order book group structure
 type OrderBookGroup struct {
 	symbols []schemas.Symbol
+	options BookOptions
 
 	wsClient   *websocket.Client
 	httpClient *httpclient.Client
 	httpProxy  proxy.Provider
 	subs       map[int64]event
 	bus        bus
+	stop       chan struct{}
+
+	// raw book orders by symbol and order ID
+	raw map[string]map[int64]rawOrder
 
 	sync.RWMutex
 }
 
-// NewOrderBookGroup - OrderBookGroup constructor
+// NewOrderBookGroup - OrderBookGroup constructor with default book options
 func NewOrderBookGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *OrderBookGroup {
+	return NewOrderBookGroupWithOptions(symbols, DefaultBookOptions, httpProxy)
+}
+
+// NewOrderBookGroupWithOptions - OrderBookGroup constructor, options have to be validated
+func NewOrderBookGroupWithOptions(symbols []schemas.Symbol, options BookOptions, httpProxy proxy.Provider) *OrderBookGroup {
 	proxyClient := httpProxy.NewClient(exchangeName)
 
 	return &OrderBookGroup{
 		symbols:    symbols,
+		options:    options,
 		httpProxy:  httpProxy,
 		httpClient: httpclient.New(proxyClient),
 		subs:       make(map[int64]event),
+		stop:       make(chan struct{}),
+		raw:        make(map[string]map[int64]rawOrder),
 		bus: bus{
 			dch: make(chan []byte, 2*len(symbols)),
 			ech: make(chan error, 2*len(symbols)),
@@ -58,16 +130,18 @@ func (ob *OrderBookGroup) Get() (books []schemas.OrderBook, err error) {
 	}
 
 	for _, smb := range ob.symbols {
-		url := apiOrderBook + "/" + "t" + unparseSymbol(smb.Name) + "/P0"
+		url := apiOrderBook + "/" + "t" + unparseSymbol(smb.Name) + "/" + ob.options.Precision
+		params := httpclient.Params()
+		params.Set("len", ob.options.Length)
 
-		if b, err = ob.httpClient.Get(url, httpclient.Params(), false); err != nil {
+		if b, err = ob.httpClient.Get(url, params, false); err != nil {
 			return
 		}
 		if err = json.Unmarshal(b, &resp); err != nil {
 			return
 		}
 		if bks, ok := resp.([]interface{}); ok {
-			books = append(books, ob.mapOrderBook("t"+unparseSymbol(smb.Name), bks))
+			books = append(books, ob.mapOrderBook("t"+unparseSymbol(smb.Name), bks, true))
 		}
 
 		time.Sleep(2 * time.Second)
@@ -90,15 +164,44 @@ func (ob *OrderBookGroup) Start(ch chan schemas.ResultChannel) {
 // need for restarting group after error.
 func (ob *OrderBookGroup) restart() {
 	time.Sleep(5 * time.Second)
+	if ob.stopped() {
+		return
+	}
 	if err := ob.wsClient.Exit(); err != nil {
 		log.Println("[BITFINEX] Error destroying connection: ", err)
 	}
 	ob.Start(ob.bus.outChannel)
 }
 
+// Stop - closing connection, group isn't restarted
+func (ob *OrderBookGroup) Stop() {
+	close(ob.stop)
+	ob.Lock()
+	ws := ob.wsClient
+	ob.Unlock()
+	if ws == nil {
+		return
+	}
+	if err := ws.Exit(); err != nil {
+		log.Println("[BITFINEX] Error destroying connection: ", err)
+	}
+}
+
+// stopped - checking group has been stopped
+func (ob *OrderBookGroup) stopped() bool {
+	select {
+	case <-ob.stop:
+		return true
+	default:
+		return false
+	}
+}
+
 // connect - creating new WS client and establishing connection
 func (ob *OrderBookGroup) connect() {
+	ob.Lock()
 	ob.wsClient = websocket.NewClient(wsURL, ob.httpProxy)
+	ob.Unlock()
 	if err := ob.wsClient.Connect(); err != nil {
 		log.Println("[BITFINEX] Error connecting to bitfinex API: ", err)
 		ob.restart()
@@ -114,9 +217,9 @@ func (ob *OrderBookGroup) subscribe() {
 			Event:     eventSubscribe,
 			Channel:   "book",
 			Symbol:    "t" + unparseSymbol(s.Name),
-			Precision: "P0",
-			Frequency: "F0",
-			Length:    "100",
+			Precision: ob.options.Precision,
+			Frequency: ob.options.Frequency,
+			Length:    ob.options.Length,
 		}
 
 		if err := ob.wsClient.Write(message); err != nil {
@@ -132,7 +235,11 @@ func (ob *OrderBookGroup) subscribe() {
 func (ob *OrderBookGroup) collectSnapshots() {
 	go func() {
 		for {
-			time.Sleep(snapshotInterval)
+			select {
+			case <-ob.stop:
+				return
+			case <-time.After(snapshotInterval):
+			}
 
 			data, err := ob.Get()
 			if err != nil {
@@ -245,13 +352,13 @@ func (ob *OrderBookGroup) handleMessage(msg []byte) {
 		if _, ok := v[0].([]interface{}); ok {
 			// handlung snapshot
 			orders, dataType := ob.mapSnapshot(e.Symbol, v)
-			go ob.publish(orders, dataType, nil)
+			ob.publish(orders, dataType, nil)
 			return
 		}
 
 		// handlng update
-		orders := ob.mapOrderBook(e.Symbol, []interface{}{v})
-		go ob.publish(orders, "u", nil)
+		orders := ob.mapOrderBook(e.Symbol, []interface{}{v}, false)
+		ob.publish(orders, "u", nil)
 		return
 	}
 
@@ -262,12 +369,15 @@ func (ob *OrderBookGroup) handleMessage(msg []byte) {
 // handleSnapshot - handling snapshot message
 func (ob *OrderBookGroup) mapSnapshot(symbol string, data []interface{}) (orders schemas.OrderBook, datatype string) {
 	datatype = "s"
-	orders = ob.mapOrderBook(symbol, data)
+	orders = ob.mapOrderBook(symbol, data, true)
 	return
 }
 
 // mapOrderBook - mapping incoming books message into commot OrderBook model
-func (ob *OrderBookGroup) mapOrderBook(symbol string, raw []interface{}) schemas.OrderBook {
+func (ob *OrderBookGroup) mapOrderBook(symbol string, raw []interface{}, snapshot bool) schemas.OrderBook {
+	if ob.options.IsRaw() {
+		return ob.mapRawOrderBook(symbol, raw, snapshot)
+	}
 	// log.Println("SYMBOL", smb)
 	smb, _, _ := parseSymbol(symbol)
 	orderBook := schemas.OrderBook{
@@ -300,6 +410,124 @@ func (ob *OrderBookGroup) mapOrderBook(symbol string, raw []interface{}) schemas
 	return orderBook
 }
 
+/*
+mapRawOrderBook - mapping raw book message: [ORDER_ID, PRICE, AMOUNT] into price levels.
+Orders are kept by ID, so published book is price keyed like aggregated one: level amount is sum
+of its orders and count is number of them. Snapshot replaces symbol orders, update returns
+changed levels only. Zero price means order is removed, its level is found by kept order
+*/
+func (ob *OrderBookGroup) mapRawOrderBook(symbol string, raw []interface{}, snapshot bool) schemas.OrderBook {
+	smb, _, _ := parseSymbol(symbol)
+
+	ob.Lock()
+	defer ob.Unlock()
+	orders := ob.raw[smb]
+	if snapshot || orders == nil {
+		orders = make(map[int64]rawOrder)
+		ob.raw[smb] = orders
+	}
+	changed := make(map[rawLevel]bool)
+	for i := range raw {
+		o, ok := raw[i].([]interface{})
+		if !ok || len(o) < 3 {
+			continue
+		}
+		id := int64Value(o[0])
+		if prev, ok := orders[id]; ok {
+			changed[prev.level()] = true
+			delete(orders, id)
+		}
+		order := rawOrder{price: o[1].(float64), amount: o[2].(float64)}
+		if order.price == 0 {
+			continue
+		}
+		orders[id] = order
+		changed[order.level()] = true
+	}
+
+	orderBook := schemas.OrderBook{
+		Symbol: smb,
+	}
+	levels := aggregateRaw(orders)
+	if !snapshot {
+		for level := range changed {
+			if _, ok := levels[level]; !ok {
+				levels[level] = schemas.Order{Price: level.price, Remove: 1}
+			}
+		}
+		for level := range levels {
+			if !changed[level] {
+				delete(levels, level)
+			}
+		}
+	}
+	for level, ordr := range levels {
+		ordr.Symbol = smb
+		if level.buy {
+			orderBook.Buy = append(orderBook.Buy, ordr)
+		} else {
+			orderBook.Sell = append(orderBook.Sell, ordr)
+		}
+	}
+	sort.Slice(orderBook.Buy, func(i, j int) bool { return orderBook.Buy[i].Price > orderBook.Buy[j].Price })
+	sort.Slice(orderBook.Sell, func(i, j int) bool { return orderBook.Sell[i].Price < orderBook.Sell[j].Price })
+	return orderBook
+}
+
+// Orders - current orders of raw book by symbol with bitfinex order IDs, empty for aggregated book
+func (ob *OrderBookGroup) Orders(symbol string) (orders []schemas.Order) {
+	ob.RLock()
+	defer ob.RUnlock()
+	for id, o := range ob.raw[symbol] {
+		orders = append(orders, schemas.Order{
+			ID:     strconv.FormatInt(id, 10),
+			Symbol: symbol,
+			Type:   o.side(),
+			Price:  o.price,
+			Amount: math.Abs(o.amount),
+			Count:  1,
+		})
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+	return
+}
+
+// rawOrder - order of raw book, amount sign is the side
+type rawOrder struct {
+	price  float64
+	amount float64
+}
+
+// rawLevel - price level of raw book side
+type rawLevel struct {
+	price float64
+	buy   bool
+}
+
+func (o rawOrder) level() rawLevel {
+	return rawLevel{price: o.price, buy: o.amount > 0}
+}
+
+func (o rawOrder) side() string {
+	if o.amount > 0 {
+		return schemas.TypeBuy
+	}
+	return schemas.TypeSell
+}
+
+// aggregateRaw - summing raw orders into price levels
+func aggregateRaw(orders map[int64]rawOrder) map[rawLevel]schemas.Order {
+	levels := make(map[rawLevel]schemas.Order)
+	for _, o := range orders {
+		l := levels[o.level()]
+		l.Price = o.price
+		l.Amount += math.Abs(o.amount)
+		l.Count++
+		levels[o.level()] = l
+	}
+	return levels
+}
+
 // add - adding channel info with it's ID.
 // Need for matching symbol with channel ID.
 func (ob *OrderBookGroup) add(e event) {
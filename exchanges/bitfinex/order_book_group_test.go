@@ -0,0 +1,74 @@
+package bitfinex
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+func TestMapRawOrderBook(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  []interface{}
+		snapshot bool
+		want     schemas.OrderBook
+		orders   int
+	}{
+		{
+			name: "snapshot orders are summed by price",
+			message: []interface{}{
+				[]interface{}{1.0, 100.0, 1.0},
+				[]interface{}{2.0, 100.0, 2.0},
+				[]interface{}{3.0, 101.0, -0.5},
+			},
+			snapshot: true,
+			want: schemas.OrderBook{
+				Symbol: "BTC-USD",
+				Buy:    []schemas.Order{{Symbol: "BTC-USD", Price: 100, Amount: 3, Count: 2}},
+				Sell:   []schemas.Order{{Symbol: "BTC-USD", Price: 101, Amount: 0.5, Count: 1}},
+			},
+			orders: 3,
+		},
+		{
+			name:    "removed order reduces its level",
+			message: []interface{}{[]interface{}{1.0, 0.0, 1.0}},
+			want: schemas.OrderBook{
+				Symbol: "BTC-USD",
+				Buy:    []schemas.Order{{Symbol: "BTC-USD", Price: 100, Amount: 2, Count: 1}},
+			},
+			orders: 2,
+		},
+		{
+			name:    "last removed order removes level",
+			message: []interface{}{[]interface{}{3.0, 0.0, -1.0}},
+			want: schemas.OrderBook{
+				Symbol: "BTC-USD",
+				Sell:   []schemas.Order{{Symbol: "BTC-USD", Price: 101, Remove: 1}},
+			},
+			orders: 1,
+		},
+		{
+			name:    "changed order amount updates level",
+			message: []interface{}{[]interface{}{2.0, 100.0, 5.0}},
+			want: schemas.OrderBook{
+				Symbol: "BTC-USD",
+				Buy:    []schemas.Order{{Symbol: "BTC-USD", Price: 100, Amount: 5, Count: 1}},
+			},
+			orders: 1,
+		},
+	}
+	ob := &OrderBookGroup{
+		options: BookOptions{Precision: PrecisionRaw},
+		raw:     make(map[string]map[int64]rawOrder),
+	}
+	for _, tt := range tests {
+		book := ob.mapOrderBook("tBTCUSD", tt.message, tt.snapshot)
+		if !reflect.DeepEqual(book, tt.want) {
+			t.Errorf("%s: book = %+v, want %+v", tt.name, book, tt.want)
+		}
+		if orders := ob.Orders("BTC-USD"); len(orders) != tt.orders {
+			t.Errorf("%s: orders = %d, want %d", tt.name, len(orders), tt.orders)
+		}
+	}
+}
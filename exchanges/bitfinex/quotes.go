@@ -1,9 +1,9 @@
 package bitfinex
 
 import (
-	"sync"
 	"time"
 
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -11,42 +11,48 @@ import (
 // QuotesProvider - quotes provider structure
 type QuotesProvider struct {
 	httpProxy proxy.Provider
-	symbols   []schemas.Symbol
-	groups    []*QuotesGroup
-
-	sync.Mutex
+	groups    *listing.Groups
 }
 
 // NewQuotesProvider - QuotesProvider constructor
 func NewQuotesProvider(httpProxy proxy.Provider) *QuotesProvider {
-	return &QuotesProvider{
+	qp := &QuotesProvider{
 		httpProxy: httpProxy,
 	}
+	qp.groups = listing.NewGroups(orderBookSymbolsLimit, qp.startGroup)
+	return qp
 }
 
-// SetSymbols - setting symbols and creating groups by symbols chunks
+// SetSymbols - replacing symbols, they are split into groups by symbols chunks on subscription.
+// Symbols of running subscription are changed by AddSymbols and RemoveSymbols
 func (qp *QuotesProvider) SetSymbols(symbols []schemas.Symbol) schemas.QuotesProvider {
-	slice := make([]schemas.Symbol, len(symbols))
-	copy(slice, symbols)
-	capacity := orderBookSymbolsLimit
-	for {
-		if len(slice) <= capacity {
-			qp.groups = append(
-				qp.groups,
-				NewQuotesGroup(slice, qp.httpProxy),
-			)
-			break
-		}
-		qp.groups = append(
-			qp.groups,
-			NewQuotesGroup(slice[0:capacity], qp.httpProxy),
-		)
-
-		slice = slice[capacity:]
-	}
+	qp.groups.Reset(symbols)
 	return qp
 }
 
+// SubscribeAll - subscribing all groups
+func (qp *QuotesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return qp.groups.Subscribe(d)
+}
+
+// AddSymbols - adding symbols to running subscription, i.e. new listings
+func (qp *QuotesProvider) AddSymbols(symbols []schemas.Symbol) {
+	qp.groups.Add(symbols)
+}
+
+// RemoveSymbols - removing symbols from subscription, i.e. delistings.
+// Groups subscribed to them are reconnected without them
+func (qp *QuotesProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	qp.groups.Remove(symbols)
+}
+
+// startGroup - creating and starting group of symbols
+func (qp *QuotesProvider) startGroup(symbols []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) listing.Group {
+	group := NewQuotesGroup(symbols, qp.httpProxy)
+	go group.Start(ch)
+	return group
+}
+
 // Get - getting quotes by symbol
 func (qp *QuotesProvider) Get(symbol schemas.Symbol) (q schemas.Quote, err error) {
 	group := NewQuotesGroup([]schemas.Symbol{symbol}, qp.httpProxy)
@@ -60,14 +66,3 @@ func (qp *QuotesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan
 	go group.Start(ch)
 	return ch
 }
-
-// SubscribeAll - subscribing to all quotes with interval
-func (qp *QuotesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
-	ch := make(chan schemas.ResultChannel)
-
-	for _, group := range qp.groups {
-		go group.Start(ch)
-		time.Sleep(100 * time.Millisecond)
-	}
-	return ch
-}
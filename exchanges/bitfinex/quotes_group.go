@@ -25,6 +25,7 @@ type QuotesGroup struct {
 	httpProxy  proxy.Provider
 	subs       map[int64]event
 	bus        bus
+	stop       chan struct{}
 
 	sync.RWMutex
 }
@@ -44,6 +45,7 @@ func NewQuotesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *QuotesG
 		httpProxy:  httpProxy,
 		httpClient: httpclient.New(proxyClient),
 		subs:       make(map[int64]event),
+		stop:       make(chan struct{}),
 		bus: bus{
 			dch: make(chan []byte, 2*len(symbols)),
 			ech: make(chan error, 2*len(symbols)),
@@ -91,15 +93,44 @@ func (q *QuotesGroup) Start(ch chan schemas.ResultChannel) {
 // need for restarting group after error.
 func (q *QuotesGroup) restart() {
 	time.Sleep(5 * time.Second)
+	if q.stopped() {
+		return
+	}
 	if err := q.wsClient.Exit(); err != nil {
 		log.Println("[BITFINEX] Error destroying connection: ", err)
 	}
 	q.Start(q.bus.outChannel)
 }
 
+// Stop - closing connection, group isn't restarted
+func (q *QuotesGroup) Stop() {
+	close(q.stop)
+	q.Lock()
+	ws := q.wsClient
+	q.Unlock()
+	if ws == nil {
+		return
+	}
+	if err := ws.Exit(); err != nil {
+		log.Println("[BITFINEX] Error destroying connection: ", err)
+	}
+}
+
+// stopped - checking group has been stopped
+func (q *QuotesGroup) stopped() bool {
+	select {
+	case <-q.stop:
+		return true
+	default:
+		return false
+	}
+}
+
 // connect - creating new WS client and establishing connection
 func (q *QuotesGroup) connect() {
+	q.Lock()
 	q.wsClient = websocket.NewClient(wsURL, q.httpProxy)
+	q.Unlock()
 	if err := q.wsClient.Connect(); err != nil {
 		log.Println("[BITFINEX] Error connecting to bitfinex API: ", err)
 		return
@@ -3,10 +3,12 @@ package bitfinex
 import (
 	"encoding/json"
 	"log"
+	"math"
 	"strconv"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -27,6 +29,16 @@ type Symbol struct {
 	Expiration     string `json:"expiration"`
 }
 
+// orderTypes - Bitfinex exchange order types mapped to common
+var orderTypes = []string{
+	schemas.OrderTypeLimit,
+	schemas.OrderTypeMarket,
+	schemas.OrderTypeStopLoss,
+	schemas.OrderTypeStopLossLimit,
+	schemas.OrderTypeTrailingStop,
+	schemas.OrderTypeFillOrKill,
+}
+
 // NewSymbolsProvider - SymbolsProvider constructor
 func NewSymbolsProvider(httpProxy proxy.Provider) *SymbolsProvider {
 	log.Println("Constructing symbols provider")
@@ -49,19 +61,27 @@ func (sp *SymbolsProvider) Get() (symbols []schemas.Symbol, err error) {
 
 	for _, smb := range resp {
 		name, baseCoin, quoteCoin := parseSymbol(smb.Pair)
-		minPrice, _ := strconv.ParseFloat(smb.MinOrderSize, 64)
-		maxPrice, _ := strconv.ParseFloat(smb.MaxOrderSize, 64)
-		minAmount, _ := strconv.ParseFloat(smb.MinMargin, 64)
+		minAmount, _ := strconv.ParseFloat(smb.MinOrderSize, 64)
+		maxAmount, _ := strconv.ParseFloat(smb.MaxOrderSize, 64)
 
+		// symbols_details returns only active pairs.
+		// Price has no fixed tick: precision is significant digits, not decimals
 		symbols = append(symbols, schemas.Symbol{
-			Name:           name,
-			OriginalName:   smb.Pair,
-			Coin:           quoteCoin,
-			BaseCoin:       baseCoin,
-			MinPrice:       minPrice,
-			MaxPrice:       maxPrice,
-			MinAmount:      minAmount,
-			PricePrecision: int(smb.PricePrecision),
+			Name:            name,
+			OriginalName:    smb.Pair,
+			Coin:            quoteCoin,
+			BaseCoin:        baseCoin,
+			Status:          schemas.SymbolStatusTrading,
+			OrderTypes:      orderTypes,
+			Fee:             defaultTakerFee,
+			MakerFee:        defaultMakerFee,
+			TakerFee:        defaultTakerFee,
+			DefaultFees:     true,
+			MinAmount:       minAmount,
+			MaxAmount:       maxAmount,
+			LotStep:         math.Pow10(-amountPrecision),
+			PricePrecision:  int(smb.PricePrecision),
+			AmountPrecision: amountPrecision,
 		})
 	}
 
@@ -84,3 +104,8 @@ func (sp *SymbolsProvider) Subscribe(d time.Duration) chan schemas.ResultChannel
 	}()
 	return ch
 }
+
+// SubscribeChanges - subscribing to symbols changes: listings, delistings, status and filters changes
+func (sp *SymbolsProvider) SubscribeChanges(d time.Duration) chan schemas.SymbolEventsChannel {
+	return listing.Watch(sp.Get, d)
+}
@@ -29,6 +29,7 @@ type TradesGroup struct {
 	httpProxy  proxy.Provider
 	subs       map[int64]event
 	bus        bus
+	stop       chan struct{}
 
 	sync.RWMutex
 }
@@ -42,6 +43,7 @@ func NewTradesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *TradesG
 		httpProxy:  httpProxy,
 		httpClient: httpclient.New(proxyClient),
 		subs:       make(map[int64]event),
+		stop:       make(chan struct{}),
 		bus: bus{
 			dch: make(chan []byte, 2*len(symbols)),
 			ech: make(chan error, 2*len(symbols)),
@@ -97,15 +99,44 @@ func (tg *TradesGroup) Start(ch chan schemas.ResultChannel) {
 // need for restarting group after error.
 func (tg *TradesGroup) restart() {
 	time.Sleep(5 * time.Second)
+	if tg.stopped() {
+		return
+	}
 	if err := tg.wsClient.Exit(); err != nil {
 		log.Println("[BITFINEX] Error destroying connection: ", err)
 	}
 	tg.Start(tg.bus.outChannel)
 }
 
+// Stop - closing connection, group isn't restarted
+func (tg *TradesGroup) Stop() {
+	close(tg.stop)
+	tg.Lock()
+	ws := tg.wsClient
+	tg.Unlock()
+	if ws == nil {
+		return
+	}
+	if err := ws.Exit(); err != nil {
+		log.Println("[BITFINEX] Error destroying connection: ", err)
+	}
+}
+
+// stopped - checking group has been stopped
+func (tg *TradesGroup) stopped() bool {
+	select {
+	case <-tg.stop:
+		return true
+	default:
+		return false
+	}
+}
+
 // connect - creating new WS client and establishing connection
 func (tg *TradesGroup) connect() {
+	tg.Lock()
 	tg.wsClient = websocket.NewClient(wsURL, tg.httpProxy)
+	tg.Unlock()
 	if err := tg.wsClient.Connect(); err != nil {
 		log.Println("[BITFINEX] Error connecting to bitfinex API: ", err)
 		return
@@ -134,7 +165,11 @@ func (tg *TradesGroup) subscribe() {
 func (tg *TradesGroup) collectSnapshots() {
 	go func() {
 		for {
-			time.Sleep(snapshotInterval)
+			select {
+			case <-tg.stop:
+				return
+			case <-time.After(snapshotInterval):
+			}
 
 			data, err := tg.Get()
 			if err != nil {
@@ -8,11 +8,13 @@ import (
 	"log"
 	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/importer"
 	"github.com/syndicatedb/goex/internal/websocket"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
@@ -40,6 +42,8 @@ const (
 	cancelAllStatus = "All orders cancelled"
 )
 
+const importLimit = 1000 // max trades in history response
+
 // TradingProvider represents bitfinex trading provider structure
 type TradingProvider struct {
 	credentials schemas.Credentials
@@ -94,49 +98,83 @@ func (trading *TradingProvider) Unsubscribe() error {
 	return trading.wsClient.Exit()
 }
 
-// Info stub method
+/*
+Info loading wallets balances, margin positions and funding offers.
+Balances contain exchange wallet, every wallet type is in Wallets
+*/
 func (trading *TradingProvider) Info() (ui schemas.UserInfo, err error) {
-	var b []byte
 	var resp []interface{}
 
-	payload := make(map[string]interface{})
-	bodyBytes, err := json.Marshal(payload)
-	if err != nil {
+	if resp, err = trading.authRead("/v2/auth/r/wallets"); err != nil {
 		return
 	}
+	wallets := trading.mapWallets(resp)
 
-	path := "/v2/auth/r/wallets"
-	req, err := http.NewRequest("POST", apiURL+path, bytes.NewBuffer(bodyBytes))
+	access, err := trading.getAccessInfo()
 	if err != nil {
 		return
 	}
-	signedReq := signV2(trading.credentials.APIKey, trading.credentials.APISecret, path, req)
-	b, err = trading.httpClient.Do(signedReq)
+
+	prices, err := trading.prices()
 	if err != nil {
+		log.Println("Error getting prices for symbols", err)
+	}
+	positions, err := trading.Positions()
+	if err != nil {
+		log.Println("[BITFINEX] Error getting positions", err)
+	}
+	offers, err := trading.FundingOffers()
+	if err != nil {
+		log.Println("[BITFINEX] Error getting funding offers", err)
+	}
+
+	ui = schemas.UserInfo{
+		Access:        access,
+		Balances:      wallets[schemas.WalletExchange],
+		Prices:        prices,
+		Wallets:       wallets,
+		Positions:     positions,
+		FundingOffers: offers,
+	}
+
+	return ui, nil
+}
+
+// Positions loading active margin positions
+func (trading *TradingProvider) Positions() (positions []schemas.Position, err error) {
+	var resp []interface{}
+	if resp, err = trading.authRead("/v2/auth/r/positions"); err != nil {
 		return
 	}
-	if err = json.Unmarshal(b, &resp); err != nil {
+	return trading.mapPositions(resp), nil
+}
+
+// FundingOffers loading active funding offers of all coins
+func (trading *TradingProvider) FundingOffers() (offers []schemas.FundingOffer, err error) {
+	var resp []interface{}
+	if resp, err = trading.authRead("/v2/auth/r/funding/offers"); err != nil {
 		return
 	}
+	return trading.mapFundingOffers(resp), nil
+}
 
-	balances := trading.mapBalance(resp)
+// authRead sending signed v2 request with empty body
+func (trading *TradingProvider) authRead(path string) (resp []interface{}, err error) {
+	var b []byte
 
-	access, err := trading.getAccessInfo()
+	bodyBytes, err := json.Marshal(map[string]interface{}{})
 	if err != nil {
 		return
 	}
-
-	prices, err := trading.prices()
+	req, err := http.NewRequest("POST", apiURL+path, bytes.NewBuffer(bodyBytes))
 	if err != nil {
-		log.Println("Error getting prices for symbols", err)
+		return
 	}
-
-	ui = schemas.UserInfo{
-		Access:   access,
-		Balances: balances,
-		Prices:   prices,
+	signedReq := signV2(trading.credentials.APIKey, trading.credentials.APISecret, path, req)
+	if b, err = trading.httpClient.Do(signedReq); err != nil {
+		return
 	}
-
+	err = json.Unmarshal(b, &resp)
 	return
 }
 
@@ -254,10 +292,125 @@ func (trading *TradingProvider) Trades(opts schemas.FilterOptions) (trades []sch
 	return
 }
 
-// ImportTrades stub method
+/*
+ImportTrades - importing trades of symbols, of all symbols if empty.
+Trades are paged by time from Since or checkpoint till Before or import start.
+Last message has DataTypeDone
+*/
 func (trading *TradingProvider) ImportTrades(opts schemas.FilterOptions) chan schemas.UserTradesChannel {
-	ch := make(chan schemas.UserTradesChannel)
-	return ch
+	if len(fmt.Sprintf("%d", opts.Before)) < 12 {
+		opts.Before = opts.Before * 1000
+	}
+	if len(fmt.Sprintf("%d", opts.Since)) < 12 {
+		opts.Since = opts.Since * 1000
+	}
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	if opts.Before == 0 || opts.Before > now {
+		opts.Before = now
+	}
+	start := schemas.Checkpoint{Cursor: fmt.Sprintf("%d", opts.Since)}
+
+	tasks := []importer.Task{{
+		Symbol: "all",
+		Start:  start,
+		Page:   trading.tradesPage("", opts.Before),
+	}}
+	if len(opts.Symbols) > 0 {
+		tasks = nil
+		for _, s := range opts.Symbols {
+			tasks = append(tasks, importer.Task{
+				Symbol: s.Name,
+				Start:  start,
+				Page:   trading.tradesPage("t"+strings.ToUpper(s.OriginalName), opts.Before),
+			})
+		}
+	}
+	return importer.Importer{
+		Exchange:    exchangeName,
+		APIKey:      trading.credentials.APIKey,
+		Checkpoints: opts.Checkpoints,
+		Acknowledge: opts.Acknowledge,
+		Delay:       time.Second,
+	}.Run(tasks)
+}
+
+/*
+tradesPage - loading trades from time in ascending order, of all symbols if symbol is empty.
+Cursor is time and ID of last trade, i.e. "1546300800000:123": next page starts from its time,
+so trades of the same millisecond are not lost, and trades up to its ID are skipped
+*/
+func (trading *TradingProvider) tradesPage(symbol string, end int64) importer.Page {
+	return func(cursor schemas.Checkpoint) (trades []schemas.Trade, next schemas.Checkpoint, done bool, err error) {
+		var from, lastID int64
+		parts := strings.SplitN(cursor.Cursor, ":", 2)
+		if from, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+			return
+		}
+		if len(parts) == 2 {
+			if lastID, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+				return
+			}
+		}
+		var resp []interface{}
+		if resp, err = trading.tradesHistory(symbol, from, end); err != nil {
+			return
+		}
+		page := trading.mapTrades(resp)
+		ids := make(map[string]int64, len(page))
+		for _, t := range page {
+			ids[t.ID], _ = strconv.ParseInt(t.ID, 10, 64)
+		}
+		sort.SliceStable(page, func(i, j int) bool {
+			if page[i].Timestamp != page[j].Timestamp {
+				return page[i].Timestamp < page[j].Timestamp
+			}
+			return ids[page[i].ID] < ids[page[j].ID]
+		})
+		next = cursor
+		for _, t := range page {
+			if t.Timestamp < from || (t.Timestamp == from && ids[t.ID] <= lastID) {
+				continue
+			}
+			trades = append(trades, t)
+			next.Cursor = fmt.Sprintf("%d:%d", t.Timestamp, ids[t.ID])
+			next.Timestamp = t.Timestamp
+		}
+		done = len(resp) < importLimit
+		if !done && len(trades) == 0 {
+			log.Printf("[BITFINEX] More than %d trades in millisecond %d, rest of them is not imported\n", importLimit, from)
+			done = true
+		}
+		return
+	}
+}
+
+// tradesHistory - trades from start till end in milliseconds, oldest first
+func (trading *TradingProvider) tradesHistory(symbol string, start, end int64) (resp []interface{}, err error) {
+	bodyBytes, err := json.Marshal(make(map[string]interface{}))
+	if err != nil {
+		return
+	}
+	path := "/v2/auth/r/trades/hist"
+	if symbol != "" {
+		path = "/v2/auth/r/trades/" + symbol + "/hist"
+	}
+	req, err := http.NewRequest("POST", apiURL+path, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return
+	}
+	query := req.URL.Query()
+	query.Add("start", fmt.Sprintf("%d", start))
+	query.Add("end", fmt.Sprintf("%d", end))
+	query.Add("limit", fmt.Sprintf("%d", importLimit))
+	query.Add("sort", "1")
+	req.URL.RawQuery = query.Encode()
+
+	b, err := trading.httpClient.Do(signV2(trading.credentials.APIKey, trading.credentials.APISecret, path, req))
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(b, &resp)
+	return
 }
 
 // Create stub method
@@ -266,6 +419,10 @@ func (trading *TradingProvider) Create(order schemas.Order) (result schemas.Orde
 	var orderType string
 	var resp newOrderResponse
 
+	if order, err = trading.prepareOrder(order); err != nil {
+		return
+	}
+
 	symbol := unparseSymbol(order.Symbol)
 	if strings.ToUpper(order.Type) == schemas.TypeBuy {
 		orderType = "buy"
@@ -281,8 +438,8 @@ func (trading *TradingProvider) Create(order schemas.Order) (result schemas.Orde
 		"request": "/v1/order/new",
 		"nonce":   nonce,
 		"symbol":  symbol,
-		"amount":  strconv.FormatFloat(order.Amount, 'f', -1, 64),
-		"price":   strconv.FormatFloat(order.Price, 'f', -1, 64),
+		"amount":  order.AmountValue(),
+		"price":   order.PriceValue(),
 		"side":    orderType,
 		"type":    "exchange limit", // TODO: add type to order model, handle it here
 	}
@@ -333,6 +490,9 @@ func (trading *TradingProvider) Create(order schemas.Order) (result schemas.Orde
 		Amount:    amount,
 		CreatedAt: tms,
 		Status:    status,
+
+		PriceDecimal:  schemas.Decimal(resp.Price),
+		AmountDecimal: schemas.Decimal(resp.OriginalAmount),
 	}
 
 	return
@@ -413,6 +573,114 @@ func (trading *TradingProvider) CancelAll() (err error) {
 	return
 }
 
+/*
+CancelAllBy cancelling orders by symbols and side.
+Active orders are loaded at once and cancelled
+with single multi cancel request
+*/
+func (trading *TradingProvider) CancelAllBy(symbols []schemas.Symbol, side string) (cancelled []schemas.Order, err error) {
+	var b []byte
+	var resp cancelMultiResponse
+	var orders []schemas.Order
+
+	if len(symbols) == 0 {
+		return nil, schemas.ErrEmptySymbols
+	}
+	if orders, err = trading.activeOrders(); err != nil {
+		return
+	}
+	orders = schemas.FilterOrders(orders, symbols, side)
+	if len(orders) == 0 {
+		return
+	}
+
+	var ids []int64
+	for _, o := range orders {
+		id, _ := strconv.ParseInt(o.ID, 10, 64)
+		ids = append(ids, id)
+	}
+
+	nonce := fmt.Sprintf("%v", time.Now().UnixNano()/1000)
+	payload := map[string]interface{}{
+		"request":   "/v1/order/cancel/multi",
+		"nonce":     nonce,
+		"order_ids": ids,
+	}
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest("POST", apiCancelMulti, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return
+	}
+	signedReq := signV1(trading.credentials.APIKey, trading.credentials.APISecret, req)
+	b, err = trading.httpClient.Do(signedReq)
+	if err != nil {
+		err = fmt.Errorf(errCancelOrder, string(b))
+		return
+	}
+	if err = json.Unmarshal(b, &resp); err != nil {
+		return
+	}
+
+	for _, o := range orders {
+		o.Status = schemas.StatusCancelled
+		cancelled = append(cancelled, o)
+	}
+	return
+}
+
+/*
+prepareOrder validating order against symbol.
+Bitfinex price precision is count of significant digits,
+so it's converted to decimals for order price before validation
+*/
+func (trading *TradingProvider) prepareOrder(order schemas.Order) (schemas.Order, error) {
+	for _, s := range trading.symbols {
+		if s.Name != order.Symbol && s.OriginalName != order.Symbol {
+			continue
+		}
+		if s.PricePrecision > 0 && order.Price > 0 {
+			integer := int(math.Floor(math.Log10(order.Price))) + 1
+			s.PricePrecision = s.PricePrecision - integer
+			if s.PricePrecision < 0 {
+				s.PricePrecision = 0
+			}
+		}
+		return s.ValidateOrder(order)
+	}
+	return order, schemas.ValidationError{Symbol: order.Symbol, Reason: schemas.ReasonUnknown}
+}
+
+// activeOrders loading all active orders of account
+func (trading *TradingProvider) activeOrders() (orders []schemas.Order, err error) {
+	var b []byte
+	var resp []interface{}
+
+	payload := make(map[string]interface{})
+	bodyBytes, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	path := "/v2/auth/r/orders"
+	req, err := http.NewRequest("POST", apiURL+path, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return
+	}
+	signedReq := signV2(trading.credentials.APIKey, trading.credentials.APISecret, path, req)
+	b, err = trading.httpClient.Do(signedReq)
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(b, &resp); err != nil {
+		return
+	}
+
+	return trading.mapOrders(resp), nil
+}
+
 func (trading *TradingProvider) subscribe() {
 	dch := make(chan []byte, 100)
 	ech := make(chan error, 100)
@@ -532,7 +800,7 @@ func (trading *TradingProvider) handleUpdates(msg []interface{}) {
 	updType := msg[1]
 
 	if updType == "ws" {
-		b := trading.mapBalance(msg[2].([]interface{}))
+		w := trading.mapWallets(msg[2].([]interface{}))
 		access, err := trading.getAccessInfo()
 		if err != nil {
 			trading.publishErr(err)
@@ -548,14 +816,15 @@ func (trading *TradingProvider) handleUpdates(msg []interface{}) {
 			DataType: dataTypeSnapshot,
 			Data: schemas.UserInfo{
 				Access:   access,
-				Balances: b,
+				Balances: w[schemas.WalletExchange],
 				Prices:   prices,
+				Wallets:  w,
 			},
 		}
 	}
 	if updType == "wu" {
 		wslice := []interface{}{msg[2]}
-		b := trading.mapBalance(wslice)
+		w := trading.mapWallets(wslice)
 		access, err := trading.getAccessInfo()
 		if err != nil {
 			trading.publishErr(err)
@@ -566,7 +835,42 @@ func (trading *TradingProvider) handleUpdates(msg []interface{}) {
 			DataType: dataTypeUpdate,
 			Data: schemas.UserInfo{
 				Access:   access,
-				Balances: b,
+				Balances: w[schemas.WalletExchange],
+				Wallets:  w,
+			},
+		}
+	}
+	// full list, it's sent as update: user info snapshot has wallets, so it replaces balances
+	if updType == "ps" {
+		trading.bus.uic <- schemas.UserInfoChannel{
+			DataType: dataTypeUpdate,
+			Data: schemas.UserInfo{
+				Positions: trading.mapPositions(msg[2].([]interface{})),
+			},
+		}
+	}
+	if updType == "pn" || updType == "pu" || updType == "pc" {
+		trading.bus.uic <- schemas.UserInfoChannel{
+			DataType: dataTypeUpdate,
+			Data: schemas.UserInfo{
+				Positions: trading.mapPositions([]interface{}{msg[2]}),
+			},
+		}
+	}
+	// full list, it's sent as update: user info snapshot has wallets, so it replaces balances
+	if updType == "fos" {
+		trading.bus.uic <- schemas.UserInfoChannel{
+			DataType: dataTypeUpdate,
+			Data: schemas.UserInfo{
+				FundingOffers: trading.mapFundingOffers(msg[2].([]interface{})),
+			},
+		}
+	}
+	if updType == "fon" || updType == "fou" || updType == "foc" {
+		trading.bus.uic <- schemas.UserInfoChannel{
+			DataType: dataTypeUpdate,
+			Data: schemas.UserInfo{
+				FundingOffers: trading.mapFundingOffers([]interface{}{msg[2]}),
 			},
 		}
 	}
@@ -668,11 +972,16 @@ func (trading *TradingProvider) publishErr(err error) {
 	}()
 }
 
-func (trading *TradingProvider) mapBalance(msg []interface{}) map[string]schemas.Balance {
-	sb := make(map[string]schemas.Balance)
+// mapWallets mapping wallets message: [WALLET_TYPE, CURRENCY, BALANCE, UNSETTLED_INTEREST, BALANCE_AVAILABLE]
+func (trading *TradingProvider) mapWallets(msg []interface{}) map[string]map[string]schemas.Balance {
+	wallets := make(map[string]map[string]schemas.Balance)
 
 	for i := range msg {
 		if wal, ok := msg[i].([]interface{}); ok {
+			walletType, _ := wal[0].(string)
+			if _, ok := wallets[walletType]; !ok {
+				wallets[walletType] = make(map[string]schemas.Balance)
+			}
 			b := schemas.Balance{
 				Coin:  wal[1].(string),
 				Total: wal[2].(float64),
@@ -688,11 +997,76 @@ func (trading *TradingProvider) mapBalance(msg []interface{}) map[string]schemas
 				b.InOrders = b.Total - b.Available
 			}
 
-			sb[wal[1].(string)] = b
+			wallets[walletType][wal[1].(string)] = b
 		}
 	}
+	if _, ok := wallets[schemas.WalletExchange]; !ok {
+		wallets[schemas.WalletExchange] = make(map[string]schemas.Balance)
+	}
 
-	return sb
+	return wallets
+}
+
+/*
+mapPositions mapping positions message:
+[SYMBOL, STATUS, AMOUNT, BASE_PRICE, MARGIN_FUNDING, MARGIN_FUNDING_TYPE,
+PL, PL_PERC, PRICE_LIQ, LEVERAGE, _, POSITION_ID, MTS_CREATE, MTS_UPDATE, ...]
+*/
+func (trading *TradingProvider) mapPositions(msg []interface{}) (positions []schemas.Position) {
+	for i := range msg {
+		p, ok := msg[i].([]interface{})
+		if !ok || len(p) < 10 {
+			continue
+		}
+		symbol, _ := p[0].(string)
+		name, _, _ := parseSymbol(symbol)
+		status, _ := p[1].(string)
+		position := schemas.Position{
+			Symbol:           name,
+			Status:           status,
+			Amount:           floatValue(p[2]),
+			BasePrice:        floatValue(p[3]),
+			MarginFunding:    floatValue(p[4]),
+			ProfitLoss:       floatValue(p[6]),
+			ProfitLossRate:   floatValue(p[7]),
+			LiquidationPrice: floatValue(p[8]),
+			Leverage:         floatValue(p[9]),
+		}
+		if len(p) > 13 {
+			position.ID = strconv.FormatInt(int64Value(p[11]), 10)
+			position.CreatedAt = int64Value(p[12])
+			position.UpdatedAt = int64Value(p[13])
+		}
+		positions = append(positions, position)
+	}
+	return
+}
+
+/*
+mapFundingOffers mapping funding offers message:
+[ID, SYMBOL, MTS_CREATED, MTS_UPDATED, AMOUNT, AMOUNT_ORIG, TYPE, _, _, FLAGS, STATUS, _, _, _, RATE, PERIOD, ...]
+*/
+func (trading *TradingProvider) mapFundingOffers(msg []interface{}) (offers []schemas.FundingOffer) {
+	for i := range msg {
+		o, ok := msg[i].([]interface{})
+		if !ok || len(o) < 16 {
+			continue
+		}
+		symbol, _ := o[1].(string)
+		status, _ := o[10].(string)
+		offers = append(offers, schemas.FundingOffer{
+			ID:             strconv.FormatInt(int64Value(o[0]), 10),
+			Coin:           strings.TrimPrefix(symbol, "f"),
+			Status:         status,
+			Amount:         floatValue(o[4]),
+			AmountOriginal: floatValue(o[5]),
+			Rate:           floatValue(o[14]),
+			Period:         int(int64Value(o[15])),
+			CreatedAt:      int64Value(o[2]),
+			UpdatedAt:      int64Value(o[3]),
+		})
+	}
+	return
 }
 
 func (trading *TradingProvider) mapOrders(msg []interface{}) (orders []schemas.Order) {
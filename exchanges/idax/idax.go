@@ -60,7 +60,7 @@ func New(opts schemas.Options) *IDAX {
 	if opts.API != "" {
 		apiHost = opts.API
 	}
-	return &IDAX{
+	idax := &IDAX{
 		Exchange: schemas.Exchange{
 			Credentials:   opts.Credentials,
 			ProxyProvider: proxyProvider,
@@ -69,9 +69,14 @@ func New(opts schemas.Options) *IDAX {
 			Quotes:        NewQuotesProvider(proxyProvider),
 			Trades:        NewTradesProvider(proxyProvider),
 			Candles:       NewCandlesProvider(proxyProvider),
-			Trading:       NewTradingProvider(opts.Credentials, proxyProvider),
 		},
 	}
+	symbols, err := idax.SymbolProvider().Get()
+	if err != nil {
+		log.Println("Error getting symbols", err)
+	}
+	idax.Trading = NewTradingProvider(opts.Credentials, proxyProvider).SetSymbols(symbols)
+	return idax
 }
 
 func parseSymbol(s string) (name, coin, baseCoin string) {
@@ -247,3 +247,118 @@ type OrdersCreateResponse struct {
 		Funds    map[string]float64 `json:"funds"`    // "eth":325
 	} `json:"return"`
 }
+
+/*
+wsDepth - websocket depth data, first message of channel has all levels
+and next ones have changed levels only, removed levels have zero amount
+
+	{"asks":[["0.0307","12.5"]],"bids":[["0.0306","0"]],"timestamp":1531088906000}
+*/
+type wsDepth struct {
+	Asks      [][]string `json:"asks"`
+	Bids      [][]string `json:"bids"`
+	Timestamp int64      `json:"timestamp"`
+}
+
+// Map - mapping websocket depth to common order book
+func (d wsDepth) Map(symbol string) schemas.OrderBook {
+	return schemas.OrderBook{
+		Symbol: symbol,
+		Buy:    mapWsLevels(symbol, schemas.TypeBuy, d.Bids),
+		Sell:   mapWsLevels(symbol, schemas.TypeSell, d.Asks),
+	}
+}
+
+func mapWsLevels(symbol, side string, levels [][]string) (orders []schemas.Order) {
+	for _, l := range levels {
+		if len(l) < 2 {
+			continue
+		}
+		price, _ := strconv.ParseFloat(l[0], 64)
+		amount, _ := strconv.ParseFloat(l[1], 64)
+		order := schemas.Order{
+			Symbol: symbol,
+			Type:   side,
+			Price:  price,
+			Amount: amount,
+			Count:  1,
+		}
+		if amount == 0 {
+			order.Count = 0
+			order.Remove = 1
+		}
+		orders = append(orders, order)
+	}
+	return
+}
+
+/*
+wsTrade - websocket trade: ID, price, amount, timestamp in ms and taker side
+
+	["21490692","0.0721605","0.18422595",1531088906000,"buy"]
+*/
+type wsTrade []interface{}
+
+// Map - mapping websocket trade to common
+func (t wsTrade) Map(symbol string) (trade schemas.Trade, ok bool) {
+	if len(t) < 5 {
+		return
+	}
+	trade = schemas.Trade{
+		ID:        fmt.Sprintf("%v", t[0]),
+		Symbol:    symbol,
+		Type:      strings.ToLower(fmt.Sprintf("%v", t[4])),
+		Price:     wsFloat(t[1]),
+		Amount:    wsFloat(t[2]),
+		Timestamp: int64(wsFloat(t[3])),
+	}
+	return trade, true
+}
+
+/*
+wsTicker - websocket ticker data
+
+	{"open":"0.0301","high":"0.0312","low":"0.0298","close":"0.0307","vol":"1520.3","quoteVol":"46.7","change":"1.99","timestamp":1531088906000}
+*/
+type wsTicker struct {
+	Open     string `json:"open"`
+	High     string `json:"high"`
+	Low      string `json:"low"`
+	Close    string `json:"close"`
+	Vol      string `json:"vol"`
+	QuoteVol string `json:"quoteVol"`
+	Change   string `json:"change"`
+}
+
+// Map - mapping websocket ticker to common quote
+func (t wsTicker) Map(symbol string) schemas.Quote {
+	open, _ := strconv.ParseFloat(t.Open, 64)
+	price, _ := strconv.ParseFloat(t.Close, 64)
+	high, _ := strconv.ParseFloat(t.High, 64)
+	low, _ := strconv.ParseFloat(t.Low, 64)
+	vol, _ := strconv.ParseFloat(t.Vol, 64)
+	quoteVol, _ := strconv.ParseFloat(t.QuoteVol, 64)
+	change, _ := strconv.ParseFloat(t.Change, 64)
+	return schemas.Quote{
+		Symbol:      symbol,
+		Price:       price,
+		High:        high,
+		Low:         low,
+		ChangeValue: price - open,
+		ChangeRate:  change,
+		VolumeBase:  vol,
+		Volume:      quoteVol,
+	}
+}
+
+// wsFloat - websocket value sent either as number or as string
+func wsFloat(v interface{}) float64 {
+	switch value := v.(type) {
+	case float64:
+		return value
+	case string:
+		f, _ := strconv.ParseFloat(value, 64)
+		return f
+	}
+	return 0
+}
@@ -5,34 +5,61 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"sync"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
 // OrdersProvider - order book provider
 type OrdersProvider struct {
+	httpProxy  proxy.Provider
 	httpClient *httpclient.Client
-	symbols    []schemas.Symbol
-	sync.Mutex
+	groups     *listing.Groups
 }
 
 // NewOrdersProvider - OrdersProvider constructor
 func NewOrdersProvider(httpProxy proxy.Provider) *OrdersProvider {
-	return &OrdersProvider{
+	ob := &OrdersProvider{
+		httpProxy:  httpProxy,
 		httpClient: httpclient.New(httpProxy.NewClient(exchangeName)),
 	}
+	ob.groups = listing.NewGroups(orderBookSymbolsLimit, ob.startGroup)
+	return ob
 }
 
-// SetSymbols - getting all symbols from Exchange
+// SetSymbols - replacing symbols, they are split into groups by symbols chunks on subscription.
+// Symbols of running subscription are changed by AddSymbols and RemoveSymbols
 func (ob *OrdersProvider) SetSymbols(symbols []schemas.Symbol) schemas.OrdersProvider {
-	ob.symbols = symbols
+	ob.groups.Reset(symbols)
 	return ob
 }
 
+// SubscribeAll - subscribing all groups
+func (ob *OrdersProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return ob.groups.Subscribe(d)
+}
+
+// AddSymbols - adding symbols to running subscription, i.e. new listings
+func (ob *OrdersProvider) AddSymbols(symbols []schemas.Symbol) {
+	ob.groups.Add(symbols)
+}
+
+// RemoveSymbols - removing symbols from subscription, i.e. delistings.
+// Groups subscribed to them are restarted without them
+func (ob *OrdersProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	ob.groups.Remove(symbols)
+}
+
+// startGroup - creating and starting group of symbols
+func (ob *OrdersProvider) startGroup(symbols []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) listing.Group {
+	group := NewOrderBookGroup(symbols, ob.httpProxy)
+	go group.Start(ch)
+	return group
+}
+
 // Get - getting all symbols from Exchange
 func (ob *OrdersProvider) Get(symbol schemas.Symbol) (book schemas.OrderBook, err error) {
 	var b []byte
@@ -75,35 +102,10 @@ func (ob *OrdersProvider) Get(symbol schemas.Symbol) (book schemas.OrderBook, er
 	return
 }
 
-// Subscribe - getting all symbols from Exchange
+// Subscribe - subscribing to symbol depth, snapshot is sent first and changed levels next
 func (ob *OrdersProvider) Subscribe(symbol schemas.Symbol, d time.Duration) (ch chan schemas.ResultChannel) {
 	ch = make(chan schemas.ResultChannel, 100)
-	go ob.subscribe(symbol, d, ch)
+	group := NewOrderBookGroup([]schemas.Symbol{symbol}, ob.httpProxy)
+	go group.Start(ch)
 	return ch
 }
-
-// SubscribeAll - getting all symbols from Exchange
-func (ob *OrdersProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
-	bufLength := 2 * len(ob.symbols)
-	ch := make(chan schemas.ResultChannel, bufLength)
-
-	for _, symbol := range ob.symbols {
-		go ob.subscribe(symbol, d, ch)
-		time.Sleep(100 * time.Millisecond)
-	}
-	return ch
-}
-
-// subscribe - getting all symbols from Exchange
-func (ob *OrdersProvider) subscribe(symbol schemas.Symbol, d time.Duration, ch chan schemas.ResultChannel) {
-	go func() {
-		for {
-			book, err := ob.Get(symbol)
-			ch <- schemas.ResultChannel{
-				Data:  book,
-				Error: err,
-			}
-			time.Sleep(d)
-		}
-	}()
-}
@@ -0,0 +1,75 @@
+package idax
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/syndicatedb/goex/schemas"
+	"github.com/syndicatedb/goproxy/proxy"
+)
+
+// OrderBookGroup - order book group, depth by websocket
+type OrderBookGroup struct {
+	symbols []schemas.Symbol
+	stream  *stream
+
+	// symbols with snapshot received on current connection
+	received map[string]bool
+	sync.Mutex
+
+	resultCh chan schemas.ResultChannel
+}
+
+// NewOrderBookGroup - OrderBookGroup constructor
+func NewOrderBookGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *OrderBookGroup {
+	var pairs []string
+	for _, s := range symbols {
+		pairs = append(pairs, symbolToPair(s.Name))
+	}
+	return &OrderBookGroup{
+		symbols:  symbols,
+		stream:   newStream(pairs, channelDepth, httpProxy),
+		received: make(map[string]bool),
+	}
+}
+
+// Start - subscribing to depth, snapshot of every symbol is sent after every connection
+func (ob *OrderBookGroup) Start(ch chan schemas.ResultChannel) {
+	ob.resultCh = ch
+	ob.stream.onConnect = ob.reset
+	ob.stream.Start(ob.handleUpdate)
+}
+
+// Stop - closing group connection
+func (ob *OrderBookGroup) Stop() {
+	ob.stream.Stop()
+}
+
+// reset - forgetting snapshots of previous connection, first depth of new one is a snapshot
+func (ob *OrderBookGroup) reset() {
+	ob.Lock()
+	ob.received = make(map[string]bool)
+	ob.Unlock()
+}
+
+func (ob *OrderBookGroup) handleUpdate(symbol string, msg wsMessage) {
+	var depth wsDepth
+	if err := json.Unmarshal(msg.Data, &depth); err != nil {
+		log.Println("[IDAX] Error parsing depth: ", err)
+		return
+	}
+	ob.Lock()
+	snapshot := !ob.received[symbol]
+	ob.received[symbol] = true
+	ob.Unlock()
+
+	dataType := schemas.DataTypeUpdate
+	if snapshot {
+		dataType = schemas.DataTypeSnapshot
+	}
+	ob.resultCh <- schemas.ResultChannel{
+		DataType: dataType,
+		Data:     depth.Map(symbol),
+	}
+}
@@ -3,7 +3,8 @@ package idax
 import (
 	"encoding/json"
 	"errors"
-	"time"
+	"log"
+	"sync"
 
 	"github.com/syndicatedb/goex/internal/http"
 	"github.com/syndicatedb/goex/internal/state"
@@ -11,43 +12,74 @@ import (
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
-// QuotesGroup - group of quotes to group requests
+// QuotesGroup - group of quotes, ticker by websocket
 type QuotesGroup struct {
 	symbols    []schemas.Symbol
 	httpClient *httpclient.Client
 	data       *state.State
+	stream     *stream
+
+	// symbols with quote received on current connection
+	received map[string]bool
+	sync.Mutex
+
+	resultCh chan schemas.ResultChannel
 }
 
 // NewQuotesGroup - OrderBook constructor
 func NewQuotesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *QuotesGroup {
 	proxyClient := httpProxy.NewClient(exchangeName)
 
+	var pairs []string
+	for _, s := range symbols {
+		pairs = append(pairs, symbolToPair(s.Name))
+	}
 	return &QuotesGroup{
 		symbols:    symbols,
 		httpClient: httpclient.New(proxyClient),
 		data:       state.New(),
+		stream:     newStream(pairs, channelTicker, httpProxy),
+		received:   make(map[string]bool),
 	}
 }
 
-// SubscribeAll - getting all symbols from Exchange
-func (q *QuotesGroup) subscribe(ch chan schemas.ResultChannel, d time.Duration) {
-	for {
-		quotes, err := q.Get()
-		if err != nil {
-			ch <- schemas.ResultChannel{
-				Data:     quotes,
-				Error:    err,
-				DataType: "s",
-			}
-		}
-		for _, b := range quotes {
-			ch <- schemas.ResultChannel{
-				Data:     b,
-				Error:    err,
-				DataType: "s",
-			}
-		}
-		time.Sleep(d)
+// Start - subscribing to ticker, first quote of symbol on every connection is sent as snapshot
+func (q *QuotesGroup) Start(ch chan schemas.ResultChannel) {
+	q.resultCh = ch
+	q.stream.onConnect = q.reset
+	q.stream.Start(q.handleUpdate)
+}
+
+// Stop - closing group connection
+func (q *QuotesGroup) Stop() {
+	q.stream.Stop()
+}
+
+// reset - forgetting quotes of previous connection
+func (q *QuotesGroup) reset() {
+	q.Lock()
+	q.received = make(map[string]bool)
+	q.Unlock()
+}
+
+func (q *QuotesGroup) handleUpdate(symbol string, msg wsMessage) {
+	var ticker wsTicker
+	if err := json.Unmarshal(msg.Data, &ticker); err != nil {
+		log.Println("[IDAX] Error parsing ticker: ", err)
+		return
+	}
+	q.Lock()
+	snapshot := !q.received[symbol]
+	q.received[symbol] = true
+	q.Unlock()
+
+	dataType := schemas.DataTypeUpdate
+	if snapshot {
+		dataType = schemas.DataTypeSnapshot
+	}
+	q.resultCh <- schemas.ResultChannel{
+		DataType: dataType,
+		Data:     ticker.Map(symbol),
 	}
 }
 
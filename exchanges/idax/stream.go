@@ -0,0 +1,201 @@
+package idax
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/syndicatedb/goex/internal/websocket"
+	"github.com/syndicatedb/goproxy/proxy"
+)
+
+const (
+	wsURL       = "wss://openws.idax.pro/ws"
+	pingMessage = `{"event":"ping"}`
+	pingTimeout = 30 * time.Second
+
+	// codeSuccess - code of data messages, other codes are errors
+	codeSuccess = "00000"
+
+	reconnectDelay    = time.Second
+	maxReconnectDelay = time.Minute
+)
+
+// Websocket channel suffixes, channel name is idax_sub_{pair}_{suffix}, e.g. idax_sub_eth_btc_depth
+const (
+	channelDepth  = "depth"
+	channelTrades = "trades"
+	channelTicker = "ticker"
+)
+
+/*
+wsMessage - websocket message, both directions
+
+	{"event":"addChannel","channel":"idax_sub_eth_btc_depth"}
+	{"channel":"idax_sub_eth_btc_depth","code":"00000","data":{...}}
+*/
+type wsMessage struct {
+	Event   string          `json:"event,omitempty"`
+	Channel string          `json:"channel,omitempty"`
+	Code    string          `json:"code,omitempty"`
+	Message string          `json:"msg,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+/*
+stream - websocket connection to channels of symbols.
+Channels are subscribed after every connection, failed connections are retried with backoff
+*/
+type stream struct {
+	channels  []string
+	httpProxy proxy.Provider
+	wsClient  *websocket.Client
+
+	handler   func(symbol string, msg wsMessage)
+	onConnect func()
+
+	dch  chan []byte
+	stop chan struct{}
+}
+
+// newStream - stream of symbols channels with suffix
+func newStream(pairs []string, suffix string, httpProxy proxy.Provider) *stream {
+	var channels []string
+	for _, pair := range pairs {
+		channels = append(channels, channelName(pair, suffix))
+	}
+	return &stream{
+		channels:  channels,
+		httpProxy: httpProxy,
+		dch:       make(chan []byte, 100),
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start - connecting and sending channels data to handler, blocks while stream is running
+func (s *stream) Start(handler func(symbol string, msg wsMessage)) {
+	s.handler = handler
+	s.listen()
+	s.run()
+}
+
+// Stop - closing connection, stream isn't reconnected
+func (s *stream) Stop() {
+	close(s.stop)
+}
+
+/*
+run - reconnect loop: connecting and waiting for connection error.
+Delay before reconnect is doubled after every failure up to maxReconnectDelay
+and reset when connection has been working longer than maxReconnectDelay
+*/
+func (s *stream) run() {
+	wait := reconnectDelay
+	for {
+		started := time.Now()
+		ech, err := s.connect()
+		if err == nil {
+			select {
+			case err = <-ech:
+			case <-s.stop:
+				s.disconnect()
+				return
+			}
+		}
+		if time.Since(started) > maxReconnectDelay {
+			wait = reconnectDelay
+		}
+		log.Printf("[IDAX] Stream error, reconnecting in %v: %v\n", wait, err)
+		s.disconnect()
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(wait):
+		}
+		if wait *= 2; wait > maxReconnectDelay {
+			wait = maxReconnectDelay
+		}
+	}
+}
+
+// disconnect - closing current connection
+func (s *stream) disconnect() {
+	if s.wsClient == nil {
+		return
+	}
+	if err := s.wsClient.Exit(); err != nil {
+		log.Println("[IDAX] Error destroying connection: ", err)
+	}
+}
+
+/*
+connect - connecting and subscribing to channels.
+Returns errors channel of connection, first error means connection is broken
+*/
+func (s *stream) connect() (ech chan error, err error) {
+	s.wsClient = websocket.NewClient(wsURL, s.httpProxy)
+	s.wsClient.UsePingMessage(pingMessage).SetKeepAliveTimeout(pingTimeout).ChangeKeepAlive(true)
+	if err = s.wsClient.Connect(); err != nil {
+		return nil, fmt.Errorf("connecting to IDAX WS API: %v", err)
+	}
+	// errors channel by connection: errors of closed connection don't restart new one
+	ech = make(chan error, 2)
+	// data of previous connection is forgotten before new subscription
+	if s.onConnect != nil {
+		s.onConnect()
+	}
+	s.wsClient.Listen(s.dch, ech)
+
+	for _, channel := range s.channels {
+		msg := wsMessage{
+			Event:   "addChannel",
+			Channel: channel,
+		}
+		if err = s.wsClient.Write(msg); err != nil {
+			return nil, fmt.Errorf("subscribing to %s: %v", channel, err)
+		}
+	}
+	return ech, nil
+}
+
+// listen - parsing messages and sending channels data to handler with common symbol name
+func (s *stream) listen() {
+	go func() {
+		for b := range s.dch {
+			var msg wsMessage
+			if err := json.Unmarshal(b, &msg); err != nil {
+				log.Println("[IDAX] Error parsing message: ", err)
+				continue
+			}
+			if msg.Event == "pong" || msg.Channel == "" {
+				continue
+			}
+			if msg.Code != codeSuccess {
+				log.Println("[IDAX] Error message: ", msg.Channel, msg.Code, msg.Message)
+				continue
+			}
+			symbol, ok := channelSymbol(msg.Channel)
+			if !ok {
+				continue
+			}
+			s.handler(symbol, msg)
+		}
+	}()
+}
+
+// channelName - channel of pair, i.e. idax_sub_eth_btc_depth for ETH_BTC
+func channelName(pair, suffix string) string {
+	return "idax_sub_" + strings.ToLower(pair) + "_" + suffix
+}
+
+// channelSymbol - common symbol name of channel, i.e. ETH-BTC for idax_sub_eth_btc_depth
+func channelSymbol(channel string) (symbol string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(channel, "idax_sub_"), "_")
+	if len(parts) < 3 {
+		return "", false
+	}
+	symbol, _, _ = parseSymbol(parts[0] + "_" + parts[1])
+	return symbol, true
+}
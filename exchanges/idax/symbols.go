@@ -3,9 +3,11 @@ package idax
 import (
 	"encoding/json"
 	"errors"
+	"math"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -44,17 +46,26 @@ func (sp *SymbolsProvider) Get() (symbols []schemas.Symbol, err error) {
 
 	for _, d := range data {
 		name, coin, baseCoin := parseSymbol(d.PairName)
+		// IDAX charges by order side, not by liquidity
+		fee := math.Max(d.BuyerFeeRate, d.SellerFeeRate)
 		symbols = append(symbols, schemas.Symbol{
-			Name:     name,
-			Coin:     coin,
-			BaseCoin: baseCoin,
-			Fee:      d.BuyerFeeRate,
+			Name:         name,
+			OriginalName: d.PairName,
+			Coin:         coin,
+			BaseCoin:     baseCoin,
+			Status:       schemas.SymbolStatusTrading,
+			OrderTypes:   []string{schemas.OrderTypeLimit, schemas.OrderTypeMarket},
+			Fee:          d.BuyerFeeRate,
+			MakerFee:     fee,
+			TakerFee:     fee,
 			// MinPrice:       d.MinPrice,
 			// MaxPrice:       d.MaxPrice,
-			MinAmount:      d.MinAmount,
-			MaxAmount:      d.MaxAmount,
-			PricePrecision: d.PriceDecimalPlace,
-			// QuotePrecision: d.QtyDecimalPlace,
+			MinAmount:       d.MinAmount,
+			MaxAmount:       d.MaxAmount,
+			PricePrecision:  d.PriceDecimalPlace,
+			AmountPrecision: d.QtyDecimalPlace,
+			TickSize:        math.Pow10(-d.PriceDecimalPlace),
+			LotStep:         math.Pow10(-d.QtyDecimalPlace),
 		})
 	}
 	return
@@ -76,3 +87,8 @@ func (sp *SymbolsProvider) Subscribe(d time.Duration) chan schemas.ResultChannel
 	}()
 	return ch
 }
+
+// SubscribeChanges - subscribing to symbols changes: listings, delistings, status and filters changes
+func (sp *SymbolsProvider) SubscribeChanges(d time.Duration) chan schemas.SymbolEventsChannel {
+	return listing.Watch(sp.Get, d)
+}
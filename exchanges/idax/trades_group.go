@@ -6,88 +6,91 @@ import (
 	"fmt"
 	"log"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/syndicatedb/goex/internal/http"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
-// TradesGroup - group of quotes to group requests
+// TradesGroup - group of trades, trades by websocket
 type TradesGroup struct {
 	symbols    []schemas.Symbol
 	httpClient *httpclient.Client
+	stream     *stream
+
+	// symbols with trades received on current connection
+	received map[string]bool
+	sync.Mutex
+
+	resultCh chan schemas.ResultChannel
 }
 
 // NewTradesGroup - OrderBook constructor
 func NewTradesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *TradesGroup {
 	proxyClient := httpProxy.NewClient(exchangeName)
 
+	var pairs []string
+	for _, s := range symbols {
+		pairs = append(pairs, symbolToPair(s.Name))
+	}
 	return &TradesGroup{
 		symbols:    symbols,
 		httpClient: httpclient.New(proxyClient),
+		stream:     newStream(pairs, channelTrades, httpProxy),
+		received:   make(map[string]bool),
 	}
 }
 
-// SubscribeAll - getting all symbols from Exchange
-func (q *TradesGroup) subscribe(ch chan schemas.ResultChannel, d time.Duration) {
-	// Local map to store incremental snapshot for some amount of time
-	// IDAX doesn't have updates, only snapshots
-
-	tradesMap := make(map[string]schemas.Trade)
-
-	// Iterator to clean up map from time to time
-	i := 0
-	for {
-		trades, err := q.Get()
-		if err != nil {
-			ch <- schemas.ResultChannel{
-				Data:  trades,
-				Error: err,
-			}
-		} else {
-			for _, b := range trades {
-				// Cleaning up snapshot map every 300 iterations
-				if i > 300 {
-					tradesMap = make(map[string]schemas.Trade)
-					i = 0
-				}
-				// If there is trades
-				if len(b) > 0 {
-
-					// By default dataType is (s)napshot
-					dataType := "s"
+/*
+Start - subscribing to trades.
+First message of channel has recent trades and is sent as snapshot, next ones have new trades only
+*/
+func (q *TradesGroup) Start(ch chan schemas.ResultChannel) {
+	q.resultCh = ch
+	q.stream.onConnect = q.reset
+	q.stream.Start(q.handleUpdate)
+}
 
-					// if trades map is not empty, then we have history
-					if len(tradesMap) > 0 {
-						dataType = "u" // dataType is (u)pdate
-					}
+// Stop - closing group connection
+func (q *TradesGroup) Stop() {
+	q.stream.Stop()
+}
 
-					// For updates or snapshot
-					var t []schemas.Trade
+// reset - forgetting trades of previous connection
+func (q *TradesGroup) reset() {
+	q.Lock()
+	q.received = make(map[string]bool)
+	q.Unlock()
+}
 
-					for _, trade := range b {
-						if _, ok := tradesMap[trade.ID]; ok == false {
-							// Appending to update
-							t = append(t, trade)
-							// Filling snapshot map
-							tradesMap[trade.ID] = trade
-						}
-					}
-					// Sending to listener
-					if len(t) > 0 {
-						log.Println("[IDAX] Trades updates trades / input / processed: ", len(tradesMap), "/", len(b), "/", len(t))
-						ch <- schemas.ResultChannel{
-							DataType: dataType,
-							Data:     b,
-							Error:    err,
-						}
-					}
-				}
-			}
+func (q *TradesGroup) handleUpdate(symbol string, msg wsMessage) {
+	var data []wsTrade
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		log.Println("[IDAX] Error parsing trades: ", err)
+		return
+	}
+	var trades []schemas.Trade
+	for _, t := range data {
+		if trade, ok := t.Map(symbol); ok {
+			trades = append(trades, trade)
 		}
-		i++
-		time.Sleep(d)
+	}
+	q.Lock()
+	snapshot := !q.received[symbol]
+	q.received[symbol] = true
+	q.Unlock()
+	if len(trades) == 0 && !snapshot {
+		return
+	}
+
+	dataType := schemas.DataTypeUpdate
+	if snapshot {
+		dataType = schemas.DataTypeSnapshot
+	}
+	q.resultCh <- schemas.ResultChannel{
+		DataType: dataType,
+		Data:     trades,
 	}
 }
 
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	httpclient "github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/importer"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -198,14 +199,13 @@ func (trading *TradingProvider) Orders(symbols []schemas.Symbol) (orders []schem
 	return
 }
 
-// ImportTrades - importing trades
+/*
+ImportTrades - trades can't be paged by cursor yet, channel has final status only.
+Trades history cursor ("since") isn't documented: it's not known if it's trade ID or time
+and if it's inclusive, so import is left for follow-up when it's checked on real account
+*/
 func (trading *TradingProvider) ImportTrades(opts schemas.FilterOptions) chan schemas.UserTradesChannel {
-	ch := make(chan schemas.UserTradesChannel)
-
-	trades, paging, err := trading.Trades(opts)
-	log.Println("paging: ", len(trades), paging, err)
-
-	return ch
+	return importer.Fail(schemas.ErrImportNotSupported)
 }
 
 // Trades - getting user trades
@@ -245,12 +245,16 @@ func (trading *TradingProvider) Trades(opts schemas.FilterOptions) (trades []sch
 func (trading *TradingProvider) Create(order schemas.Order) (result schemas.Order, err error) {
 	var b []byte
 
+	if order, err = schemas.PrepareOrder(trading.symbols, order); err != nil {
+		return
+	}
+
 	payload := httpclient.Params()
 
 	params := httpclient.Params()
 
-	price := strconv.FormatFloat(order.Price, 'f', -1, 64)
-	amount := strconv.FormatFloat(order.Amount, 'f', -1, 64)
+	price := order.PriceValue()
+	amount := order.AmountValue()
 
 	params.Set("orderSide", getOrderSideByType(order.Type))
 	params.Set("orderType", "1")
@@ -315,6 +319,25 @@ func (trading *TradingProvider) CancelAll() (err error) {
 	return
 }
 
+// CancelAllBy - cancelling orders by symbols and side
+func (trading *TradingProvider) CancelAllBy(symbols []schemas.Symbol, side string) (cancelled []schemas.Order, err error) {
+	var orders []schemas.Order
+	if len(symbols) == 0 {
+		return nil, schemas.ErrEmptySymbols
+	}
+	if orders, err = trading.Orders(symbols); err != nil {
+		return
+	}
+	for _, o := range schemas.FilterOrders(orders, symbols, side) {
+		if err = trading.Cancel(o); err != nil {
+			return
+		}
+		o.Status = schemas.StatusCancelled
+		cancelled = append(cancelled, o)
+	}
+	return
+}
+
 func signJSON(key, secret, url string, payload httpclient.KeyValue) (*http.Request, error) {
 	// pair and since already in payload
 	var query []string
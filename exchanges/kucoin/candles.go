@@ -29,7 +29,7 @@ func NewCandlesProvider(httpProxy proxy.Provider) *CandlesProvider {
 func (cp *CandlesProvider) SetSymbols(symbols []schemas.Symbol) schemas.CandlesProvider {
 	slice := make([]schemas.Symbol, len(symbols))
 	copy(slice, symbols)
-	capacity := orderBookSymbolsLimit
+	capacity := candlesSymbolsLimit
 	for {
 		if len(slice) <= capacity {
 			cp.groups = append(
@@ -67,7 +67,7 @@ func (cp *CandlesProvider) Get(symbol schemas.Symbol) ([]schemas.Candle, error)
 func (cp *CandlesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan schemas.ResultChannel {
 	ch := make(chan schemas.ResultChannel)
 	group := NewCandlesGroup([]schemas.Symbol{symbol}, cp.httpProxy)
-	go group.Subscribe(ch, d)
+	go group.Start(ch)
 	return ch
 }
 
@@ -75,8 +75,8 @@ func (cp *CandlesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) cha
 func (cp *CandlesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
 	ch := make(chan schemas.ResultChannel)
 
-	for _, orderBook := range cp.groups {
-		go orderBook.Subscribe(ch, d)
+	for _, group := range cp.groups {
+		go group.Start(ch)
 		time.Sleep(100 * time.Millisecond)
 	}
 	return ch
@@ -2,7 +2,7 @@ package kucoin
 
 import (
 	"encoding/json"
-	"fmt"
+	"log"
 	"strconv"
 	"time"
 
@@ -11,20 +11,13 @@ import (
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
-type klinesResponse struct {
-	Success   string    `json:"s"`
-	Close     []float64 `json:"c"`
-	Volume    []float64 `json:"v"`
-	Timestamp []int64   `json:"t"`
-	High      []float64 `json:"h"`
-	Low       []float64 `json:"l"`
-	Open      []float64 `json:"o"`
-}
+const candlesInterval = "1min"
 
-// CandlesGroup - kucoin candles group structure
+// CandlesGroup - kucoin candles group structure, candles updates by websocket
 type CandlesGroup struct {
 	symbols    []schemas.Symbol
 	httpClient *httpclient.Client
+	stream     *stream
 
 	outChannel chan schemas.ResultChannel
 }
@@ -32,57 +25,83 @@ type CandlesGroup struct {
 // NewCandlesGroup - kucoin candles group constructor
 func NewCandlesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *CandlesGroup {
 	proxyClient := httpProxy.NewClient(exchangeName)
+	httpClient := httpclient.New(proxyClient)
 
+	var topics []string
+	for _, s := range symbols {
+		topics = append(topics, topicCandles+s.OriginalName+"_"+candlesInterval)
+	}
 	return &CandlesGroup{
 		symbols:    symbols,
-		httpClient: httpclient.New(proxyClient),
+		httpClient: httpClient,
+		stream:     newStream(topics, false, httpClient, httpProxy),
 	}
 }
 
-// Subscribe - starting updates for symbols
-func (cg *CandlesGroup) Subscribe(ch chan schemas.ResultChannel, d time.Duration) {
+// Start - sending last hour candles and subscribing to updates
+func (cg *CandlesGroup) Start(ch chan schemas.ResultChannel) {
 	cg.outChannel = ch
+	cg.stream.onConnect = cg.snapshot
+	cg.stream.Start(cg.handleUpdate)
+}
 
-	for {
-		candles, err := cg.Get()
-		if err != nil {
-			go cg.publish(nil, "s", err)
-			continue
-		}
-		for _, b := range candles {
-			go cg.publish(b, "s", nil)
-		}
+func (cg *CandlesGroup) snapshot() {
+	candles, err := cg.Get()
+	if err != nil {
+		cg.publish(nil, dataTypeSnapshot, err)
+		return
+	}
+	for _, c := range candles {
+		cg.publish(c, dataTypeSnapshot, nil)
+	}
+}
 
-		time.Sleep(d)
+func (cg *CandlesGroup) handleUpdate(msg wsMessage) {
+	var update candleUpdate
+	if err := json.Unmarshal(msg.Data, &update); err != nil {
+		log.Println("[KUCOIN] Error parsing candle: ", err)
+		return
+	}
+	name, _, _ := parseSymbol(update.Symbol)
+	candle, err := mapCandle(name, update.Candles)
+	if err != nil {
+		log.Println("[KUCOIN] Error mapping candle: ", err)
+		return
 	}
+	cg.publish(candle, dataTypeUpdate, nil)
 }
 
-// Get - loading candles snapshot by symbols
+// Get - loading last hour candles by symbols
 func (cg *CandlesGroup) Get() (candles [][]schemas.Candle, err error) {
 	var b []byte
-	var resp klinesResponse
 
 	for _, symb := range cg.symbols {
+		var resp [][]string
 		to := time.Now()
 		from := to.Add(-1 * time.Hour)
 		query := httpclient.Params()
 		query.Set("symbol", symb.OriginalName)
-		query.Set("from", strconv.FormatInt(from.Unix(), 10))
-		query.Set("to", strconv.FormatInt(to.Unix(), 10))
-		query.Set("resolution", "1")
+		query.Set("type", candlesInterval)
+		query.Set("startAt", strconv.FormatInt(from.Unix(), 10))
+		query.Set("endAt", strconv.FormatInt(to.Unix(), 10))
 
 		if b, err = cg.httpClient.Get(apiCandles, query, false); err != nil {
 			return
 		}
-		if err = json.Unmarshal(b, &resp); err != nil {
-			return
-		}
-		if resp.Success != "ok" {
-			err = fmt.Errorf("[KUCOIN] Error getting candle: %v", resp)
+		if err = parseResponse(b, &resp); err != nil {
 			return
 		}
 
-		candles = append(candles, cg.mapSnapshot(symb.Name, resp))
+		var c []schemas.Candle
+		// newest candles come first
+		for i := len(resp) - 1; i >= 0; i-- {
+			var candle schemas.Candle
+			if candle, err = mapCandle(symb.Name, resp[i]); err != nil {
+				return
+			}
+			c = append(c, candle)
+		}
+		candles = append(candles, c)
 	}
 
 	return
@@ -95,20 +114,3 @@ func (cg *CandlesGroup) publish(data interface{}, dataType string, e error) {
 		Error:    e,
 	}
 }
-
-func (cg *CandlesGroup) mapSnapshot(symbol string, data klinesResponse) (candles []schemas.Candle) {
-	for i := range data.Close {
-		candles = append(candles, schemas.Candle{
-			Symbol:         symbol,
-			Open:           data.Open[i],
-			Close:          data.Close[i],
-			High:           data.High[i],
-			Low:            data.Low[i],
-			Volume:         data.Volume[i],
-			Timestamp:      data.Timestamp[i],
-			Discretization: 60,
-		})
-	}
-
-	return
-}
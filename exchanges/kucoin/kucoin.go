@@ -1,11 +1,13 @@
 package kucoin
 
 import (
+	"bytes"
 	"crypto/hmac"
 	"crypto/sha256"
 	b64 "encoding/base64"
-	"encoding/hex"
 	"fmt"
+	"io/ioutil"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -17,29 +19,52 @@ import (
 const (
 	exchangeName = "kucoin"
 
-	apiHost      = "https://api.kucoin.com"
-	apiSymbols   = "https://api.kucoin.com/v1/market/open/symbols"
-	apiCoins     = "https://api.kucoin.com/v1/market/open/coins"
-	apiOrderBook = "https://api.kucoin.com/v1/open/orders"
-	apiTrades    = "https://api.kucoin.com/v1/open/deal-orders"
-	apiTicker    = "https://api.kucoin.com/v1/open/tick"
+	apiSymbols   = "https://api.kucoin.com/api/v2/symbols"
+	apiOrderBook = "https://api.kucoin.com/api/v1/market/orderbook/level2_100"
+	apiTrades    = "https://api.kucoin.com/api/v1/market/histories"
+	apiTicker    = "https://api.kucoin.com/api/v1/market/allTickers"
+	apiStats     = "https://api.kucoin.com/api/v1/market/stats"
+	apiCandles   = "https://api.kucoin.com/api/v1/market/candles"
 
-	apiUserBalance  = "https://api.kucoin.com/v1/account/balance"
-	apiActiveOrders = "https://api.kucoin.com/v1/order/active-map"
-	apiUserTrades   = "https://api.kucoin.com/v1/order/dealt"
-	apiCandles      = "https://api.kucoin.com/v1/open/chart/history"
+	apiUserBalance  = "https://api.kucoin.com/api/v1/accounts"
+	apiActiveOrders = "https://api.kucoin.com/api/v1/orders"
+	apiUserTrades   = "https://api.kucoin.com/api/v1/fills"
 
-	apiCreateOrder = "https://api.kucoin.com/v1/order"
-	apiCancelOrder = "https://api.kucoin.com/v1/cancel-order"
+	apiCreateOrder = "https://api.kucoin.com/api/v1/orders"
+	apiCancelOrder = "https://api.kucoin.com/api/v1/orders/"
+	apiCancelAll   = "https://api.kucoin.com/api/v1/orders"
+
+	apiBulletPublic  = "https://api.kucoin.com/api/v1/bullet-public"
+	apiBulletPrivate = "https://api.kucoin.com/api/v1/bullet-private"
+)
+
+// Websocket topics
+const (
+	topicOrderBook = "/market/level2:"
+	topicTrades    = "/market/match:"
+	topicQuotes    = "/market/snapshot:"
+	topicCandles   = "/market/candles:"
+	topicOrders    = "/spotMarket/tradeOrders"
+	topicBalance   = "/account/balance"
 )
 
 const (
 	// SubscriptionInterval - default subscription interval
 	SubscriptionInterval  = 1 * time.Second
-	orderBookSymbolsLimit = 10
-	tradesSymbolsLimit    = 10
-	quotesSymbolsLimit    = 10
-	defaultPrecision      = 4
+	orderBookSymbolsLimit = 50
+	tradesSymbolsLimit    = 50
+	quotesSymbolsLimit    = 50
+	candlesSymbolsLimit   = 50
+	pageSize              = 500
+
+	importWindow  = 7 * 24 * time.Hour   // max time range of fills request
+	importHistory = 365 * 24 * time.Hour // fills are kept for one year
+)
+
+// Kucoin default (level 0) fees, symbols endpoint doesn't contain fees
+const (
+	defaultMakerFee = 0.001
+	defaultTakerFee = 0.001
 )
 
 const (
@@ -47,6 +72,11 @@ const (
 	dataTypeUpdate   = "u"
 )
 
+const (
+	codeSuccess   = "200000"
+	apiKeyVersion = "2"
+)
+
 // Kucoin - kucoin exchange structure
 type Kucoin struct {
 	schemas.Exchange
@@ -58,8 +88,8 @@ func New(opts schemas.Options) *Kucoin {
 	if proxyProvider == nil {
 		proxyProvider = proxy.NewNoProxy()
 	}
-	opts.Credentials.Sign = sign
-	return &Kucoin{
+	opts.Credentials.Sign = newSigner(opts.Credentials.Passphrase)
+	kucoin := &Kucoin{
 		Exchange: schemas.Exchange{
 			Credentials:   opts.Credentials,
 			ProxyProvider: proxyProvider,
@@ -68,13 +98,21 @@ func New(opts schemas.Options) *Kucoin {
 			Trades:        NewTradesProvider(proxyProvider),
 			Quotes:        NewQuotesProvider(proxyProvider),
 			Candles:       NewCandlesProvider(proxyProvider),
-			Trading:       NewTradingProvider(opts.Credentials, proxyProvider),
 		},
 	}
+	symbols, err := kucoin.SymbolProvider().Get()
+	if err != nil {
+		log.Println("Error getting symbols", err)
+	}
+	kucoin.Trading = NewTradingProvider(opts.Credentials, proxyProvider).SetSymbols(symbols)
+	return kucoin
 }
 
 func parseSymbol(s string) (name, coin, baseCoin string) {
 	sa := strings.Split(s, "-")
+	if len(sa) < 2 {
+		return s, s, ""
+	}
 	coin = strings.ToUpper(sa[0])
 	baseCoin = strings.ToUpper(sa[1])
 	name = coin + "-" + baseCoin
@@ -82,41 +120,47 @@ func parseSymbol(s string) (name, coin, baseCoin string) {
 	return
 }
 
-// sign - signing request
-func sign(key, secret string, req *http.Request) *http.Request {
-	// b, _ := req.GetBody()
-	// body, err := ioutil.ReadAll(b)
-	// if err != nil {
-	// 	return req
-	// }
-
-	// log.Printf("req: %+v\n", req.URL.Path)
-	// log.Printf("req: %+v\n", req.URL.Query().Encode())
-	// log.Println("path: ", req.URL.String())
-	path := req.URL.Path
-	// path := req.URL.String()
-	nonce := fmt.Sprintf("%v", time.Now().UnixNano()/int64(time.Millisecond))
-	var signed string
-	strForSign := path + "/" + nonce + "/" + req.URL.Query().Encode()
-	// log.Println("strForSign: ", strForSign)
-	signed = signRequest(strForSign, secret)
-	req.Header.Add("KC-API-NONCE", nonce)
-	req.Header.Add("KC-API-KEY", key)
-	req.Header.Add("KC-API-SIGNATURE", signed)
-
-	return req
+/*
+newSigner - creating request signer with API key passphrase.
+Signer interface doesn't pass passphrase, so it's kept in closure
+*/
+func newSigner(passphrase string) schemas.Signer {
+	return func(key, secret string, req *http.Request) *http.Request {
+		return sign(key, secret, passphrase, req)
+	}
 }
 
-func signRequest(str, secret string) string {
+/*
+sign - signing request with API key v2:
+KC-API-SIGN is base64(hmac-sha256(timestamp + method + path with query + body)),
+passphrase is signed by API secret too
+*/
+func sign(key, secret, passphrase string, req *http.Request) *http.Request {
+	var body []byte
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			body, _ = ioutil.ReadAll(rc)
+			rc.Close()
+		}
+	} else if req.Body != nil {
+		body, _ = ioutil.ReadAll(req.Body)
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	timestamp := fmt.Sprintf("%d", time.Now().UnixNano()/int64(time.Millisecond))
+	strForSign := timestamp + req.Method + req.URL.RequestURI() + string(body)
+
+	req.Header.Set("KC-API-KEY", key)
+	req.Header.Set("KC-API-SIGN", computeHmac256(strForSign, secret))
+	req.Header.Set("KC-API-TIMESTAMP", timestamp)
+	req.Header.Set("KC-API-PASSPHRASE", computeHmac256(passphrase, secret))
+	req.Header.Set("KC-API-KEY-VERSION", apiKeyVersion)
 
-	// fmt.Println("strForSign", strForSign)
-	signatureStr := b64.StdEncoding.EncodeToString([]byte(str))
-	return computeHmac256(signatureStr, secret)
+	return req
 }
 
 func computeHmac256(message string, secret string) string {
 	key := []byte(secret)
 	h := hmac.New(sha256.New, key)
 	h.Write([]byte(message))
-	return hex.EncodeToString(h.Sum(nil))
+	return b64.StdEncoding.EncodeToString(h.Sum(nil))
 }
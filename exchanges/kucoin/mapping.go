@@ -1,308 +1,599 @@
 package kucoin
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/syndicatedb/goex/schemas"
 )
 
-type responseHeader struct {
-	Success   bool   `json:"success"`
-	Code      string `json:"code"`
-	Message   string `json:"msg"`
-	Timestamp int64  `json:"timestamp"`
+/*
+response - v2 API response envelope
+
+	{
+	  "code": "200000",
+	  "msg": "",
+	  "data": data
+	}
+*/
+type response struct {
+	Code    string          `json:"code"`
+	Message string          `json:"msg"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// parseResponse - checking response code and unmarshaling response data
+func parseResponse(b []byte, data interface{}) (err error) {
+	var resp response
+	if err = json.Unmarshal(b, &resp); err != nil {
+		return
+	}
+	if resp.Code != codeSuccess {
+		return fmt.Errorf("[KUCOIN] Error response %v: %v", resp.Code, resp.Message)
+	}
+	if data == nil || len(resp.Data) == 0 {
+		return
+	}
+	return json.Unmarshal(resp.Data, data)
 }
+
+/*
+symbol - v2 symbol
+
+	{
+	  "symbol": "XLM-USDT",
+	  "name": "XLM-USDT",
+	  "baseCurrency": "XLM",
+	  "quoteCurrency": "USDT",
+	  "baseMinSize": "0.1",
+	  "quoteMinSize": "0.01",
+	  "baseMaxSize": "10000000000",
+	  "quoteMaxSize": "99999999",
+	  "baseIncrement": "0.0001",
+	  "quoteIncrement": "0.000001",
+	  "priceIncrement": "0.000001",
+	  "minFunds": "0.1",
+	  "enableTrading": true
+	}
+*/
 type symbol struct {
-	CoinType      string  `json:"coinType"`
-	Trading       bool    `json:"trading"`
-	Symbol        string  `json:"symbol"`
-	LastDealPrice float64 `json:"lastDealPrice"`
-	Buy           float64 `json:"buy"`
-	Sell          float64 `json:"sell"`
-	Change        float64 `json:"change"`
-	CoinTypePair  string  `json:"coinTypePair"`
-	Sort          int     `json:"sort"`
-	FeeRate       float64 `json:"feeRate"`
-	VolValue      float64 `json:"volValue"`
-	High          float64 `json:"high"`
-	Datetime      int64   `json:"datetime"`
-	Vol           float64 `json:"vol"`
-	Low           float64 `json:"low"`
-	ChangeRate    float64 `json:"changeRate"`
+	Symbol         string          `json:"symbol"`
+	Name           string          `json:"name"`
+	BaseCurrency   string          `json:"baseCurrency"`
+	QuoteCurrency  string          `json:"quoteCurrency"`
+	BaseMinSize    schemas.Decimal `json:"baseMinSize"`
+	QuoteMinSize   schemas.Decimal `json:"quoteMinSize"`
+	BaseMaxSize    schemas.Decimal `json:"baseMaxSize"`
+	QuoteMaxSize   schemas.Decimal `json:"quoteMaxSize"`
+	BaseIncrement  schemas.Decimal `json:"baseIncrement"`
+	QuoteIncrement schemas.Decimal `json:"quoteIncrement"`
+	PriceIncrement schemas.Decimal `json:"priceIncrement"`
+	MinFunds       schemas.Decimal `json:"minFunds"`
+	EnableTrading  bool            `json:"enableTrading"`
 }
 
 func (s *symbol) Map() schemas.Symbol {
 	name, quoteCoin, baseCoin := parseSymbol(s.Symbol)
+	status := schemas.SymbolStatusTrading
+	if !s.EnableTrading {
+		status = schemas.SymbolStatusHalted
+	}
+	minNotional := s.MinFunds
+	if !minNotional.IsSet() {
+		minNotional = s.QuoteMinSize
+	}
 
 	return schemas.Symbol{
-		Name:         name,
-		OriginalName: s.Symbol,
-		BaseCoin:     baseCoin,
-		Coin:         quoteCoin,
-		Fee:          s.FeeRate,
-		MinPrice:     s.Low,
-		MaxPrice:     s.High,
-	}
-}
-
-type coin struct {
-	WithdrawMinFee    float64 `json:"withdrawMinFee"`
-	CoinType          string  `json:"coinType"`
-	WithdrawMinAmount float64 `json:"withdrawMinAmount"`
-	WithdrawRemark    string  `json:"withdrawRemark"`
-	WithdrawFeeRate   float64 `json:"withdrawFeeRate"`
-	ConfirmationCount int64   `json:"confirmationCount"`
-	Enable            bool    `json:"enable"`
-	Name              string  `json:"name"`
-	TradePrecision    int64   `json:"tradePrecision"`
-	EnableWithdraw    bool    `json:"enableWithdraw"`
-	EnableDeposit     bool    `json:"enableDeposit"`
-	Coin              string  `json:"coin"`
-}
-
-type quote struct {
-	CoinType      string  `json:"coinType"`
-	Trading       bool    `json:"trading"`
-	Symbol        string  `json:"symbol"`
-	LastDealPrice float64 `json:"lastDealPrice"`
-	Buy           float64 `json:"buy"`
-	Sell          float64 `json:"sell"`
-	Change        float64 `json:"change"`
-	CoinTypePair  string  `json:"coinTypePair"`
-	Sort          int64   `json:"sort"`
-	FeeRate       float64 `json:"feeRate"`
-	VolValue      float64 `json:"volValue"`
-	Plus          bool    `json:"plus"`
-	High          float64 `json:"high"`
-	DateTime      int64   `json:"datetime"`
-	Vol           float64 `json:"vol"`
-	Low           float64 `json:"low"`
-	ChangeRate    float64 `json:"changeRate"`
-}
-
-func (q *quote) Map() schemas.Quote {
-	name, _, _ := parseSymbol(q.Symbol)
+		Name:            name,
+		OriginalName:    s.Symbol,
+		BaseCoin:        baseCoin,
+		Coin:            quoteCoin,
+		Status:          status,
+		OrderTypes:      []string{schemas.OrderTypeLimit, schemas.OrderTypeMarket},
+		Fee:             defaultTakerFee,
+		MakerFee:        defaultMakerFee,
+		TakerFee:        defaultTakerFee,
+		DefaultFees:     true,
+		MinAmount:       s.BaseMinSize.Float64(),
+		MaxAmount:       s.BaseMaxSize.Float64(),
+		TickSize:        s.PriceIncrement.Float64(),
+		LotStep:         s.BaseIncrement.Float64(),
+		MinNotional:     minNotional.Float64(),
+		PricePrecision:  precision(s.PriceIncrement),
+		AmountPrecision: precision(s.BaseIncrement),
+	}
+}
+
+// precision - number of decimal places in step: 0.0001 -> 4
+func precision(step schemas.Decimal) int {
+	s := strings.TrimRight(step.String(), "0")
+	if i := strings.Index(s, "."); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+/*
+ticker - 24h stats, used by allTickers, stats and snapshot topic
+
+	{
+	  "symbol": "BTC-USDT",
+	  "buy": "0.00001191",
+	  "sell": "0.00001206",
+	  "changeRate": "0.057",
+	  "changePrice": "0.00000065",
+	  "high": "0.0000123",
+	  "low": "0.00001109",
+	  "vol": "45161.5073",
+	  "volValue": "2127.28693026",
+	  "last": "0.00001204"
+	}
+*/
+type ticker struct {
+	Symbol          string          `json:"symbol"`
+	Buy             schemas.Decimal `json:"buy"`
+	Sell            schemas.Decimal `json:"sell"`
+	ChangeRate      schemas.Decimal `json:"changeRate"`
+	ChangePrice     schemas.Decimal `json:"changePrice"`
+	High            schemas.Decimal `json:"high"`
+	Low             schemas.Decimal `json:"low"`
+	Vol             schemas.Decimal `json:"vol"`
+	VolValue        schemas.Decimal `json:"volValue"`
+	Last            schemas.Decimal `json:"last"`
+	LastTradedPrice schemas.Decimal `json:"lastTradedPrice"` // snapshot topic
+}
+
+type tickersResponse struct {
+	Time   int64    `json:"time"`
+	Ticker []ticker `json:"ticker"`
+}
+
+func (t *ticker) Map() schemas.Quote {
+	name, _, _ := parseSymbol(t.Symbol)
+	last := t.Last
+	if !last.IsSet() {
+		last = t.LastTradedPrice
+	}
 
 	return schemas.Quote{
 		Symbol:      name,
-		Price:       q.LastDealPrice,
-		Sell:        q.Sell,
-		Buy:         q.Buy,
-		High:        q.High,
-		Low:         q.Low,
-		ChangeValue: q.Change,
-		ChangeRate:  q.ChangeRate,
-		VolumeBase:  q.Vol,
-		Volume:      q.VolValue,
+		Price:       last.Float64(),
+		Sell:        t.Sell.Float64(),
+		Buy:         t.Buy.Float64(),
+		High:        t.High.Float64(),
+		Low:         t.Low.Float64(),
+		ChangeValue: t.ChangePrice.Float64(),
+		ChangeRate:  t.ChangeRate.Float64(),
+		VolumeBase:  t.Vol.Float64(),
+		Volume:      t.VolValue.Float64(),
 	}
 }
 
 /*
-UserBalanceResponse http response
-{
-  "success": true,
-  "code": "OK",
-  "msg": "Operation succeeded.",
-  "timestamp": 1534014768145,
-  "data": [
-    {
-      "coinType": "KCS",
-      "balanceStr": "0.0",
-      "freezeBalance": 0,
-      "balance": 0,
-      "freezeBalanceStr": "0.0"
-    }
- ]
+orderBookSnapshot - level2_100 snapshot
+
+	{
+	  "sequence": "3262786978",
+	  "time": 1550653727731,
+	  "bids": [["6500.12", "0.45054140"]],
+	  "asks": [["6500.16", "0.57753524"]]
+	}
+*/
+type orderBookSnapshot struct {
+	Sequence schemas.Decimal     `json:"sequence"`
+	Time     int64               `json:"time"`
+	Bids     [][]schemas.Decimal `json:"bids"`
+	Asks     [][]schemas.Decimal `json:"asks"`
+}
+
+func (s *orderBookSnapshot) Map(symbol string) schemas.OrderBook {
+	return schemas.OrderBook{
+		Symbol: symbol,
+		Buy:    mapBookOrders(symbol, schemas.Buy, s.Bids),
+		Sell:   mapBookOrders(symbol, schemas.Sell, s.Asks),
+	}
 }
+
+/*
+orderBookUpdate - level2 topic message data
+
+	{
+	  "sequenceStart": 1545896669105,
+	  "sequenceEnd": 1545896669106,
+	  "symbol": "BTC-USDT",
+	  "changes": {
+	    "asks": [["6", "1", "1545896669105"]],
+	    "bids": [["4", "1", "1545896669106"]]
+	  }
+	}
 */
-type UserBalanceResponse struct {
-	Success   bool          `json:"success"`   // : true,
-	Code      string        `json:"code"`      // : "OK",
-	Msg       string        `json:"msg"`       // : "Operation succeeded.",
-	Timestamp int64         `json:"timestamp"` // : 1534014768145,
-	Data      []UserBalance `json:"data"`
-}
-
-func (ubr *UserBalanceResponse) Map(prices map[string]float64) schemas.UserInfo {
-	balances := make(map[string]schemas.Balance)
-	for _, b := range ubr.Data {
-		balances[b.CoinType] = schemas.Balance{
-			Coin:      b.CoinType,
-			Available: b.Balance,
-			InOrders:  b.FreezeBalance,
-			Total:     b.Balance + b.FreezeBalance,
+type orderBookUpdate struct {
+	SequenceStart int64  `json:"sequenceStart"`
+	SequenceEnd   int64  `json:"sequenceEnd"`
+	Symbol        string `json:"symbol"`
+	Changes       struct {
+		Asks [][]schemas.Decimal `json:"asks"`
+		Bids [][]schemas.Decimal `json:"bids"`
+	} `json:"changes"`
+}
+
+func (u *orderBookUpdate) Map() schemas.OrderBook {
+	name, _, _ := parseSymbol(u.Symbol)
+	return schemas.OrderBook{
+		Symbol: name,
+		Buy:    mapBookOrders(name, schemas.Buy, u.Changes.Bids),
+		Sell:   mapBookOrders(name, schemas.Sell, u.Changes.Asks),
+	}
+}
+
+// mapBookOrders - mapping [price, size, (sequence)] rows, zero size removes price level
+func mapBookOrders(symbol, side string, rows [][]schemas.Decimal) (orders []schemas.Order) {
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
 		}
+		order := schemas.Order{
+			Symbol:        symbol,
+			Type:          side,
+			Price:         row[0].Float64(),
+			Amount:        row[1].Float64(),
+			Count:         1,
+			PriceDecimal:  row[0],
+			AmountDecimal: row[1],
+		}
+		if row[1].Sign() == 0 {
+			order.Remove = 1
+		}
+		orders = append(orders, order)
 	}
-	return schemas.UserInfo{
-		Balances: balances,
-		Prices:   prices,
+	return
+}
+
+/*
+trade - public trade, histories response and match topic message data
+
+	{
+	  "sequence": "1545896668571",
+	  "symbol": "BTC-USDT",
+	  "tradeId": "5c24c5da03aa673885cd67aa",
+	  "price": "0.07",
+	  "size": "0.004",
+	  "side": "buy",
+	  "time": 1545904567062140823
+	}
+*/
+type trade struct {
+	Sequence schemas.Decimal `json:"sequence"`
+	Symbol   string          `json:"symbol"`
+	TradeID  string          `json:"tradeId"`
+	Price    schemas.Decimal `json:"price"`
+	Size     schemas.Decimal `json:"size"`
+	Side     string          `json:"side"`
+	Time     schemas.Decimal `json:"time"` // nanoseconds, string in match topic
+}
+
+func (t *trade) Map(symbol string) schemas.Trade {
+	id := t.TradeID
+	if id == "" {
+		id = t.Sequence.String()
+	}
+	return schemas.Trade{
+		ID:            id,
+		Symbol:        symbol,
+		Type:          strings.ToLower(t.Side),
+		Price:         t.Price.Float64(),
+		Amount:        t.Size.Float64(),
+		Timestamp:     toInt(t.Time) / 1e6,
+		PriceDecimal:  t.Price,
+		AmountDecimal: t.Size,
 	}
 }
 
 /*
-UserBalance - kucoin user balance
-   {
-     "coinType": "KCS",
-     "balanceStr": "0.0",
-     "freezeBalance": 0,
-     "balance": 0,
-     "freezeBalanceStr": "0.0"
-   }
+candleUpdate - candles topic message data
 
+	{
+	  "symbol": "BTC-USDT",
+	  "candles": ["1589968800", "9786.9", "9740.8", "9806.1", "9732", "27.45649579", "268280.09830877"],
+	  "time": 1589970010253893337
+	}
 */
-type UserBalance struct {
-	CoinType         string  `json:"coinType"`         // "KCS",
-	BalanceStr       string  `json:"balanceStr"`       // "0.0",
-	FreezeBalance    float64 `json:"freezeBalance"`    // 0,
-	Balance          float64 `json:"balance"`          // 0,
-	FreezeBalanceStr string  `json:"freezeBalanceStr"` // "0.0"
+type candleUpdate struct {
+	Symbol  string   `json:"symbol"`
+	Candles []string `json:"candles"`
+	Time    int64    `json:"time"`
+}
+
+// mapCandle - mapping [time, open, close, high, low, volume, turnover] row
+func mapCandle(symbol string, row []string) (candle schemas.Candle, err error) {
+	if len(row) < 6 {
+		err = fmt.Errorf("[KUCOIN] Invalid candle: %v", row)
+		return
+	}
+	var values [6]float64
+	for i := range values {
+		if values[i], err = strconv.ParseFloat(row[i], 64); err != nil {
+			return
+		}
+	}
+	return schemas.Candle{
+		Symbol:         symbol,
+		Timestamp:      int64(values[0]) * 1000,
+		Open:           values[1],
+		Close:          values[2],
+		High:           values[3],
+		Low:            values[4],
+		Volume:         values[5],
+		Discretization: 60,
+	}, nil
 }
 
 /*
-UserTradesResponse http response
-{
-  "success": true,
-  "code": "OK",
-  "msg": "Operation succeeded.",
-  "timestamp": 1534017182845,
-  "data": data
+account - user account, kucoin has main and trade accounts by each currency
+
+	{
+	  "id": "5bd6e9286d99522a52e458de",
+	  "currency": "BTC",
+	  "type": "trade",
+	  "balance": "237582.04299",
+	  "available": "237582.032",
+	  "holds": "0.01099"
+	}
+*/
+type account struct {
+	ID        string          `json:"id"`
+	Currency  string          `json:"currency"`
+	Type      string          `json:"type"`
+	Balance   schemas.Decimal `json:"balance"`
+	Available schemas.Decimal `json:"available"`
+	Holds     schemas.Decimal `json:"holds"`
+}
+
+func (a *account) Map() schemas.Balance {
+	return schemas.Balance{
+		Coin:             a.Currency,
+		Available:        a.Available.Float64(),
+		InOrders:         a.Holds.Float64(),
+		Total:            a.Balance.Float64(),
+		AvailableDecimal: a.Available,
+		InOrdersDecimal:  a.Holds,
+		TotalDecimal:     a.Balance,
+	}
 }
+
+// paged - paginated response data
+type paged struct {
+	CurrentPage int64           `json:"currentPage"`
+	PageSize    int             `json:"pageSize"`
+	TotalNum    int64           `json:"totalNum"`
+	TotalPage   int64           `json:"totalPage"`
+	Items       json.RawMessage `json:"items"`
+}
+
+/*
+order - user order
+
+	{
+	  "id": "5c35c02703aa673ceec2a168",
+	  "symbol": "BTC-USDT",
+	  "type": "limit",
+	  "side": "buy",
+	  "price": "10",
+	  "size": "2",
+	  "dealSize": "0",
+	  "isActive": true,
+	  "cancelExist": false,
+	  "createdAt": 1547026471000
+	}
 */
-type UserTradesResponse struct {
-	Success   bool   `json:"success"`   // : true,
-	Code      string `json:"code"`      // : "OK",
-	Msg       string `json:"msg"`       // : "Operation succeeded.",
-	Timestamp int64  `json:"timestamp"` // : 1534014768145,
-	Data      struct {
-		Total      int64       `json:"total"`      // 59180,
-		FirstPage  bool        `json:"firstPage"`  // true,
-		LastPage   bool        `json:"lastPage"`   // false,
-		CurrPageNo int64       `json:"currPageNo"` // 1,
-		Limit      int         `json:"limit"`      // 12,
-		PageNos    int64       `json:"pageNos"`    // 4932
-		Datas      []UserTrade `json:"datas"`      // 4932
-	} `json:"data"`
-}
-
-func (utr *UserTradesResponse) Map() []schemas.Trade {
-	var trades []schemas.Trade
-	for _, t := range utr.Data.Datas {
-		trades = append(trades, schemas.Trade{
-			ID:        fmt.Sprintf("%d", t.ID),
-			OrderID:   t.OrderID,
-			Symbol:    t.CoinType + "-" + t.CoinTypePair,
-			Type:      t.DealDirection,
-			Price:     t.DealPrice,
-			Amount:    t.Amount,
-			Fee:       t.Fee,
-			Timestamp: t.CreatedAt,
-		})
-	}
-	return trades
+type order struct {
+	ID          string          `json:"id"`
+	Symbol      string          `json:"symbol"`
+	Type        string          `json:"type"`
+	Side        string          `json:"side"`
+	Price       schemas.Decimal `json:"price"`
+	Size        schemas.Decimal `json:"size"`
+	DealSize    schemas.Decimal `json:"dealSize"`
+	IsActive    bool            `json:"isActive"`
+	CancelExist bool            `json:"cancelExist"`
+	CreatedAt   int64           `json:"createdAt"`
+}
+
+func (o *order) Map() schemas.Order {
+	name, _, _ := parseSymbol(o.Symbol)
+	status := schemas.StatusNew
+	if !o.IsActive {
+		status = schemas.StatusTrade
+		if o.CancelExist {
+			status = schemas.StatusCancelled
+		}
+	}
+	return schemas.Order{
+		ID:                  o.ID,
+		Symbol:              name,
+		Type:                strings.ToUpper(o.Side),
+		Price:               o.Price.Float64(),
+		Amount:              o.Size.Float64(),
+		AmountFilled:        o.DealSize.Float64(),
+		Count:               1,
+		CreatedAt:           o.CreatedAt,
+		Status:              status,
+		PriceDecimal:        o.Price,
+		AmountDecimal:       o.Size,
+		AmountFilledDecimal: o.DealSize,
+	}
 }
 
 /*
-UserTrade user trade
-
-   {
-     "coinType": "CAPP",
-     "amount": 2148.25,
-     "dealValue": 0.00388833,
-     "fee": 2.14825,
-     "dealDirection": "BUY",
-     "coinTypePair": "BTC",
-     "oid": "5b5f0f4025cae61a001c8271",
-     "dealPrice": 0.00000181,
-     "orderOid": "5b5f0f4025cae61d5840a58d",
-     "feeRate": 0.001,
-     "createdAt": 1532956480000,
-     "id": 1845414,
-     "direction": "BUY"
-   }
+fill - user trade
+
+	{
+	  "symbol": "BTC-USDT",
+	  "tradeId": "5c35c02709e4f67d5266954e",
+	  "orderId": "5c35c02703aa673ceec2a168",
+	  "side": "buy",
+	  "price": "0.083",
+	  "size": "0.8424304",
+	  "fee": "0",
+	  "feeCurrency": "USDT",
+	  "createdAt": 1547026472000
+	}
 */
-type UserTrade struct {
-	CoinType      string  `json:"coinType"`      // "CAPP",
-	Amount        float64 `json:"amount"`        // 2148.25,
-	DealValue     float64 `json:"dealValue"`     // 0.00388833,
-	Fee           float64 `json:"fee"`           // 2.14825,
-	DealDirection string  `json:"dealDirection"` // "BUY",
-	CoinTypePair  string  `json:"coinTypePair"`  // "BTC",
-	Oid           string  `json:"oid"`           // "5b5f0f4025cae61a001c8271",
-	DealPrice     float64 `json:"dealPrice"`     // 0.00000181,
-	OrderID       string  `json:"orderOid"`      // "5b5f0f4025cae61d5840a58d",
-	FeeRate       float64 `json:"feeRate"`       // 0.001,
-	CreatedAt     int64   `json:"createdAt"`     // 1532956480000,
-	ID            int64   `json:"id"`            // 1845414,
-	Direction     string  `json:"direction"`     // "BUY"
-}
-
-type UserOrdersResponse struct {
-	Success   bool      `json:"success"`   // : true,
-	Code      string    `json:"code"`      // : "OK",
-	Msg       string    `json:"msg"`       // : "Operation succeeded.",
-	Timestamp int64     `json:"timestamp"` // : 1534014768145,
-	Data      OrderBook `json:"data"`
-}
-
-type OrderBook struct {
-	Sell []UserOrder
-	Buy  []UserOrder
-}
-
-func (ob *OrderBook) Map() (orders []schemas.Order) {
-	for _, o := range ob.Buy {
-		orders = append(orders, o.Map())
-	}
-	for _, o := range ob.Sell {
-		orders = append(orders, o.Map())
+type fill struct {
+	Symbol      string          `json:"symbol"`
+	TradeID     string          `json:"tradeId"`
+	OrderID     string          `json:"orderId"`
+	Side        string          `json:"side"`
+	Price       schemas.Decimal `json:"price"`
+	Size        schemas.Decimal `json:"size"`
+	Fee         schemas.Decimal `json:"fee"`
+	FeeCurrency string          `json:"feeCurrency"`
+	CreatedAt   int64           `json:"createdAt"`
+}
+
+func (f *fill) Map() schemas.Trade {
+	name, _, _ := parseSymbol(f.Symbol)
+	return schemas.Trade{
+		ID:            f.TradeID,
+		OrderID:       f.OrderID,
+		Symbol:        name,
+		Type:          strings.ToUpper(f.Side),
+		Price:         f.Price.Float64(),
+		Amount:        f.Size.Float64(),
+		Fee:           f.Fee.Float64(),
+		FeeCoin:       f.FeeCurrency,
+		Timestamp:     f.CreatedAt,
+		PriceDecimal:  f.Price,
+		AmountDecimal: f.Size,
+		FeeDecimal:    f.Fee,
 	}
-	return
 }
 
-type UserOrder struct {
-	Oid           string  `json:"oid"`           // "59e59b279bd8d31d093d956e",
-	Type          string  `json:"type"`          // "SELL",
-	UserOid       string  `json:"userOid"`       // null,
-	CoinType      string  `json:"coinType"`      // "KCS",
-	CoinTypePair  string  `json:"coinTypePair"`  // "BTC",
-	Direction     string  `json:"direction"`     // "SELL",
-	Price         float64 `json:"price"`         // 0.1,
-	DealAmount    float64 `json:"dealAmount"`    // 0,
-	PendingAmount float64 `json:"pendingAmount"` // 100,
-	CreatedAt     int64   `json:"createdAt"`     // 1508219688000,
-	UpdatedAt     int64   `json:"updatedAt"`     // 1508219688000
+type orderCreateResponse struct {
+	OrderID string `json:"orderId"`
+}
+
+type orderCancelResponse struct {
+	CancelledOrderIds []string `json:"cancelledOrderIds"`
+}
+
+/*
+orderChange - private orders topic message data
+
+	{
+	  "symbol": "KCS-USDT",
+	  "orderType": "limit",
+	  "side": "buy",
+	  "orderId": "5efab07953bdea00089965d2",
+	  "type": "match",
+	  "orderTime": 1593487481683297666,
+	  "size": "0.1",
+	  "filledSize": "0.1",
+	  "price": "0.937",
+	  "matchPrice": "0.937",
+	  "matchSize": "0.1",
+	  "tradeId": "5efab07a4ee4c7000a82d6d9",
+	  "remainSize": "0",
+	  "status": "match",
+	  "ts": 1593487482038606180
+	}
+*/
+type orderChange struct {
+	Symbol     string          `json:"symbol"`
+	OrderType  string          `json:"orderType"`
+	Side       string          `json:"side"`
+	OrderID    string          `json:"orderId"`
+	Type       string          `json:"type"`
+	OrderTime  int64           `json:"orderTime"`
+	Size       schemas.Decimal `json:"size"`
+	FilledSize schemas.Decimal `json:"filledSize"`
+	Price      schemas.Decimal `json:"price"`
+	MatchPrice schemas.Decimal `json:"matchPrice"`
+	MatchSize  schemas.Decimal `json:"matchSize"`
+	TradeID    string          `json:"tradeId"`
+	RemainSize schemas.Decimal `json:"remainSize"`
+	Status     string          `json:"status"`
+	Ts         int64           `json:"ts"`
 }
 
-func (uo *UserOrder) Map() schemas.Order {
+func (oc *orderChange) Map() schemas.Order {
+	name, _, _ := parseSymbol(oc.Symbol)
+	status := schemas.StatusNew
+	switch oc.Type {
+	case "match", "filled":
+		status = schemas.StatusTrade
+	case "canceled":
+		status = schemas.StatusCancelled
+	}
 	return schemas.Order{
-		ID:           uo.Oid,
-		Symbol:       uo.CoinType + "-" + uo.CoinTypePair,
-		Type:         uo.Direction,
-		Price:        uo.Price,
-		Amount:       uo.DealAmount,
-		AmountFilled: (uo.DealAmount - uo.PendingAmount),
-		Count:        1,
-		CreatedAt:    uo.CreatedAt,
-		Remove:       0,
-	}
-}
-
-type OrderCreateResponse struct {
-	Success   bool   `json:"success"`   // : true,
-	Code      string `json:"code"`      // : "OK",
-	Msg       string `json:"msg"`       // : "Operation succeeded.",
-	Timestamp int64  `json:"timestamp"` // : 1534014768145,
-	Data      struct {
-		OrderOid string `json:"orderOid"`
-	} `json:"data"`
-}
-
-type OrderCancelResponse struct {
-	Success   bool   `json:"success"`   // : true,
-	Code      string `json:"code"`      // : "OK",
-	Msg       string `json:"msg"`       // : "Operation succeeded.",
-	Timestamp int64  `json:"timestamp"` // : 1534014768145,
-	Data      struct {
-		OrderOid string `json:"orderOid"`
-	} `json:"data"`
+		ID:                  oc.OrderID,
+		Symbol:              name,
+		Type:                strings.ToUpper(oc.Side),
+		Price:               oc.Price.Float64(),
+		Amount:              oc.Size.Float64(),
+		AmountFilled:        oc.FilledSize.Float64(),
+		Count:               1,
+		CreatedAt:           oc.OrderTime / 1e6,
+		Status:              status,
+		PriceDecimal:        oc.Price,
+		AmountDecimal:       oc.Size,
+		AmountFilledDecimal: oc.FilledSize,
+	}
+}
+
+// Trade - order match as user trade, fee is not sent in orders topic
+func (oc *orderChange) Trade() schemas.Trade {
+	name, _, _ := parseSymbol(oc.Symbol)
+	return schemas.Trade{
+		ID:            oc.TradeID,
+		OrderID:       oc.OrderID,
+		Symbol:        name,
+		Type:          strings.ToUpper(oc.Side),
+		Price:         oc.MatchPrice.Float64(),
+		Amount:        oc.MatchSize.Float64(),
+		Timestamp:     oc.Ts / 1e6,
+		PriceDecimal:  oc.MatchPrice,
+		AmountDecimal: oc.MatchSize,
+	}
+}
+
+/*
+balanceChange - private balance topic message data
+
+	{
+	  "total": "88",
+	  "available": "88",
+	  "availableChange": "88",
+	  "currency": "KCS",
+	  "hold": "0",
+	  "holdChange": "0",
+	  "relationEvent": "trade.setted",
+	  "time": "1574831000000"
+	}
+*/
+type balanceChange struct {
+	Total     schemas.Decimal `json:"total"`
+	Available schemas.Decimal `json:"available"`
+	Hold      schemas.Decimal `json:"hold"`
+	Currency  string          `json:"currency"`
+}
+
+func (bc *balanceChange) Map() schemas.Balance {
+	return schemas.Balance{
+		Coin:             bc.Currency,
+		Available:        bc.Available.Float64(),
+		InOrders:         bc.Hold.Float64(),
+		Total:            bc.Total.Float64(),
+		AvailableDecimal: bc.Available,
+		InOrdersDecimal:  bc.Hold,
+		TotalDecimal:     bc.Total,
+	}
+}
+
+// toInt - integer value of decimal, sequences and nanoseconds timestamps don't fit float
+func toInt(d schemas.Decimal) int64 {
+	i, _ := strconv.ParseInt(d.String(), 10, 64)
+	return i
 }
@@ -2,9 +2,9 @@ package kucoin
 
 import (
 	"fmt"
-	"sync"
 	"time"
 
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -12,43 +12,48 @@ import (
 // OrdersProvider - order book provider structure
 type OrdersProvider struct {
 	httpProxy proxy.Provider
-	symbols   []schemas.Symbol
-	groups    []*OrderBookGroup
-
-	sync.Mutex
+	groups    *listing.Groups
 }
 
 // NewOrdersProvider - OrdersProvider constructor
 func NewOrdersProvider(httpProxy proxy.Provider) *OrdersProvider {
-	return &OrdersProvider{
+	ob := &OrdersProvider{
 		httpProxy: httpProxy,
 	}
+	ob.groups = listing.NewGroups(orderBookSymbolsLimit, ob.startGroup)
+	return ob
 }
 
-// SetSymbols - getting all symbols from Exchange
+// SetSymbols - replacing symbols, they are split into groups by symbols chunks on subscription.
+// Symbols of running subscription are changed by AddSymbols and RemoveSymbols
 func (ob *OrdersProvider) SetSymbols(symbols []schemas.Symbol) schemas.OrdersProvider {
-	ob.symbols = symbols
-	slice := make([]schemas.Symbol, len(symbols))
-	copy(slice, symbols)
-	capacity := orderBookSymbolsLimit
-	for {
-		if len(slice) <= capacity {
-			ob.groups = append(
-				ob.groups,
-				NewOrderBookGroup(slice, ob.httpProxy),
-			)
-			break
-		}
-		ob.groups = append(
-			ob.groups,
-			NewOrderBookGroup(slice[0:capacity], ob.httpProxy),
-		)
-
-		slice = slice[capacity:]
-	}
+	ob.groups.Reset(symbols)
 	return ob
 }
 
+// SubscribeAll - subscribing all groups
+func (ob *OrdersProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return ob.groups.Subscribe(d)
+}
+
+// AddSymbols - adding symbols to running subscription, i.e. new listings
+func (ob *OrdersProvider) AddSymbols(symbols []schemas.Symbol) {
+	ob.groups.Add(symbols)
+}
+
+// RemoveSymbols - removing symbols from subscription, i.e. delistings.
+// Groups subscribed to them are reconnected without them
+func (ob *OrdersProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	ob.groups.Remove(symbols)
+}
+
+// startGroup - creating and starting group of symbols
+func (ob *OrdersProvider) startGroup(symbols []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) listing.Group {
+	group := NewOrderBookGroup(symbols, ob.httpProxy)
+	go group.Start(ch)
+	return group
+}
+
 // Get - getting all symbols from Exchange
 func (ob *OrdersProvider) Get(symbol schemas.Symbol) (book schemas.OrderBook, err error) {
 	orderBookGroup := NewOrderBookGroup([]schemas.Symbol{symbol}, ob.httpProxy)
@@ -65,18 +70,6 @@ func (ob *OrdersProvider) Get(symbol schemas.Symbol) (book schemas.OrderBook, er
 func (ob *OrdersProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan schemas.ResultChannel {
 	ch := make(chan schemas.ResultChannel)
 	group := NewOrderBookGroup([]schemas.Symbol{symbol}, ob.httpProxy)
-	go group.Subscribe(ch, d)
-	return ch
-}
-
-// SubscribeAll - getting all symbols from Exchange
-func (ob *OrdersProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
-	bufLength := len(ob.symbols)
-	ch := make(chan schemas.ResultChannel, 2*bufLength)
-
-	for _, orderBook := range ob.groups {
-		go orderBook.Subscribe(ch, d)
-		time.Sleep(100 * time.Millisecond)
-	}
+	go group.Start(ch)
 	return ch
 }
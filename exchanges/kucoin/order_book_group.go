@@ -2,126 +2,118 @@ package kucoin
 
 import (
 	"encoding/json"
-	"fmt"
 	"log"
-	"time"
+	"strings"
+	"sync"
 
+	"github.com/syndicatedb/goex/internal/http"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
-
-	"github.com/syndicatedb/goex/internal/http"
 )
 
-type orderbookResponse struct {
-	responseHeader
-	Data map[string]interface{} `json:"data"`
-}
-
-// OrderBookGroup - order book
+// OrderBookGroup - order book group, level2 updates by websocket
 type OrderBookGroup struct {
 	symbols    []schemas.Symbol
 	httpClient *httpclient.Client
-	// emptySymbols map[string]string
+	stream     *stream
+
+	// sequences of loaded snapshots, older updates are skipped
+	sequences map[string]int64
+	sync.Mutex
+
+	resultCh chan schemas.ResultChannel
 }
 
 // NewOrderBookGroup - OrderBook constructor
 func NewOrderBookGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *OrderBookGroup {
 	proxyClient := httpProxy.NewClient(exchangeName)
+	httpClient := httpclient.New(proxyClient)
 
+	var smbls []string
+	for _, s := range symbols {
+		smbls = append(smbls, s.OriginalName)
+	}
 	return &OrderBookGroup{
 		symbols:    symbols,
-		httpClient: httpclient.New(proxyClient),
-		// emptySymbols: make(map[string]string),
+		httpClient: httpClient,
+		stream:     newStream([]string{topicOrderBook + strings.Join(smbls, ",")}, false, httpClient, httpProxy),
+		sequences:  make(map[string]int64),
 	}
 }
 
-// Subscribe - starting updates for symbols
-func (ob *OrderBookGroup) Subscribe(ch chan schemas.ResultChannel, d time.Duration) {
-	for {
-		book, err := ob.Get()
+// Start - subscribing to level2 updates and sending snapshots after every connection
+func (ob *OrderBookGroup) Start(ch chan schemas.ResultChannel) {
+	ob.resultCh = ch
+	ob.stream.onConnect = ob.snapshot
+	ob.stream.Start(ob.handleUpdate)
+}
+
+// Stop - closing group connection
+func (ob *OrderBookGroup) Stop() {
+	ob.stream.Stop()
+}
+
+// snapshot - loading snapshots, websocket sends updates only
+func (ob *OrderBookGroup) snapshot() {
+	for _, symbol := range ob.symbols {
+		book, sequence, err := ob.get(symbol)
 		if err != nil {
-			go func() {
-				ch <- schemas.ResultChannel{
-					Data:  book,
-					Error: err,
-				}
-			}()
-			continue
+			log.Println("[KUCOIN] Error getting orderbook snapshot", symbol.Name, err)
 		}
-		for _, b := range book {
-			ch <- schemas.ResultChannel{
-				DataType: "s",
-				Data:     b,
-				Error:    err,
-			}
+		ob.Lock()
+		ob.sequences[symbol.OriginalName] = sequence
+		ob.Unlock()
+
+		ob.resultCh <- schemas.ResultChannel{
+			DataType: dataTypeSnapshot,
+			Data:     book,
+			Error:    err,
 		}
+	}
+}
 
-		time.Sleep(d)
+func (ob *OrderBookGroup) handleUpdate(msg wsMessage) {
+	var update orderBookUpdate
+	if err := json.Unmarshal(msg.Data, &update); err != nil {
+		log.Println("[KUCOIN] Error parsing orderbook update: ", err)
+		return
+	}
+	ob.Lock()
+	sequence, ok := ob.sequences[update.Symbol]
+	ob.Unlock()
+	if !ok || update.SequenceEnd <= sequence {
+		return
+	}
+	ob.resultCh <- schemas.ResultChannel{
+		DataType: dataTypeUpdate,
+		Data:     update.Map(),
 	}
 }
 
 // Get - loading order books snapshot by symbols from exhange
 func (ob *OrderBookGroup) Get() (books map[string]schemas.OrderBook, err error) {
 	books = make(map[string]schemas.OrderBook)
-	var b []byte
-	var resp orderbookResponse
-
 	for _, symbol := range ob.symbols {
-		query := httpclient.Params()
-		query.Set("symbol", symbol.OriginalName)
-		query.Set("limit", "200")
-
-		if b, err = ob.httpClient.Get(apiOrderBook, query, false); err != nil {
-			log.Println("[KUCOIN] Error sending request", err)
-			return
-		}
-		if err = json.Unmarshal(b, &resp); err != nil {
-			return
-		}
-		if !resp.Success {
-			err = fmt.Errorf("[KUCOIN] Error getting orderbook: %v", resp.Message)
+		var book schemas.OrderBook
+		if book, _, err = ob.get(symbol); err != nil {
 			return
 		}
-
-		ordb := ob.mapSnapshot(symbol.Name, resp.Data)
-		books[symbol.Name] = ordb
+		books[symbol.Name] = book
 	}
-
 	return
 }
 
-func (ob *OrderBookGroup) mapSnapshot(symbol string, data map[string]interface{}) schemas.OrderBook {
-	book := schemas.OrderBook{
-		Symbol: symbol,
-	}
-	if _, ok := data["SELL"]; ok {
-		if s, ok := data["SELL"].([]interface{}); ok {
-			for _, el := range s {
-				if sell, ok := el.([]interface{}); ok {
-					book.Sell = append(book.Sell, schemas.Order{
-						Symbol: symbol,
-						Price:  sell[0].(float64),
-						Amount: sell[1].(float64),
-						Count:  1,
-					})
-				}
-			}
-		}
+func (ob *OrderBookGroup) get(symbol schemas.Symbol) (book schemas.OrderBook, sequence int64, err error) {
+	var b []byte
+	var resp orderBookSnapshot
+
+	query := httpclient.Params()
+	query.Set("symbol", symbol.OriginalName)
+	if b, err = ob.httpClient.Get(apiOrderBook, query, false); err != nil {
+		return
 	}
-	if _, ok := data["BUY"]; ok {
-		if s, ok := data["BUY"].([]interface{}); ok {
-			for _, el := range s {
-				if buy, ok := el.([]interface{}); ok {
-					book.Buy = append(book.Buy, schemas.Order{
-						Symbol: symbol,
-						Price:  buy[0].(float64),
-						Amount: buy[1].(float64),
-						Count:  1,
-					})
-				}
-			}
-		}
+	if err = parseResponse(b, &resp); err != nil {
+		return
 	}
-
-	return book
+	return resp.Map(symbol.Name), toInt(resp.Sequence), nil
 }
@@ -1,149 +1,68 @@
 package kucoin
 
 import (
-	"encoding/json"
-	"fmt"
 	"time"
 
-	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
-type allQuotesResp struct {
-	responseHeader
-	Data []quote `json:"data"`
-}
-
-type symbolQuoteResp struct {
-	responseHeader
-	Data quote `json:"data"`
-}
-
 // QuotesProvider - quotes provider structure
 type QuotesProvider struct {
-	symbols    []schemas.Symbol
-	httpClient *httpclient.Client
+	httpProxy proxy.Provider
+	groups    *listing.Groups
 }
 
 // NewQuotesProvider - QuotesProvider constructor
 func NewQuotesProvider(httpProxy proxy.Provider) *QuotesProvider {
-	proxyClient := httpProxy.NewClient(exchangeName)
-
-	return &QuotesProvider{
-		httpClient: httpclient.New(proxyClient),
+	qp := &QuotesProvider{
+		httpProxy: httpProxy,
 	}
+	qp.groups = listing.NewGroups(quotesSymbolsLimit, qp.startGroup)
+	return qp
 }
 
-// SetSymbols - getting all symbols from Exchange
+// SetSymbols - replacing symbols, they are split into groups by symbols chunks on subscription.
+// Symbols of running subscription are changed by AddSymbols and RemoveSymbols
 func (qp *QuotesProvider) SetSymbols(symbols []schemas.Symbol) schemas.QuotesProvider {
-	qp.symbols = symbols
+	qp.groups.Reset(symbols)
 	return qp
 }
 
-// Subscribe - subscribing to one symbol ticker updates
-func (qp *QuotesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan schemas.ResultChannel {
-	bufLength := len(qp.symbols)
-	ch := make(chan schemas.ResultChannel, 2*bufLength)
-
-	go func() {
-		for {
-			quote, err := qp.getBySymbol(symbol)
-			if err != nil {
-				ch <- schemas.ResultChannel{
-					Data:     quote,
-					Error:    err,
-					DataType: "s",
-				}
-				continue
-			}
-			ch <- schemas.ResultChannel{
-				Data:     quote,
-				Error:    err,
-				DataType: "s",
-			}
-
-			time.Sleep(d)
-		}
-	}()
-
-	return ch
-}
-
-// SubscribeAll - subscribing to all symbols ticker updates
+// SubscribeAll - subscribing all groups
 func (qp *QuotesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
-	bufLength := len(qp.symbols)
-	ch := make(chan schemas.ResultChannel, 2*bufLength)
-
-	go func() {
-		for {
-			quotes, err := qp.get()
-			if err != nil {
-				ch <- schemas.ResultChannel{
-					Data:     quotes,
-					Error:    err,
-					DataType: "s",
-				}
-				continue
-			}
-			for _, b := range quotes {
-				ch <- schemas.ResultChannel{
-					Data:     b,
-					Error:    err,
-					DataType: "s",
-				}
-			}
-			time.Sleep(d)
-		}
-	}()
-
-	return ch
+	return qp.groups.Subscribe(d)
 }
 
-// Get - getting tick by symbol
-func (qp *QuotesProvider) Get(symbol schemas.Symbol) (q schemas.Quote, err error) {
-	return qp.getBySymbol(symbol)
+// AddSymbols - adding symbols to running subscription, i.e. new listings
+func (qp *QuotesProvider) AddSymbols(symbols []schemas.Symbol) {
+	qp.groups.Add(symbols)
 }
 
-func (qp *QuotesProvider) get() (quotes []schemas.Quote, err error) {
-	var b []byte
-	var resp allQuotesResp
-
-	if b, err = qp.httpClient.Get(apiTicker, httpclient.Params(), false); err != nil {
-		return
-	}
-	if err = json.Unmarshal(b, &resp); err != nil {
-		return
-	}
-	if !resp.Success {
-		err = fmt.Errorf("Error getting ticker: %v", resp.Message)
-		return
-	}
-
-	for _, qt := range resp.Data {
-		quotes = append(quotes, qt.Map())
-	}
-
-	return
+// RemoveSymbols - removing symbols from subscription, i.e. delistings.
+// Groups subscribed to them are reconnected without them
+func (qp *QuotesProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	qp.groups.Remove(symbols)
 }
 
-func (qp *QuotesProvider) getBySymbol(symbol schemas.Symbol) (quote schemas.Quote, err error) {
-	var b []byte
-	var resp symbolQuoteResp
+// startGroup - creating and starting group of symbols
+func (qp *QuotesProvider) startGroup(symbols []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) listing.Group {
+	group := NewQuotesGroup(symbols, qp.httpProxy)
+	go group.Start(ch)
+	return group
+}
 
-	query := httpclient.Params()
-	query.Set("symbol", symbol.Name)
-	if b, err = qp.httpClient.Get(apiTicker, query, false); err != nil {
-		return
-	}
-	if err = json.Unmarshal(b, &resp); err != nil {
-		return
-	}
-	if !resp.Success {
-		err = fmt.Errorf("Error getting ticker: %v", resp.Message)
-		return
-	}
+// Get - getting quote by symbol
+func (qp *QuotesProvider) Get(symbol schemas.Symbol) (q schemas.Quote, err error) {
+	group := NewQuotesGroup([]schemas.Symbol{symbol}, qp.httpProxy)
+	return group.Get(symbol)
+}
 
-	quote = resp.Data.Map()
-	return
+// Subscribe - subscribing to one symbol ticker updates
+func (qp *QuotesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan schemas.ResultChannel {
+	ch := make(chan schemas.ResultChannel)
+	group := NewQuotesGroup([]schemas.Symbol{symbol}, qp.httpProxy)
+	go group.Start(ch)
+	return ch
 }
@@ -0,0 +1,81 @@
+package kucoin
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/schemas"
+	"github.com/syndicatedb/goproxy/proxy"
+)
+
+// snapshotMessage - snapshot topic message data
+type snapshotMessage struct {
+	Sequence int64  `json:"sequence"`
+	Data     ticker `json:"data"`
+}
+
+// QuotesGroup - quotes group structure, 24h stats snapshots by websocket
+type QuotesGroup struct {
+	symbols    []schemas.Symbol
+	httpClient *httpclient.Client
+	stream     *stream
+
+	resultCh chan schemas.ResultChannel
+}
+
+// NewQuotesGroup - QuotesGroup constructor
+func NewQuotesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *QuotesGroup {
+	proxyClient := httpProxy.NewClient(exchangeName)
+	httpClient := httpclient.New(proxyClient)
+
+	// snapshot topic accepts one symbol
+	var topics []string
+	for _, s := range symbols {
+		topics = append(topics, topicQuotes+s.OriginalName)
+	}
+	return &QuotesGroup{
+		symbols:    symbols,
+		httpClient: httpClient,
+		stream:     newStream(topics, false, httpClient, httpProxy),
+	}
+}
+
+// Start - subscribing to quotes updates
+func (qg *QuotesGroup) Start(ch chan schemas.ResultChannel) {
+	qg.resultCh = ch
+	qg.stream.Start(qg.handleUpdate)
+}
+
+// Stop - closing group connection
+func (qg *QuotesGroup) Stop() {
+	qg.stream.Stop()
+}
+
+func (qg *QuotesGroup) handleUpdate(msg wsMessage) {
+	var data snapshotMessage
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		log.Println("[KUCOIN] Error parsing quote: ", err)
+		return
+	}
+	qg.resultCh <- schemas.ResultChannel{
+		DataType: dataTypeUpdate,
+		Data:     data.Data.Map(),
+	}
+}
+
+// Get - getting 24h stats by symbol
+func (qg *QuotesGroup) Get(symbol schemas.Symbol) (quote schemas.Quote, err error) {
+	var b []byte
+	var resp ticker
+	query := httpclient.Params()
+	query.Set("symbol", symbol.OriginalName)
+
+	if b, err = qg.httpClient.Get(apiStats, query, false); err != nil {
+		return
+	}
+	if err = parseResponse(b, &resp); err != nil {
+		return
+	}
+	return resp.Map(), nil
+}
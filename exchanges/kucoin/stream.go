@@ -0,0 +1,229 @@
+package kucoin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/websocket"
+	"github.com/syndicatedb/goproxy/proxy"
+)
+
+const (
+	pingMessage = `{"id":"ping","type":"ping"}`
+	defaultPing = 18 * time.Second
+
+	reconnectDelay    = time.Second
+	maxReconnectDelay = time.Minute
+)
+
+/*
+bullet - websocket token and servers
+
+	{
+	  "token": "vYNlCtbz4XNJ1QncwWilJnBtmmfe4geLQDUA62kKJsDChc6I4bRDQc73JfIrlFaVYIAE",
+	  "instanceServers": [{
+	    "endpoint": "wss://push1-v2.kucoin.com/endpoint",
+	    "protocol": "websocket",
+	    "encrypt": true,
+	    "pingInterval": 50000,
+	    "pingTimeout": 10000
+	  }]
+	}
+*/
+type bullet struct {
+	Token           string `json:"token"`
+	InstanceServers []struct {
+		Endpoint     string `json:"endpoint"`
+		Protocol     string `json:"protocol"`
+		PingInterval int64  `json:"pingInterval"`
+	} `json:"instanceServers"`
+}
+
+// wsMessage - websocket message, both directions
+type wsMessage struct {
+	ID             string          `json:"id,omitempty"`
+	Type           string          `json:"type"`
+	Topic          string          `json:"topic,omitempty"`
+	Subject        string          `json:"subject,omitempty"`
+	PrivateChannel bool            `json:"privateChannel,omitempty"`
+	Response       bool            `json:"response,omitempty"`
+	Data           json.RawMessage `json:"data,omitempty"`
+}
+
+/*
+stream - websocket connection to topics.
+Kucoin websocket requires token, which is loaded before every connection,
+so stream is reconnecting with new token on errors, failed connections are retried with backoff
+*/
+type stream struct {
+	topics     []string
+	private    bool
+	httpClient *httpclient.Client
+	httpProxy  proxy.Provider
+	wsClient   *websocket.Client
+
+	handler   func(msg wsMessage)
+	onConnect func()
+
+	dch  chan []byte
+	stop chan struct{}
+}
+
+// newStream - stream constructor, httpClient has to be signed for private topics
+func newStream(topics []string, private bool, httpClient *httpclient.Client, httpProxy proxy.Provider) *stream {
+	return &stream{
+		topics:     topics,
+		private:    private,
+		httpClient: httpClient,
+		httpProxy:  httpProxy,
+		dch:        make(chan []byte, 100),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start - connecting and sending topics messages to handler, blocks while stream is running
+func (s *stream) Start(handler func(msg wsMessage)) {
+	s.handler = handler
+	s.listen()
+	s.run()
+}
+
+// Stop - closing connection, stream isn't reconnected
+func (s *stream) Stop() {
+	close(s.stop)
+}
+
+/*
+run - reconnect loop: connecting and waiting for connection error.
+Delay before reconnect is doubled after every failure up to maxReconnectDelay
+and reset when connection has been working longer than maxReconnectDelay
+*/
+func (s *stream) run() {
+	wait := reconnectDelay
+	for {
+		started := time.Now()
+		ech, err := s.connect()
+		if err == nil {
+			select {
+			case err = <-ech:
+			case <-s.stop:
+				s.disconnect()
+				return
+			}
+		}
+		if time.Since(started) > maxReconnectDelay {
+			wait = reconnectDelay
+		}
+		log.Printf("[KUCOIN] Stream error, reconnecting in %v: %v\n", wait, err)
+		s.disconnect()
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(wait):
+		}
+		if wait *= 2; wait > maxReconnectDelay {
+			wait = maxReconnectDelay
+		}
+	}
+}
+
+// disconnect - closing current connection
+func (s *stream) disconnect() {
+	if s.wsClient == nil {
+		return
+	}
+	if err := s.wsClient.Exit(); err != nil {
+		log.Println("[KUCOIN] Error destroying connection: ", err)
+	}
+}
+
+/*
+connect - loading token, connecting and subscribing to topics.
+Returns errors channel of connection, first error means connection is broken
+*/
+func (s *stream) connect() (ech chan error, err error) {
+	endpoint, ping, err := s.token()
+	if err != nil {
+		return nil, fmt.Errorf("getting websocket token: %v", err)
+	}
+	s.wsClient = websocket.NewClient(endpoint, s.httpProxy)
+	s.wsClient.UsePingMessage(pingMessage).SetKeepAliveTimeout(ping).ChangeKeepAlive(true)
+	if err = s.wsClient.Connect(); err != nil {
+		return nil, fmt.Errorf("connecting to kucoin WS API: %v", err)
+	}
+	// errors channel by connection: errors of closed connection don't restart new one
+	ech = make(chan error, 2)
+	s.wsClient.Listen(s.dch, ech)
+
+	for _, topic := range s.topics {
+		msg := wsMessage{
+			ID:             fmt.Sprintf("%d", time.Now().UnixNano()),
+			Type:           "subscribe",
+			Topic:          topic,
+			PrivateChannel: s.private,
+		}
+		if err = s.wsClient.Write(msg); err != nil {
+			return nil, fmt.Errorf("subscribing to %s: %v", topic, err)
+		}
+	}
+	if s.onConnect != nil {
+		s.onConnect()
+	}
+	return ech, nil
+}
+
+// listen - parsing messages and sending topics data to handler
+func (s *stream) listen() {
+	go func() {
+		for b := range s.dch {
+			var msg wsMessage
+			if err := json.Unmarshal(b, &msg); err != nil {
+				log.Println("[KUCOIN] Error parsing message: ", err)
+				continue
+			}
+			switch msg.Type {
+			case "message":
+				s.handler(msg)
+			case "error":
+				log.Println("[KUCOIN] Error message: ", string(msg.Data))
+			}
+		}
+	}()
+}
+
+// token - loading websocket token and building connection URL
+func (s *stream) token() (endpoint string, ping time.Duration, err error) {
+	var b []byte
+	var resp bullet
+	apiURL := apiBulletPublic
+	if s.private {
+		apiURL = apiBulletPrivate
+	}
+	if b, err = s.httpClient.Post(apiURL, httpclient.Params(), httpclient.Params(), s.private); err != nil {
+		return
+	}
+	if err = parseResponse(b, &resp); err != nil {
+		return
+	}
+	if len(resp.InstanceServers) == 0 {
+		err = errors.New("[KUCOIN] No websocket servers in token response")
+		return
+	}
+	server := resp.InstanceServers[0]
+	endpoint = fmt.Sprintf(
+		"%s?token=%s&connectId=%d",
+		server.Endpoint,
+		url.QueryEscape(resp.Token),
+		time.Now().UnixNano(),
+	)
+	ping = time.Duration(server.PingInterval) * time.Millisecond
+	if ping == 0 {
+		ping = defaultPing
+	}
+	return
+}
@@ -4,46 +4,53 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
 type TradesProvider struct {
-	symbols   []schemas.Symbol
-	groups    []*TradesGroup
 	httpProxy proxy.Provider
+	groups    *listing.Groups
 }
 
 // NewTradesProvider - TradesProvider constructor
 func NewTradesProvider(httpProxy proxy.Provider) *TradesProvider {
-	return &TradesProvider{
+	tp := &TradesProvider{
 		httpProxy: httpProxy,
 	}
+	tp.groups = listing.NewGroups(tradesSymbolsLimit, tp.startGroup)
+	return tp
 }
 
-// SetSymbols - getting all symbols from Exchange
+// SetSymbols - replacing symbols, they are split into groups by symbols chunks on subscription.
+// Symbols of running subscription are changed by AddSymbols and RemoveSymbols
 func (tp *TradesProvider) SetSymbols(symbols []schemas.Symbol) schemas.TradesProvider {
-	tp.symbols = symbols
-	slice := make([]schemas.Symbol, len(symbols))
-	copy(slice, symbols)
-	capacity := tradesSymbolsLimit
-	for {
-		if len(slice) <= capacity {
-			tp.groups = append(
-				tp.groups,
-				NewTradesGroup(slice, tp.httpProxy),
-			)
-			break
-		}
-		tp.groups = append(
-			tp.groups,
-			NewTradesGroup(slice[0:capacity], tp.httpProxy),
-		)
+	tp.groups.Reset(symbols)
+	return tp
+}
 
-		slice = slice[capacity:]
-	}
+// SubscribeAll - subscribing all groups
+func (tp *TradesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return tp.groups.Subscribe(d)
+}
 
-	return tp
+// AddSymbols - adding symbols to running subscription, i.e. new listings
+func (tp *TradesProvider) AddSymbols(symbols []schemas.Symbol) {
+	tp.groups.Add(symbols)
+}
+
+// RemoveSymbols - removing symbols from subscription, i.e. delistings.
+// Groups subscribed to them are reconnected without them
+func (tp *TradesProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	tp.groups.Remove(symbols)
+}
+
+// startGroup - creating and starting group of symbols
+func (tp *TradesProvider) startGroup(symbols []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) listing.Group {
+	group := NewTradesGroup(symbols, tp.httpProxy)
+	go group.Start(ch)
+	return group
 }
 
 // Get - getting quotes by symbol
@@ -66,18 +73,6 @@ func (tp *TradesProvider) Get(symbol schemas.Symbol) (q []schemas.Trade, err err
 func (tp *TradesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan schemas.ResultChannel {
 	ch := make(chan schemas.ResultChannel)
 	group := NewTradesGroup([]schemas.Symbol{symbol}, tp.httpProxy)
-	go group.Subscribe(ch, d)
-	return ch
-}
-
-// SubscribeAll - subscribing to all quotes with interval
-func (tp *TradesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
-	bufLength := len(tp.symbols)
-	ch := make(chan schemas.ResultChannel, 2*bufLength)
-
-	for _, group := range tp.groups {
-		go group.Subscribe(ch, d)
-		time.Sleep(100 * time.Millisecond)
-	}
+	go group.Start(ch)
 	return ch
 }
@@ -1,18 +1,24 @@
 package kucoin
 
 import (
+	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/importer"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
+const accountTypeTrade = "trade"
+
 // TradingProvider - provides quotes/ticker
 type TradingProvider struct {
 	credentials schemas.Credentials
@@ -38,49 +44,48 @@ func (trading *TradingProvider) SetSymbols(symbols []schemas.Symbol) schemas.Tra
 	return trading
 }
 
-// Info - provides user info: Keys access, balances
+// Info - provides user info: balances of trade accounts
 func (trading *TradingProvider) Info() (ui schemas.UserInfo, err error) {
 	var b []byte
+	var accounts []account
 	params := httpclient.Params()
-	params.Set("coin", "")
-	params.Set("nonce", fmt.Sprintf("%d", time.Now().Unix()))
+	params.Set("type", accountTypeTrade)
 
-	b, err = trading.httpClient.Get(apiUserBalance, params, true)
-	if err != nil {
+	if b, err = trading.httpClient.Get(apiUserBalance, params, true); err != nil {
 		return
 	}
-	var resp UserBalanceResponse
-	if err = json.Unmarshal(b, &resp); err != nil {
+	if err = parseResponse(b, &accounts); err != nil {
 		return
 	}
-	if resp.Success == false {
-		err = errors.New(resp.Msg)
-		return
+	balances := make(map[string]schemas.Balance)
+	for _, a := range accounts {
+		balances[a.Currency] = a.Map()
 	}
 	prices, err := trading.prices()
 	if err != nil {
 		log.Println("Error getting prices for balances", err)
 	}
-	return resp.Map(prices), nil
+	return schemas.UserInfo{
+		Balances: balances,
+		Prices:   prices,
+	}, nil
 }
 
 func (trading *TradingProvider) prices() (resp map[string]float64, err error) {
 	var b []byte
+	var tickers tickersResponse
 
-	b, err = trading.httpClient.Get(apiTicker, httpclient.Params(), false)
-	if err != nil {
+	if b, err = trading.httpClient.Get(apiTicker, httpclient.Params(), false); err != nil {
 		return
 	}
-
-	var prices allQuotesResp
-	if err = json.Unmarshal(b, &prices); err != nil {
+	if err = parseResponse(b, &tickers); err != nil {
 		return
 	}
 
 	resp = make(map[string]float64)
-	for _, p := range prices.Data {
-		symbol, _, _ := parseSymbol(p.Symbol)
-		resp[symbol] = p.LastDealPrice
+	for _, t := range tickers.Ticker {
+		symbol, _, _ := parseSymbol(t.Symbol)
+		resp[symbol] = t.Last.Float64()
 	}
 
 	return
@@ -91,18 +96,18 @@ Subscribe - subscribing to user info
 — user info
 - orders
 - trades
+Snapshots are loaded by REST, updates come from private websocket:
+balance changes, order changes and order matches as trades
 */
 func (trading *TradingProvider) Subscribe(interval time.Duration) (chan schemas.UserInfoChannel, chan schemas.UserOrdersChannel, chan schemas.UserTradesChannel) {
 	uic := make(chan schemas.UserInfoChannel)
 	uoc := make(chan schemas.UserOrdersChannel)
 	utc := make(chan schemas.UserTradesChannel)
 
-	if interval == 0 {
-		interval = SubscriptionInterval
-	}
-	lastTradeID := "1"
-	go func() {
-		for {
+	s := newStream([]string{topicOrders, topicBalance}, true, trading.httpClient, trading.httpProxy)
+	// snapshots after every connection, updates could be missed while reconnecting
+	s.onConnect = func() {
+		go func() {
 			ui, err := trading.Info()
 			uic <- schemas.UserInfoChannel{
 				DataType: dataTypeSnapshot,
@@ -115,190 +120,341 @@ func (trading *TradingProvider) Subscribe(interval time.Duration) (chan schemas.
 				Data:     o,
 				Error:    err,
 			}
-			t, _, err := trading.Trades(schemas.FilterOptions{
-				FromID: lastTradeID,
-			})
+			t, _, err := trading.Trades(schemas.FilterOptions{})
 			utc <- schemas.UserTradesChannel{
 				DataType: dataTypeSnapshot,
 				Data:     t,
 				Error:    err,
 			}
-			time.Sleep(interval)
+		}()
+	}
+	go s.Start(func(msg wsMessage) {
+		switch msg.Topic {
+		case topicOrders:
+			var oc orderChange
+			if err := json.Unmarshal(msg.Data, &oc); err != nil {
+				log.Println("[KUCOIN] Error parsing order change: ", err)
+				return
+			}
+			uoc <- schemas.UserOrdersChannel{
+				DataType: dataTypeUpdate,
+				Data:     []schemas.Order{oc.Map()},
+			}
+			if oc.Type == "match" {
+				utc <- schemas.UserTradesChannel{
+					DataType: dataTypeUpdate,
+					Data:     []schemas.Trade{oc.Trade()},
+				}
+			}
+		case topicBalance:
+			var bc balanceChange
+			if err := json.Unmarshal(msg.Data, &bc); err != nil {
+				log.Println("[KUCOIN] Error parsing balance change: ", err)
+				return
+			}
+			uic <- schemas.UserInfoChannel{
+				DataType: dataTypeUpdate,
+				Data: schemas.UserInfo{
+					Balances: map[string]schemas.Balance{bc.Currency: bc.Map()},
+				},
+			}
 		}
-	}()
+	})
 	return uic, uoc, utc
 }
 
-// Orders - getting user active orders
+// Orders - getting user active orders, all pages
 func (trading *TradingProvider) Orders(symbols []schemas.Symbol) (orders []schemas.Order, err error) {
-	var b []byte
-	b, err = trading.httpClient.Get(apiActiveOrders, httpclient.Params(), true)
-	if err != nil {
-		return
+	params := httpclient.Params()
+	params.Set("status", "active")
+	params.Set("pageSize", fmt.Sprintf("%d", pageSize))
+	if len(symbols) == 1 {
+		params.Set("symbol", symbols[0].OriginalName)
 	}
-	var resp UserOrdersResponse
-	if err = json.Unmarshal(b, &resp); err != nil {
-		return
+
+	for current := 1; ; current++ {
+		var b []byte
+		var resp paged
+		var items []order
+
+		params.Set("currentPage", fmt.Sprintf("%d", current))
+		if b, err = trading.httpClient.Get(apiActiveOrders, params, true); err != nil {
+			return
+		}
+		if err = parseResponse(b, &resp); err != nil {
+			return
+		}
+		if err = json.Unmarshal(resp.Items, &items); err != nil {
+			return
+		}
+		for _, o := range items {
+			orders = append(orders, o.Map())
+		}
+		if int64(current) >= resp.TotalPage {
+			break
+		}
 	}
-	if resp.Success == false {
-		err = errors.New(resp.Msg)
-		return
+	if len(symbols) > 1 {
+		orders = schemas.FilterOrders(orders, symbols, "")
 	}
-	return resp.Data.Map(), nil
+	return
 }
 
-// ImportTrades importing trades
+/*
+ImportTrades - importing fills of symbols, of all symbols if empty.
+Fills are paged by time windows from Since or checkpoint till Before or import start.
+Last message has DataTypeDone
+*/
 func (trading *TradingProvider) ImportTrades(opts schemas.FilterOptions) chan schemas.UserTradesChannel {
-	ch := make(chan schemas.UserTradesChannel)
 	if len(fmt.Sprintf("%d", opts.Before)) < 12 {
 		opts.Before = opts.Before * 1000
 	}
 	if len(fmt.Sprintf("%d", opts.Since)) < 12 {
 		opts.Since = opts.Since * 1000
 	}
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	if opts.Since == 0 {
+		opts.Since = now - int64(importHistory/time.Millisecond)
+	}
+	if opts.Before == 0 || opts.Before > now {
+		opts.Before = now
+	}
+	start := schemas.Checkpoint{Cursor: fmt.Sprintf("%d", opts.Since)}
 
-	_, paging, err := trading.Trades(opts)
-	if err != nil {
-		log.Println("[KUCOIN] Error loading trades, exiting: ", err)
-		return nil
-	}
-	opts.Page = int(paging.Pages)
-	go func() {
-		for {
-			trades, _, err := trading.Trades(opts)
-			if err != nil {
-				log.Println("[KUCOIN] Error loading trades: ", err)
-				continue
-			}
-			ch <- schemas.UserTradesChannel{
-				Data:  trades,
-				Error: err,
-			}
-			opts.Page = opts.Page - 1
-			if opts.Page < 1 {
+	// fills can be filtered by one symbol only, so every symbol is imported separately
+	tasks := []importer.Task{{
+		Symbol: "all",
+		Start:  start,
+		Page:   trading.tradesPage(nil, opts.Before),
+	}}
+	if len(opts.Symbols) > 0 {
+		tasks = nil
+		for _, s := range opts.Symbols {
+			tasks = append(tasks, importer.Task{
+				Symbol: s.Name,
+				Start:  start,
+				Page:   trading.tradesPage([]schemas.Symbol{s}, opts.Before),
+			})
+		}
+	}
+	return importer.Importer{
+		Exchange:    exchangeName,
+		APIKey:      trading.credentials.APIKey,
+		Checkpoints: opts.Checkpoints,
+		Acknowledge: opts.Acknowledge,
+		Delay:       time.Second,
+	}.Run(tasks)
+}
+
+/*
+tradesPage - loading fills of time window (Kucoin limit), every page of window.
+Cursor is start of next window in milliseconds, fills are sent sorted from oldest
+*/
+func (trading *TradingProvider) tradesPage(symbols []schemas.Symbol, end int64) importer.Page {
+	return func(cursor schemas.Checkpoint) (trades []schemas.Trade, next schemas.Checkpoint, done bool, err error) {
+		var from int64
+		if from, err = strconv.ParseInt(cursor.Cursor, 10, 64); err != nil {
+			return
+		}
+		to := from + int64(importWindow/time.Millisecond) - 1
+		if to >= end {
+			to = end
+			done = true
+		}
+		opts := schemas.FilterOptions{
+			Symbols: symbols,
+			Since:   from,
+			Before:  to,
+			Limit:   pageSize,
+		}
+		for opts.Page = 1; ; opts.Page++ {
+			var page []schemas.Trade
+			var p schemas.Paging
+			if page, p, err = trading.Trades(opts); err != nil {
 				return
 			}
-			time.Sleep(1 * time.Second)
+			trades = append(trades, page...)
+			if int64(opts.Page) >= p.Pages {
+				break
+			}
+			time.Sleep(time.Second)
 		}
-	}()
-	// log.Printf("paging: %d, %+v, %v", len(trades), paging, err)
-
-	return ch
+		sort.SliceStable(trades, func(i, j int) bool {
+			return trades[i].Timestamp < trades[j].Timestamp
+		})
+		next = schemas.Checkpoint{
+			Cursor:    fmt.Sprintf("%d", to+1),
+			Timestamp: cursor.Timestamp,
+		}
+		if len(trades) > 0 {
+			next.Timestamp = trades[len(trades)-1].Timestamp
+		}
+		return
+	}
 }
 
-// Trades - getting user trades
+// Trades - getting user trades (fills)
 func (trading *TradingProvider) Trades(opts schemas.FilterOptions) (trades []schemas.Trade, p schemas.Paging, err error) {
 	var b []byte
+	var resp paged
+	var items []fill
 	params := httpclient.Params()
 
-	if len(opts.Symbols) > 0 {
-		var pairs []string
-		for _, s := range opts.Symbols {
-			pairs = append(pairs, s.Name)
-		}
-		params.Set("symbol", strings.Join(pairs, ","))
+	// fills are filtered by one symbol only
+	if len(opts.Symbols) == 1 {
+		params.Set("symbol", opts.Symbols[0].OriginalName)
 	}
-
 	if opts.Limit > 0 {
-		params.Set("limit", fmt.Sprintf("%d", opts.Limit))
+		params.Set("pageSize", fmt.Sprintf("%d", opts.Limit))
 	}
-
 	if opts.Since != 0 {
-		params.Set("since", fmt.Sprintf("%d", opts.Since))
+		params.Set("startAt", fmt.Sprintf("%d", opts.Since))
 	}
 	if opts.Before != 0 {
-		params.Set("before", fmt.Sprintf("%d", opts.Before))
+		params.Set("endAt", fmt.Sprintf("%d", opts.Before))
 	}
 	if opts.Page != 0 {
-		params.Set("page", fmt.Sprintf("%d", opts.Page))
+		params.Set("currentPage", fmt.Sprintf("%d", opts.Page))
 	}
-	b, err = trading.httpClient.Get(apiUserTrades, params, true)
-	if err != nil {
+	if b, err = trading.httpClient.Get(apiUserTrades, params, true); err != nil {
 		return
 	}
-	var resp UserTradesResponse
-	if err = json.Unmarshal(b, &resp); err != nil {
+	if err = parseResponse(b, &resp); err != nil {
 		return
 	}
-	if resp.Success == false {
-		log.Printf("[KUCOIN] resp error: %+v\n", resp)
-		if resp.Code == "UNAUTH" {
-			err = errors.New(resp.Msg)
-			return
+	if err = json.Unmarshal(resp.Items, &items); err != nil {
+		return
+	}
+	for _, f := range items {
+		t := f.Map()
+		if len(opts.Symbols) > 1 && !hasSymbol(opts.Symbols, t.Symbol) {
+			continue
 		}
+		trades = append(trades, t)
 	}
-	return resp.Map(), schemas.Paging{
-		Count:   resp.Data.Total,
-		Pages:   resp.Data.PageNos,
-		Current: resp.Data.CurrPageNo,
-		Limit:   resp.Data.Limit,
+	return trades, schemas.Paging{
+		Count:   resp.TotalNum,
+		Pages:   resp.TotalPage,
+		Current: resp.CurrentPage,
+		Limit:   resp.PageSize,
 	}, nil
 }
 
-// Create - creating order
+// Create - creating limit order
 func (trading *TradingProvider) Create(order schemas.Order) (result schemas.Order, err error) {
 	var b []byte
-	params := httpclient.Params()
-
-	payload := httpclient.Params()
-	payload.Set("symbol", order.Symbol)
-	payload.Set("type", strings.ToUpper(order.Type))
-	payload.Set("price", fmt.Sprintf("%.10f", order.Price))
-	payload.Set("amount", fmt.Sprintf("%.10f", order.Amount))
-
-	b, err = trading.httpClient.Post(apiCreateOrder, params, payload, true)
-	if err != nil {
+	var resp orderCreateResponse
+	if order, err = schemas.PrepareOrder(trading.symbols, order); err != nil {
 		return
 	}
-	var resp OrderCreateResponse
-	if err = json.Unmarshal(b, &resp); err != nil {
+
+	payload := map[string]string{
+		"clientOid": fmt.Sprintf("%d", time.Now().UnixNano()),
+		"side":      strings.ToLower(order.Type),
+		"symbol":    order.Symbol,
+		"type":      "limit",
+		"price":     order.PriceValue(),
+		"size":      order.AmountValue(),
+	}
+	if b, err = trading.post(apiCreateOrder, payload); err != nil {
 		return
 	}
-	if resp.Success == false {
-		err = errors.New(resp.Msg)
+	if err = parseResponse(b, &resp); err != nil {
 		return
 	}
-	order.ID = resp.Data.OrderOid
-	order.CreatedAt = resp.Timestamp
+	order.ID = resp.OrderID
+	order.CreatedAt = time.Now().UnixNano() / int64(time.Millisecond)
+	order.Status = schemas.StatusNew
 	result = order
 	return
 }
 
-// Cancel - cancelling order
+// Cancel - cancelling order by ID
 func (trading *TradingProvider) Cancel(order schemas.Order) (err error) {
 	var b []byte
+	if b, err = trading.httpClient.Request(http.MethodDelete, apiCancelOrder+order.ID, httpclient.Params(), httpclient.Params(), true); err != nil {
+		return
+	}
+	return parseResponse(b, &orderCancelResponse{})
+}
 
-	params := httpclient.Params()
-	params.Set("symbol", order.Symbol)
-
-	payload := httpclient.Params()
-	payload.Set("symbol", order.Symbol)
-	payload.Set("orderOid", order.ID)
-	payload.Set("type", order.Type)
+// CancelAll - cancelling all orders
+func (trading *TradingProvider) CancelAll() (err error) {
+	var b []byte
+	if b, err = trading.httpClient.Request(http.MethodDelete, apiCancelAll, httpclient.Params(), httpclient.Params(), true); err != nil {
+		return
+	}
+	return parseResponse(b, &orderCancelResponse{})
+}
 
-	b, err = trading.httpClient.Post(apiCancelOrder, params, payload, true)
-	if err != nil {
+/*
+CancelAllBy - cancelling orders by symbols and side.
+Kucoin cancels all symbol orders natively, orders of one side are cancelled one by one
+*/
+func (trading *TradingProvider) CancelAllBy(symbols []schemas.Symbol, side string) (cancelled []schemas.Order, err error) {
+	var orders []schemas.Order
+	if len(symbols) == 0 {
+		return nil, schemas.ErrEmptySymbols
+	}
+	if orders, err = trading.Orders(symbols); err != nil {
 		return
 	}
-	var resp OrderCancelResponse
-	if err = json.Unmarshal(b, &resp); err != nil {
-		log.Println("err: ", err)
+	orders = schemas.FilterOrders(orders, symbols, side)
+	if side != "" {
+		for _, o := range orders {
+			if err = trading.Cancel(o); err != nil {
+				return
+			}
+			o.Status = schemas.StatusCancelled
+			cancelled = append(cancelled, o)
+		}
 		return
 	}
-	if resp.Success == false {
-		return errors.New(resp.Msg)
+
+	for _, s := range symbols {
+		var b []byte
+		var resp orderCancelResponse
+		params := httpclient.Params()
+		params.Set("symbol", s.OriginalName)
+
+		if b, err = trading.httpClient.Request(http.MethodDelete, apiCancelAll, params, httpclient.Params(), true); err != nil {
+			return
+		}
+		if err = parseResponse(b, &resp); err != nil {
+			return
+		}
+	}
+	for _, o := range orders {
+		o.Status = schemas.StatusCancelled
+		cancelled = append(cancelled, o)
 	}
 	return
 }
 
-// CancelAll - cancelling all orders
-func (trading *TradingProvider) CancelAll() (err error) {
-	var orders []schemas.Order
-	if orders, err = trading.Orders([]schemas.Symbol{}); err != nil {
+/*
+post - signed POST with JSON body.
+httpclient puts payload into query too, kucoin signs body and path separately
+*/
+func (trading *TradingProvider) post(url string, payload interface{}) (b []byte, err error) {
+	var body []byte
+	var req *http.Request
+	if body, err = json.Marshal(payload); err != nil {
 		return
 	}
-	for _, o := range orders {
-		err = trading.Cancel(o)
+	if req, err = http.NewRequest(http.MethodPost, url, bytes.NewReader(body)); err != nil {
+		return
 	}
-	return
+	req.Header.Add("Content-Type", httpclient.ContentTypeJSON)
+	req = trading.credentials.Sign(trading.credentials.APIKey, trading.credentials.APISecret, req)
+	return trading.httpClient.Do(req)
+}
+
+// hasSymbol - checking trade symbol name is in list
+func hasSymbol(symbols []schemas.Symbol, name string) bool {
+	for _, s := range symbols {
+		if s.Name == name || s.OriginalName == name {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,202 @@
+package paper
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+/*
+book - order book levels of symbol by price.
+Levels taken by paper fills are reduced until exchange updates them
+*/
+type book struct {
+	buy  map[float64]float64
+	sell map[float64]float64
+}
+
+func newBook() *book {
+	return &book{
+		buy:  make(map[float64]float64),
+		sell: make(map[float64]float64),
+	}
+}
+
+// side - levels of order side: bids for buying, asks for selling
+func (b *book) side(orderType string) map[float64]float64 {
+	if orderType == schemas.TypeBuy {
+		return b.buy
+	}
+	return b.sell
+}
+
+// opposite - levels order is matched with: asks for buying, bids for selling
+func (b *book) opposite(orderType string) map[float64]float64 {
+	if orderType == schemas.TypeBuy {
+		return b.sell
+	}
+	return b.buy
+}
+
+/*
+ApplyBook - applying order book snapshot or update of symbol (OrdersProvider data)
+and filling open orders which book has reached: they are filled by their price with maker fee.
+Amount ahead of order in queue is reduced when its price level is reduced
+*/
+func (p *TradingProvider) ApplyBook(symbol schemas.Symbol, msg schemas.ResultChannel) {
+	if msg.Error != nil {
+		return
+	}
+	var books []schemas.OrderBook
+	switch v := msg.Data.(type) {
+	case schemas.OrderBook:
+		books = []schemas.OrderBook{v}
+	case []schemas.OrderBook:
+		books = v
+	default:
+		return
+	}
+
+	var ev events
+	p.Lock()
+	p.advance(&ev)
+	for _, ob := range books {
+		if !isSymbol(symbol, ob.Symbol) {
+			continue
+		}
+		b := p.books[symbol.Name]
+		if b == nil || msg.DataType == schemas.DataTypeSnapshot {
+			b = newBook()
+			p.books[symbol.Name] = b
+		}
+		schemas.ApplyLevels(b.buy, ob.Buy)
+		schemas.ApplyLevels(b.sell, ob.Sell)
+		for _, o := range sortedOrders(p.orders) {
+			if o.Symbol != symbol.Name || !o.active {
+				continue
+			}
+			p.match(o, b, false, &ev)
+			o.ahead = math.Min(o.ahead, b.side(o.Type)[o.Price])
+		}
+	}
+	p.Unlock()
+	p.emit(ev)
+}
+
+/*
+ApplyTrades - filling open orders by public trades of symbol (TradesProvider data)
+by order price with maker fee: trade through order price fills it,
+trade at order price fills it after amount ahead of it in queue
+*/
+func (p *TradingProvider) ApplyTrades(symbol schemas.Symbol, msg schemas.ResultChannel) {
+	if msg.Error != nil || msg.DataType == schemas.DataTypeSnapshot {
+		return
+	}
+	var trades []schemas.Trade
+	switch v := msg.Data.(type) {
+	case schemas.Trade:
+		trades = []schemas.Trade{v}
+	case []schemas.Trade:
+		trades = v
+	case [][]schemas.Trade:
+		for _, t := range v {
+			trades = append(trades, t...)
+		}
+	default:
+		return
+	}
+
+	var ev events
+	p.Lock()
+	p.advance(&ev)
+	for _, t := range trades {
+		if !isSymbol(symbol, t.Symbol) {
+			continue
+		}
+		left := t.Amount
+		for _, o := range sortedOrders(p.orders) {
+			if left <= amountEpsilon {
+				break
+			}
+			if o.Symbol != symbol.Name || !o.active || !crosses(o.Type, o.Price, t.Price) {
+				continue
+			}
+			if o.Price == t.Price {
+				queued := math.Min(left, o.ahead)
+				o.ahead -= queued
+				if left -= queued; left <= amountEpsilon {
+					break
+				}
+			}
+			amount := math.Min(left, o.remaining())
+			p.fill(o, amount, o.Price, false, &ev)
+			left -= amount
+		}
+	}
+	p.Unlock()
+	p.emit(ev)
+}
+
+/*
+match - filling order by opposite book levels it crosses, taken amounts are removed from levels.
+Taker order gets levels prices, resting order reached by book gets its own price.
+Has to be called under lock
+*/
+func (p *TradingProvider) match(o *order, b *book, taker bool, ev *events) {
+	levels := b.opposite(o.Type)
+	prices := make([]float64, 0, len(levels))
+	for price := range levels {
+		if crosses(o.Type, o.Price, price) {
+			prices = append(prices, price)
+		}
+	}
+	// best levels first: lowest asks for buying, highest bids for selling
+	sort.Float64s(prices)
+	if o.Type == schemas.TypeSell {
+		sort.Sort(sort.Reverse(sort.Float64Slice(prices)))
+	}
+	for _, price := range prices {
+		remaining := o.remaining()
+		if remaining <= amountEpsilon {
+			return
+		}
+		amount := math.Min(levels[price], remaining)
+		if levels[price] -= amount; levels[price] <= amountEpsilon {
+			delete(levels, price)
+		}
+		fillPrice := o.Price
+		if taker {
+			fillPrice = price
+		}
+		p.fill(o, amount, fillPrice, taker, ev)
+	}
+}
+
+// crosses - order of side and limit price can be filled by price
+func crosses(side string, limit, price float64) bool {
+	if side == schemas.TypeBuy {
+		return price <= limit
+	}
+	return price >= limit
+}
+
+// sortedOrders - orders by creation, so older orders are filled first
+func sortedOrders(orders map[string]*order) []*order {
+	result := make([]*order, 0, len(orders))
+	for _, o := range orders {
+		result = append(result, o)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, _ := strconv.ParseInt(result[i].ID, 10, 64)
+		b, _ := strconv.ParseInt(result[j].ID, 10, 64)
+		return a < b
+	})
+	return result
+}
+
+// isSymbol - data symbol matches common or original symbol name, data without symbol matches any
+func isSymbol(symbol schemas.Symbol, name string) bool {
+	return name == "" || name == symbol.Name || name == symbol.OriginalName
+}
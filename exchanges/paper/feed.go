@@ -0,0 +1,54 @@
+package paper
+
+import (
+	"log"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// liveInterval - subscription interval of exchanges polling market data
+const liveInterval = time.Second
+
+// Feed - market data source orders are filled by
+type Feed interface {
+	// Watch - starting to apply order book and trades of symbol to provider
+	Watch(symbol schemas.Symbol, provider *TradingProvider)
+}
+
+// LiveFeed - market data of real exchange
+type LiveFeed struct {
+	books  schemas.OrdersProvider
+	trades schemas.TradesProvider
+}
+
+// NewLiveFeed - LiveFeed constructor
+func NewLiveFeed(books schemas.OrdersProvider, trades schemas.TradesProvider) *LiveFeed {
+	return &LiveFeed{
+		books:  books,
+		trades: trades,
+	}
+}
+
+// Watch - loading order book snapshot, so first order is matched with it, and subscribing to symbol
+func (f *LiveFeed) Watch(symbol schemas.Symbol, provider *TradingProvider) {
+	book, err := f.books.Get(symbol)
+	if err != nil {
+		log.Println("[PAPER] Error loading order book:", symbol.Name, err)
+	} else {
+		provider.ApplyBook(symbol, schemas.ResultChannel{
+			DataType: schemas.DataTypeSnapshot,
+			Data:     book,
+		})
+	}
+	go func() {
+		for msg := range f.books.Subscribe(symbol, liveInterval) {
+			provider.ApplyBook(symbol, msg)
+		}
+	}()
+	go func() {
+		for msg := range f.trades.Subscribe(symbol, liveInterval) {
+			provider.ApplyTrades(symbol, msg)
+		}
+	}()
+}
@@ -0,0 +1,652 @@
+/*
+Package paper provides simulated TradingProvider: virtual balances,
+limit orders filled against market data of real exchange.
+Fees are charged in quote coin
+*/
+package paper
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+const (
+	channelsBufferSize = 100
+
+	// amountEpsilon - float tail ignored when comparing amounts
+	amountEpsilon = 1e-9
+)
+
+// ErrOrderNotFound - cancelled order is not open
+var ErrOrderNotFound = errors.New("Order not found")
+
+// order - open order and balance locked by it
+type order struct {
+	schemas.Order
+	base, quote string
+	// quote coin locked by buy order per base unit: price with max fee
+	unitLock float64
+	// balance locked by remaining amount: quote coin for buying, base coin for selling
+	locked float64
+
+	// order is matched after it's active, cancelled when cancel time is reached
+	active     bool
+	activeAt   time.Time
+	cancelling bool
+	cancelAt   time.Time
+	// book amount at order price before order in queue
+	ahead float64
+}
+
+func (o *order) remaining() float64 {
+	return o.Amount - o.AmountFilled
+}
+
+// events - changes sent to subscription after lock is released
+type events struct {
+	orders []schemas.Order
+	trades []schemas.Trade
+	info   bool
+}
+
+// TradingProvider - paper trading provider
+type TradingProvider struct {
+	opts    schemas.PaperOptions
+	symbols []schemas.Symbol
+	feed    Feed
+	now     func() time.Time
+
+	balances map[string]schemas.Balance
+	orders   map[string]*order
+	trades   []schemas.Trade
+	history  []*order
+	books    map[string]*book
+	watched  map[string]bool
+	lastID   int64
+	clocked  bool
+
+	subscribed bool
+	uic        chan schemas.UserInfoChannel
+	uoc        chan schemas.UserOrdersChannel
+	utc        chan schemas.UserTradesChannel
+	// messages waiting to be sent to subscription channels, in order of changes
+	outbox []interface{}
+	notify chan struct{}
+
+	sync.Mutex
+}
+
+/*
+NewTradingProvider - paper TradingProvider constructor.
+Feed is watched for symbol when its first order is created,
+nil feed is for market data applied with ApplyBook and ApplyTrades only
+*/
+func NewTradingProvider(opts schemas.PaperOptions, feed Feed) *TradingProvider {
+	p := &TradingProvider{
+		opts:     opts,
+		feed:     feed,
+		now:      time.Now,
+		balances: make(map[string]schemas.Balance),
+		orders:   make(map[string]*order),
+		books:    make(map[string]*book),
+		watched:  make(map[string]bool),
+		uic:      make(chan schemas.UserInfoChannel, channelsBufferSize),
+		uoc:      make(chan schemas.UserOrdersChannel, channelsBufferSize),
+		utc:      make(chan schemas.UserTradesChannel, channelsBufferSize),
+		notify:   make(chan struct{}, 1),
+	}
+	for coin, amount := range opts.Balances {
+		coin = strings.ToUpper(coin)
+		p.balances[coin] = schemas.Balance{
+			Coin:      coin,
+			Available: amount,
+			Total:     amount,
+		}
+	}
+	return p
+}
+
+// SetSymbols - setting symbols orders are validated by
+func (p *TradingProvider) SetSymbols(symbols []schemas.Symbol) *TradingProvider {
+	p.Lock()
+	defer p.Unlock()
+	p.symbols = symbols
+	return p
+}
+
+/*
+SetClock - setting time source of orders and trades, i.e. market data time when replaying it.
+Latency is counted by clock then: Create and Cancel return at once,
+order is active and cancel is done by first ApplyBook or ApplyTrades after latency has passed
+*/
+func (p *TradingProvider) SetClock(now func() time.Time) *TradingProvider {
+	p.Lock()
+	defer p.Unlock()
+	p.now = now
+	p.clocked = true
+	return p
+}
+
+// Info - virtual balances
+func (p *TradingProvider) Info() (schemas.UserInfo, error) {
+	p.Lock()
+	defer p.Unlock()
+	return p.info(), nil
+}
+
+// Orders - open orders of symbols, all open orders if symbols are empty
+func (p *TradingProvider) Orders(symbols []schemas.Symbol) ([]schemas.Order, error) {
+	p.Lock()
+	orders := p.openOrders()
+	p.Unlock()
+	if len(symbols) == 0 {
+		return orders, nil
+	}
+	return schemas.FilterOrders(orders, symbols, ""), nil
+}
+
+// History - all created orders with their last state, oldest first
+func (p *TradingProvider) History() []schemas.Order {
+	p.Lock()
+	defer p.Unlock()
+	orders := make([]schemas.Order, len(p.history))
+	for i, o := range p.history {
+		orders[i] = o.Order
+	}
+	return orders
+}
+
+// Trades - executed trades by filter options, oldest first
+func (p *TradingProvider) Trades(opts schemas.FilterOptions) (trades []schemas.Trade, paging schemas.Paging, err error) {
+	names := make(map[string]bool)
+	for _, s := range opts.Symbols {
+		names[s.Name] = true
+		names[s.OriginalName] = true
+	}
+	since, before := toMilliseconds(opts.Since), toMilliseconds(opts.Before)
+
+	p.Lock()
+	for _, t := range p.trades {
+		if len(names) > 0 && !names[t.Symbol] {
+			continue
+		}
+		if (since > 0 && t.Timestamp < since) || (before > 0 && t.Timestamp >= before) {
+			continue
+		}
+		trades = append(trades, t)
+	}
+	p.Unlock()
+
+	paging.Count = int64(len(trades))
+	paging.Limit = opts.Limit
+	paging.Pages = 1
+	paging.Current = 1
+	skip := opts.Skip
+	if opts.Limit > 0 {
+		paging.Pages = int64(math.Ceil(float64(len(trades)) / float64(opts.Limit)))
+		if opts.Page > 0 {
+			paging.Current = int64(opts.Page)
+			skip += (opts.Page - 1) * opts.Limit
+		}
+	}
+	if skip >= len(trades) {
+		return nil, paging, nil
+	}
+	trades = trades[skip:]
+	if opts.Limit > 0 && len(trades) > opts.Limit {
+		trades = trades[:opts.Limit]
+	}
+	return
+}
+
+// ImportTrades - all executed trades by filter options in one message, then final status
+func (p *TradingProvider) ImportTrades(opts schemas.FilterOptions) chan schemas.UserTradesChannel {
+	ch := make(chan schemas.UserTradesChannel, 2)
+	opts.Limit = 0
+	opts.Page = 0
+	trades, _, err := p.Trades(opts)
+	if err == nil {
+		ch <- schemas.UserTradesChannel{
+			DataType: schemas.DataTypeUpdate,
+			Data:     trades,
+		}
+	}
+	ch <- schemas.UserTradesChannel{
+		DataType: schemas.DataTypeDone,
+		Error:    err,
+	}
+	close(ch)
+	return ch
+}
+
+/*
+Subscribe - balances, open orders and trades snapshots, then updates on every change.
+Changes are pushed, interval is not used. Every call returns the same channels.
+Messages are queued and sent by separate goroutine, so slow reader doesn't block trading
+*/
+func (p *TradingProvider) Subscribe(d time.Duration) (chan schemas.UserInfoChannel, chan schemas.UserOrdersChannel, chan schemas.UserTradesChannel) {
+	p.Lock()
+	defer p.Unlock()
+	if !p.subscribed {
+		p.subscribed = true
+		trades := make([]schemas.Trade, len(p.trades))
+		copy(trades, p.trades)
+		p.push(
+			schemas.UserInfoChannel{
+				Data:     p.info(),
+				DataType: schemas.DataTypeSnapshot,
+			},
+			schemas.UserOrdersChannel{
+				Data:     p.openOrders(),
+				DataType: schemas.DataTypeSnapshot,
+			},
+			schemas.UserTradesChannel{
+				Data:     trades,
+				DataType: schemas.DataTypeSnapshot,
+			},
+		)
+		go p.send()
+	}
+	return p.uic, p.uoc, p.utc
+}
+
+/*
+Create - placing limit order after latency.
+Balance is locked (quote coin with max fee for buying, base coin for selling),
+part crossing order book is filled at once by book prices with taker fee,
+rest is open until market reaches its price
+*/
+func (p *TradingProvider) Create(o schemas.Order) (result schemas.Order, err error) {
+	p.sleep()
+	o.Type = strings.ToUpper(o.Type)
+	if o.Type != schemas.TypeBuy && o.Type != schemas.TypeSell {
+		err = fmt.Errorf("Invalid order side: %s", o.Type)
+		return
+	}
+	symbol, ok := p.symbol(o.Symbol)
+	if !ok {
+		err = fmt.Errorf("Unknown symbol: %s", o.Symbol)
+		return
+	}
+	if o, err = symbol.ValidateOrder(o); err != nil {
+		return
+	}
+	if o.Price <= 0 || o.Amount <= 0 {
+		err = fmt.Errorf("Invalid order price %v or amount %v", o.Price, o.Amount)
+		return
+	}
+	coins := strings.Split(symbol.Name, "-")
+	if len(coins) != 2 {
+		err = fmt.Errorf("Invalid symbol: %s", symbol.Name)
+		return
+	}
+	p.watch(symbol)
+
+	var ev events
+	p.Lock()
+	maker, taker := p.fees(symbol)
+	open := &order{
+		Order:    o,
+		base:     coins[0],
+		quote:    coins[1],
+		unitLock: o.Price * (1 + math.Max(maker, taker)),
+	}
+	open.Symbol = symbol.Name
+	open.AmountFilled = 0
+	open.Status = schemas.StatusNew
+	coin, lock := open.base, o.Amount
+	if o.Type == schemas.TypeBuy {
+		coin, lock = open.quote, o.Amount*open.unitLock
+	}
+	balance := p.balances[coin]
+	if balance.Available < lock-amountEpsilon {
+		p.Unlock()
+		err = fmt.Errorf("Insufficient %s balance: %v, required %v", coin, balance.Available, lock)
+		return
+	}
+	p.addBalance(coin, -lock, lock)
+	open.locked = lock
+	p.lastID++
+	open.ID = strconv.FormatInt(p.lastID, 10)
+	open.CreatedAt = p.timestamp()
+	p.orders[open.ID] = open
+	p.history = append(p.history, open)
+	ev.orders = append(ev.orders, open.Order)
+	ev.info = true
+
+	if p.clocked {
+		open.activeAt = p.now().Add(p.opts.Latency)
+		p.advance(&ev)
+	} else {
+		p.activate(open, &ev)
+	}
+	result = open.Order
+	p.Unlock()
+	p.emit(ev)
+	return
+}
+
+// Cancel - cancelling open order by ID after latency, locked balance is released
+func (p *TradingProvider) Cancel(o schemas.Order) (err error) {
+	p.sleep()
+	var ev events
+	p.Lock()
+	open, ok := p.orders[o.ID]
+	if ok {
+		p.requestCancel(open, &ev)
+	}
+	p.Unlock()
+	if !ok {
+		return ErrOrderNotFound
+	}
+	p.emit(ev)
+	return
+}
+
+// CancelAll - cancelling all open orders
+func (p *TradingProvider) CancelAll() (err error) {
+	p.sleep()
+	var ev events
+	p.Lock()
+	for _, o := range sortedOrders(p.orders) {
+		p.requestCancel(o, &ev)
+	}
+	p.Unlock()
+	p.emit(ev)
+	return
+}
+
+// CancelAllBy - cancelling open orders of symbols and side, empty side cancels both sides
+func (p *TradingProvider) CancelAllBy(symbols []schemas.Symbol, side string) (cancelled []schemas.Order, err error) {
+	if len(symbols) == 0 {
+		return nil, schemas.ErrEmptySymbols
+	}
+	p.sleep()
+	var ev events
+	p.Lock()
+	cancelled = schemas.FilterOrders(p.openOrders(), symbols, side)
+	for _, o := range cancelled {
+		p.requestCancel(p.orders[o.ID], &ev)
+	}
+	p.Unlock()
+	p.emit(ev)
+	return
+}
+
+// requestCancel - cancelling order now or scheduling cancel by clock, has to be called under lock
+func (p *TradingProvider) requestCancel(o *order, ev *events) {
+	if !p.clocked {
+		p.cancel(o, ev)
+		return
+	}
+	if !o.cancelling {
+		o.cancelling = true
+		o.cancelAt = p.now().Add(p.opts.Latency)
+	}
+	p.advance(ev)
+}
+
+/*
+advance - activating orders and cancelling them when their latency has passed by clock.
+Has to be called under lock
+*/
+func (p *TradingProvider) advance(ev *events) {
+	if !p.clocked {
+		return
+	}
+	now := p.now()
+	for _, o := range sortedOrders(p.orders) {
+		if !o.active && !now.Before(o.activeAt) {
+			p.activate(o, ev)
+		}
+		if _, open := p.orders[o.ID]; open && o.cancelling && !now.Before(o.cancelAt) {
+			p.cancel(o, ev)
+		}
+	}
+}
+
+/*
+activate - matching new order with book as taker, rest of it is queued behind book amount at its price.
+Has to be called under lock
+*/
+func (p *TradingProvider) activate(o *order, ev *events) {
+	o.active = true
+	b := p.books[o.Symbol]
+	if b == nil {
+		return
+	}
+	p.match(o, b, true, ev)
+	if _, open := p.orders[o.ID]; open {
+		o.ahead = b.side(o.Type)[o.Price]
+	}
+}
+
+// sleep - waiting for latency, unless it's counted by clock
+func (p *TradingProvider) sleep() {
+	p.Lock()
+	clocked := p.clocked
+	p.Unlock()
+	if !clocked {
+		time.Sleep(p.opts.Latency)
+	}
+}
+
+// cancel - has to be called under lock
+func (p *TradingProvider) cancel(o *order, ev *events) {
+	p.release(o)
+	o.Status = schemas.StatusCancelled
+	delete(p.orders, o.ID)
+	ev.orders = append(ev.orders, o.Order)
+	ev.info = true
+}
+
+/*
+fill - executing amount of order by price, fee is charged in quote coin.
+Filled order is closed and rest of locked balance is released.
+Has to be called under lock
+*/
+func (p *TradingProvider) fill(o *order, amount, price float64, taker bool, ev *events) {
+	symbol, _ := p.symbol(o.Symbol)
+	maker, takerFee := p.fees(symbol)
+	rate := maker
+	if taker {
+		rate = takerFee
+	}
+	cost := amount * price
+	fee := cost * rate
+	if o.Type == schemas.TypeBuy {
+		unlock := math.Min(amount*o.unitLock, o.locked)
+		o.locked -= unlock
+		p.addBalance(o.quote, unlock-cost-fee, -unlock)
+		p.addBalance(o.base, amount, 0)
+	} else {
+		unlock := math.Min(amount, o.locked)
+		o.locked -= unlock
+		p.addBalance(o.base, 0, -unlock)
+		p.addBalance(o.quote, cost-fee, 0)
+	}
+	o.AmountFilled += amount
+	o.Status = schemas.StatusTrade
+	if o.remaining() <= amountEpsilon {
+		o.AmountFilled = o.Amount
+		p.release(o)
+		delete(p.orders, o.ID)
+	}
+
+	p.lastID++
+	trade := schemas.Trade{
+		ID:        strconv.FormatInt(p.lastID, 10),
+		OrderID:   o.ID,
+		Symbol:    o.Symbol,
+		Type:      o.Type,
+		Price:     price,
+		Amount:    amount,
+		Fee:       fee,
+		Timestamp: p.timestamp(),
+	}
+	p.trades = append(p.trades, trade)
+	ev.trades = append(ev.trades, trade)
+	ev.orders = append(ev.orders, o.Order)
+	ev.info = true
+}
+
+// release - unlocking balance locked by order, has to be called under lock
+func (p *TradingProvider) release(o *order) {
+	coin := o.base
+	if o.Type == schemas.TypeBuy {
+		coin = o.quote
+	}
+	p.addBalance(coin, o.locked, -o.locked)
+	o.locked = 0
+}
+
+// addBalance - changing available and locked amounts of coin, has to be called under lock
+func (p *TradingProvider) addBalance(coin string, available, inOrders float64) {
+	b := p.balances[coin]
+	b.Coin = coin
+	b.Available += available
+	b.InOrders += inOrders
+	if math.Abs(b.InOrders) < amountEpsilon {
+		b.InOrders = 0
+	}
+	b.Total = b.Available + b.InOrders
+	p.balances[coin] = b
+}
+
+// fees - maker and taker rates: options or symbol ones
+func (p *TradingProvider) fees(symbol schemas.Symbol) (maker, taker float64) {
+	if p.opts.MakerFee != 0 || p.opts.TakerFee != 0 {
+		return p.opts.MakerFee, p.opts.TakerFee
+	}
+	taker = symbol.TakerFee
+	if taker == 0 {
+		taker = symbol.Fee
+	}
+	maker = symbol.MakerFee
+	if maker == 0 {
+		maker = taker
+	}
+	return
+}
+
+// symbol - symbol by common or original name, there are no symbols until SetSymbols
+func (p *TradingProvider) symbol(name string) (schemas.Symbol, bool) {
+	for _, s := range p.symbols {
+		if s.Name == name || s.OriginalName == name {
+			return s, true
+		}
+	}
+	return schemas.Symbol{}, false
+}
+
+// watch - starting market data feed of symbol once
+func (p *TradingProvider) watch(symbol schemas.Symbol) {
+	p.Lock()
+	watched := p.watched[symbol.Name]
+	p.watched[symbol.Name] = true
+	p.Unlock()
+	if !watched && p.feed != nil {
+		p.feed.Watch(symbol, p)
+	}
+}
+
+// info - has to be called under lock
+func (p *TradingProvider) info() schemas.UserInfo {
+	balances := make(map[string]schemas.Balance)
+	for coin, b := range p.balances {
+		balances[coin] = b
+	}
+	return schemas.UserInfo{
+		Access: schemas.Access{
+			Read:  true,
+			Trade: true,
+		},
+		Balances:    balances,
+		TradesCount: int32(len(p.trades)),
+		OrdersCount: int32(len(p.orders)),
+	}
+}
+
+// openOrders - open orders by creation, has to be called under lock
+func (p *TradingProvider) openOrders() []schemas.Order {
+	orders := make([]schemas.Order, 0, len(p.orders))
+	for _, o := range sortedOrders(p.orders) {
+		orders = append(orders, o.Order)
+	}
+	return orders
+}
+
+func (p *TradingProvider) timestamp() int64 {
+	return p.now().UnixNano() / int64(time.Millisecond)
+}
+
+// emit - queueing changes to subscription, has to be called without lock
+func (p *TradingProvider) emit(ev events) {
+	p.Lock()
+	defer p.Unlock()
+	if !p.subscribed {
+		return
+	}
+	if len(ev.orders) > 0 {
+		p.push(schemas.UserOrdersChannel{
+			Data:     ev.orders,
+			DataType: schemas.DataTypeUpdate,
+		})
+	}
+	if len(ev.trades) > 0 {
+		p.push(schemas.UserTradesChannel{
+			Data:     ev.trades,
+			DataType: schemas.DataTypeUpdate,
+		})
+	}
+	if ev.info {
+		p.push(schemas.UserInfoChannel{
+			Data:     p.info(),
+			DataType: schemas.DataTypeUpdate,
+		})
+	}
+}
+
+// push - queueing messages to subscription channels, has to be called under lock
+func (p *TradingProvider) push(messages ...interface{}) {
+	p.outbox = append(p.outbox, messages...)
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// send - sending queued messages to subscription channels without lock, in order they were queued
+func (p *TradingProvider) send() {
+	for range p.notify {
+		p.Lock()
+		messages := p.outbox
+		p.outbox = nil
+		p.Unlock()
+		for _, msg := range messages {
+			switch m := msg.(type) {
+			case schemas.UserInfoChannel:
+				p.uic <- m
+			case schemas.UserOrdersChannel:
+				p.uoc <- m
+			case schemas.UserTradesChannel:
+				p.utc <- m
+			}
+		}
+	}
+}
+
+// toMilliseconds - filter time can be set in seconds or milliseconds
+func toMilliseconds(ts int64) int64 {
+	if ts > 0 && ts < 1e12 {
+		return ts * 1000
+	}
+	return ts
+}
@@ -0,0 +1,195 @@
+package paper
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+var ethBTC = schemas.Symbol{Name: "ETH-BTC", OriginalName: "ETHBTC"}
+
+func newTestProvider() *TradingProvider {
+	return NewTradingProvider(schemas.PaperOptions{
+		Balances: map[string]float64{"BTC": 1000, "ETH": 100},
+		MakerFee: 0.001,
+		TakerFee: 0.002,
+	}, nil).SetSymbols([]schemas.Symbol{ethBTC})
+}
+
+func levels(prices ...float64) (orders []schemas.Order) {
+	for i := 0; i < len(prices); i += 2 {
+		orders = append(orders, schemas.Order{Price: prices[i], Amount: prices[i+1]})
+	}
+	return
+}
+
+func applyBook(p *TradingProvider, dataType string, buy, sell []schemas.Order) {
+	p.ApplyBook(ethBTC, schemas.ResultChannel{
+		DataType: dataType,
+		Data:     schemas.OrderBook{Symbol: ethBTC.Name, Buy: buy, Sell: sell},
+	})
+}
+
+func applyTrade(p *TradingProvider, price, amount float64) {
+	p.ApplyTrades(ethBTC, schemas.ResultChannel{
+		DataType: schemas.DataTypeUpdate,
+		Data:     []schemas.Trade{{Symbol: ethBTC.Name, Price: price, Amount: amount}},
+	})
+}
+
+func TestTakerMatching(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  schemas.Order
+		trades [][2]float64 // price, amount
+		status string
+	}{
+		{
+			name:   "buy takes best asks first",
+			order:  schemas.Order{Symbol: "ETH-BTC", Type: schemas.TypeBuy, Price: 102, Amount: 2},
+			trades: [][2]float64{{101, 1}, {102, 1}},
+			status: schemas.StatusTrade,
+		},
+		{
+			name:   "sell takes best bids first, rest is open",
+			order:  schemas.Order{Symbol: "ETH-BTC", Type: schemas.TypeSell, Price: 99, Amount: 4},
+			trades: [][2]float64{{100, 2}, {99, 1}},
+			status: schemas.StatusTrade,
+		},
+		{
+			name:   "order behind book is not filled",
+			order:  schemas.Order{Symbol: "ETH-BTC", Type: schemas.TypeBuy, Price: 100.5, Amount: 1},
+			status: schemas.StatusNew,
+		},
+	}
+	for _, tt := range tests {
+		p := newTestProvider()
+		applyBook(p, schemas.DataTypeSnapshot, levels(100, 2, 99, 1), levels(101, 1, 102, 3))
+		result, err := p.Create(tt.order)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		trades, _, _ := p.Trades(schemas.FilterOptions{})
+		if len(trades) != len(tt.trades) {
+			t.Errorf("%s: trades = %+v, want %v", tt.name, trades, tt.trades)
+			continue
+		}
+		var filled float64
+		for i, trade := range trades {
+			if trade.Price != tt.trades[i][0] || trade.Amount != tt.trades[i][1] {
+				t.Errorf("%s: trade %d = %v @ %v, want %v @ %v", tt.name, i, trade.Amount, trade.Price, tt.trades[i][1], tt.trades[i][0])
+			}
+			if fee := trade.Price * trade.Amount * 0.002; math.Abs(trade.Fee-fee) > 1e-9 {
+				t.Errorf("%s: trade %d fee = %v, want taker fee %v", tt.name, i, trade.Fee, fee)
+			}
+			filled += trade.Amount
+		}
+		if result.Status != tt.status || result.AmountFilled != filled {
+			t.Errorf("%s: order = %s %v, want %s %v", tt.name, result.Status, result.AmountFilled, tt.status, filled)
+		}
+	}
+}
+
+func TestQueuePosition(t *testing.T) {
+	type step struct {
+		name   string
+		do     func(p *TradingProvider)
+		ahead  float64
+		filled float64
+	}
+	trade := func(price, amount float64) func(p *TradingProvider) {
+		return func(p *TradingProvider) { applyTrade(p, price, amount) }
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "trades at order price fill it after amount ahead",
+			steps: []step{
+				{name: "trade ahead", do: trade(100, 3), ahead: 2},
+				{name: "trade reaches order", do: trade(100, 2.5), ahead: 0, filled: 0.5},
+				{name: "next trade", do: trade(100, 0.2), ahead: 0, filled: 0.7},
+			},
+		},
+		{
+			name: "reduced level reduces amount ahead",
+			steps: []step{
+				{name: "level reduced", do: func(p *TradingProvider) {
+					applyBook(p, schemas.DataTypeUpdate, levels(100, 1), nil)
+				}, ahead: 1},
+				{name: "level increased", do: func(p *TradingProvider) {
+					applyBook(p, schemas.DataTypeUpdate, levels(100, 4), nil)
+				}, ahead: 1},
+				{name: "trade", do: trade(100, 1.5), ahead: 0, filled: 0.5},
+			},
+		},
+		{
+			name: "trade through order price fills it at once",
+			steps: []step{
+				{name: "trade below", do: trade(99.5, 0.6), ahead: 5, filled: 0.6},
+			},
+		},
+		{
+			name: "book reaching order price fills it",
+			steps: []step{
+				{name: "ask at order price", do: func(p *TradingProvider) {
+					applyBook(p, schemas.DataTypeUpdate, nil, levels(100, 0.3))
+				}, ahead: 5, filled: 0.3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		p := newTestProvider()
+		applyBook(p, schemas.DataTypeSnapshot, levels(100, 5), levels(101, 1))
+		result, err := p.Create(schemas.Order{Symbol: "ETH-BTC", Type: schemas.TypeBuy, Price: 100, Amount: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if o := p.orders[result.ID]; o.ahead != 5 {
+			t.Errorf("%s: ahead = %v, want 5", tt.name, o.ahead)
+		}
+		for _, s := range tt.steps {
+			s.do(p)
+			o := p.history[0]
+			if math.Abs(o.ahead-s.ahead) > 1e-9 || math.Abs(o.AmountFilled-s.filled) > 1e-9 {
+				t.Errorf("%s, %s: ahead %v, filled %v, want %v, %v", tt.name, s.name, o.ahead, o.AmountFilled, s.ahead, s.filled)
+			}
+		}
+	}
+}
+
+func TestUnknownSymbol(t *testing.T) {
+	for _, p := range []*TradingProvider{
+		newTestProvider(),
+		NewTradingProvider(schemas.PaperOptions{Balances: map[string]float64{"BTC": 1}}, nil),
+	} {
+		if _, err := p.Create(schemas.Order{Symbol: "LTC-BTC", Type: schemas.TypeBuy, Price: 0.01, Amount: 1}); err == nil {
+			t.Error("order of unknown symbol is created")
+		}
+	}
+}
+
+func TestSubscribeDoesNotBlock(t *testing.T) {
+	p := newTestProvider()
+	p.Subscribe(time.Second)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3*channelsBufferSize; i++ {
+			p.Create(schemas.Order{Symbol: "ETH-BTC", Type: schemas.TypeBuy, Price: 0.01, Amount: 1})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Create is blocked by subscription which is not read")
+	}
+	_, uoc, _ := p.Subscribe(time.Second)
+	if msg := <-uoc; msg.DataType != schemas.DataTypeSnapshot {
+		t.Errorf("first message = %s, want snapshot", msg.DataType)
+	}
+}
@@ -1,124 +1,136 @@
 package poloniex
 
-var currencPairs = map[int]string{
-	7:   "BTC_BCN",
-	12:  "BTC_BTCD",
-	13:  "BTC_BTM",
-	14:  "BTC_BTS",
-	15:  "BTC_BURST",
-	20:  "BTC_CLAM",
-	24:  "BTC_DASH",
-	25:  "BTC_DGB",
-	27:  "BTC_DOGE",
-	28:  "BTC_EMC2",
-	38:  "BTC_GAME",
-	40:  "BTC_GRC",
-	43:  "BTC_HUC",
-	50:  "BTC_LTC",
-	51:  "BTC_MAID",
-	58:  "BTC_OMNI",
-	61:  "BTC_NAV",
-	63:  "BTC_NEOS",
-	64:  "BTC_NMC",
-	69:  "BTC_NXT",
-	74:  "BTC_POT",
-	75:  "BTC_PPC",
-	89:  "BTC_STR",
-	92:  "BTC_SYS",
-	97:  "BTC_VIA",
-	99:  "BTC_VRC",
-	100: "BTC_VTC",
-	104: "BTC_XBC",
-	108: "BTC_XCP",
-	112: "BTC_XEM",
-	114: "BTC_XMR",
-	116: "BTC_XPM",
-	117: "BTC_XRP",
-	121: "USDT_BTC",
-	122: "USDT_DASH",
-	123: "USDT_LTC",
-	124: "USDT_NXT",
-	125: "USDT_STR",
-	126: "USDT_XMR",
-	127: "USDT_XRP",
-	129: "XMR_BCN",
-	131: "XMR_BTCD",
-	132: "XMR_DASH",
-	137: "XMR_LTC",
-	138: "XMR_MAID",
-	140: "XMR_NXT",
-	148: "BTC_ETH",
-	149: "USDT_ETH",
-	150: "BTC_SC",
-	153: "BTC_EXP",
-	155: "BTC_FCT",
-	160: "BTC_AMP",
-	162: "BTC_DCR",
-	163: "BTC_LSK",
-	166: "ETH_LSK",
-	167: "BTC_LBC",
-	168: "BTC_STEEM",
-	169: "ETH_STEEM",
-	170: "BTC_SBD",
-	171: "BTC_ETC",
-	172: "ETH_ETC",
-	173: "USDT_ETC",
-	174: "BTC_REP",
-	175: "USDT_REP",
-	176: "ETH_REP",
-	177: "BTC_ARDR",
-	178: "BTC_ZEC",
-	179: "ETH_ZEC",
-	180: "USDT_ZEC",
-	181: "XMR_ZEC",
-	182: "BTC_STRAT",
-	184: "BTC_PASC",
-	185: "BTC_GNT",
-	186: "ETH_GNT",
-	187: "BTC_GNO",
-	188: "ETH_GNO",
-	189: "BTC_BCH",
-	190: "ETH_BCH",
-	191: "USDT_BCH",
-	192: "BTC_ZRX",
-	193: "ETH_ZRX",
-	194: "BTC_CVC",
-	195: "ETH_CVC",
-	196: "BTC_OMG",
-	197: "ETH_OMG",
-	198: "BTC_GAS",
-	199: "ETH_GAS",
-	200: "BTC_STORJ",
-	201: "BTC_EOS",
-	202: "ETH_EOS",
-	203: "USDT_EOS",
-	204: "BTC_SNT",
-	205: "ETH_SNT",
-	206: "USDT_SNT",
-	207: "BTC_KNC",
-	208: "ETH_KNC",
-	209: "USDT_KNC",
-	210: "BTC_BAT",
-	211: "ETH_BAT",
-	212: "USDT_BAT",
-	213: "BTC_LOOM",
-	214: "ETH_LOOM",
-	215: "USDT_LOOM",
-	216: "USDT_DOGE",
-	217: "USDT_GNT",
-	218: "USDT_LSK",
-	219: "USDT_SC",
-	220: "USDT_ZRX",
-	221: "BTC_QTUM",
-	222: "ETH_QTUM",
-	223: "USDT_QTUM",
-	224: "USDC_BTC",
-	225: "USDC_ETH",
-	226: "USDC_USDT",
-	229: "BTC_MANA",
-	230: "ETH_MANA",
-	231: "USDT_MANA",
-	232: "BTC_BNT",
-	233: "ETH_BNT",
-	234: "USDT_BNT",
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goproxy/proxy"
+)
+
+// pairsRefreshInterval - min interval between reloads, unknown IDs of delisted pairs shouldn't flood API
+const pairsRefreshInterval = 30 * time.Second
+
+/*
+currencyPairs - currency pair ID to pair name table (i.e. 148 -> BTC_ETH).
+Poloniex websocket sends pair IDs only, table is loaded from ticker
+and reloaded when unknown ID comes, so new listings are decoded too.
+Every provider has own table with its proxy, groups of provider share it
+*/
+type currencyPairs struct {
+	httpClient *httpclient.Client
+	pairs      map[int]string
+	loadedAt   time.Time
+	// loading - closed when running reload is finished
+	loading chan struct{}
+
+	sync.RWMutex
+}
+
+// newCurrencyPairs - currencyPairs constructor, table is loaded on first lookup
+func newCurrencyPairs(httpProxy proxy.Provider) *currencyPairs {
+	return &currencyPairs{
+		httpClient: httpclient.New(httpProxy.NewClient(exchangeName)),
+		pairs:      make(map[int]string),
+	}
+}
+
+// Get - getting pair name by ID, reloading table if ID is unknown
+func (cp *currencyPairs) Get(id int) (string, error) {
+	if name, ok := cp.lookup(id); ok {
+		return name, nil
+	}
+	if err := cp.reload(); err != nil {
+		return "", err
+	}
+	if name, ok := cp.lookup(id); ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("[POLONIEX] Symbol %d not found", id)
+}
+
+// Set - storing pair ID from websocket snapshot
+func (cp *currencyPairs) Set(id int, name string) {
+	cp.Lock()
+	defer cp.Unlock()
+	cp.pairs[id] = name
+}
+
+// Len - count of known pairs, loading table if it's empty
+func (cp *currencyPairs) Len() int {
+	cp.RLock()
+	l := len(cp.pairs)
+	cp.RUnlock()
+	if l > 0 {
+		return l
+	}
+	if err := cp.reload(); err != nil {
+		log.Println("[POLONIEX] Error loading currency pairs: ", err)
+	}
+	cp.RLock()
+	defer cp.RUnlock()
+	return len(cp.pairs)
+}
+
+func (cp *currencyPairs) lookup(id int) (name string, ok bool) {
+	cp.RLock()
+	defer cp.RUnlock()
+	name, ok = cp.pairs[id]
+	return
+}
+
+/*
+reload - loading pairs IDs from ticker, not more often than pairsRefreshInterval.
+Ticker is requested without lock, so lookups aren't blocked, callers during request wait for it.
+Loaded table replaces current one, IDs missing in ticker (i.e. set from snapshots) are kept
+*/
+func (cp *currencyPairs) reload() (err error) {
+	var b []byte
+	var resp map[string]tickerSymbol
+
+	cp.Lock()
+	if loading := cp.loading; loading != nil {
+		cp.Unlock()
+		<-loading
+		return
+	}
+	if time.Since(cp.loadedAt) < pairsRefreshInterval {
+		cp.Unlock()
+		return
+	}
+	cp.loadedAt = time.Now()
+	cp.loading = make(chan struct{})
+	cp.Unlock()
+
+	pairs := make(map[int]string)
+	defer func() {
+		cp.Lock()
+		if err == nil {
+			for id, name := range cp.pairs {
+				if _, ok := pairs[id]; !ok {
+					pairs[id] = name
+				}
+			}
+			cp.pairs = pairs
+		}
+		close(cp.loading)
+		cp.loading = nil
+		cp.Unlock()
+	}()
+
+	query := httpclient.Params()
+	query.Set("command", commandTicker)
+	if b, err = cp.httpClient.Get(restURL, query, false); err != nil {
+		return
+	}
+	if err = json.Unmarshal(b, &resp); err != nil {
+		return
+	}
+	for name, t := range resp {
+		pairs[t.ID] = name
+	}
+	return
 }
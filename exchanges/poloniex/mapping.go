@@ -27,10 +27,13 @@ func (ub *UserBalance) Map(coin string) schemas.Balance {
 	}
 
 	return schemas.Balance{
-		Coin:      coin,
-		Available: available,
-		InOrders:  onOrders,
-		Total:     available + onOrders,
+		Coin:             coin,
+		Available:        available,
+		InOrders:         onOrders,
+		Total:            available + onOrders,
+		AvailableDecimal: schemas.Decimal(ub.Available),
+		InOrdersDecimal:  schemas.Decimal(ub.OnOrders),
+		TotalDecimal:     schemas.Decimal(ub.Available).Add(schemas.Decimal(ub.OnOrders)),
 	}
 }
 
@@ -73,6 +76,9 @@ func (uo *UserOrder) Map(symbol string) schemas.Order {
 		Amount:    amount,
 		CreatedAt: 1, // poloniex doesn't return open orders timestamp
 		Status:    schemas.StatusNew,
+
+		PriceDecimal:  schemas.Decimal(uo.Rate),
+		AmountDecimal: schemas.Decimal(uo.Amount),
 	}
 }
 
@@ -131,6 +137,10 @@ func (ut *UserTrade) Map(symbol string) schemas.Trade {
 		Amount:    amount,
 		Fee:       fee,
 		Timestamp: tms.Unix() * 1000,
+
+		PriceDecimal:  schemas.Decimal(ut.Rate),
+		AmountDecimal: schemas.Decimal(ut.Amount),
+		FeeDecimal:    schemas.Decimal(ut.Fee),
 	}
 }
 
@@ -3,9 +3,9 @@ package poloniex
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"log"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
@@ -29,7 +29,7 @@ type orderbook struct {
 // OrderBookGroup - order book group structure
 type OrderBookGroup struct {
 	symbols []schemas.Symbol
-	pairs   map[int]string
+	pairs   *currencyPairs
 
 	wsClient   *websocket.Client
 	httpClient *httpclient.Client
@@ -38,7 +38,9 @@ type OrderBookGroup struct {
 	outChannel chan schemas.ResultChannel
 	dch        chan []byte
 	ech        chan error
-	// bus        bus
+	stop       chan struct{}
+
+	sync.Mutex
 }
 
 type bus struct {
@@ -49,15 +51,21 @@ type bus struct {
 
 // NewOrderBookGroup - OrderBookGroup constructor
 func NewOrderBookGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *OrderBookGroup {
+	return newOrderBookGroup(symbols, newCurrencyPairs(httpProxy), httpProxy)
+}
+
+// newOrderBookGroup - OrderBookGroup constructor with pairs table of provider
+func newOrderBookGroup(symbols []schemas.Symbol, pairs *currencyPairs, httpProxy proxy.Provider) *OrderBookGroup {
 	proxyClient := httpProxy.NewClient(exchangeName)
 
 	return &OrderBookGroup{
 		symbols:    symbols,
 		httpProxy:  httpProxy,
 		httpClient: httpclient.New(proxyClient),
-		pairs:      currencPairs,
+		pairs:      pairs,
 		dch:        make(chan []byte, 2*len(symbols)),
 		ech:        make(chan error, 2*len(symbols)),
+		stop:       make(chan struct{}),
 	}
 }
 
@@ -102,14 +110,43 @@ func (ob *OrderBookGroup) Start(ch chan schemas.ResultChannel) {
 
 func (ob *OrderBookGroup) restart() {
 	time.Sleep(5 * time.Second)
+	if ob.stopped() {
+		return
+	}
 	if err := ob.wsClient.Exit(); err != nil {
 		log.Println("[POLONIEX] Error destroying connection: ", err)
 	}
 	ob.Start(ob.outChannel)
 }
 
+// Stop - closing connection, group isn't restarted
+func (ob *OrderBookGroup) Stop() {
+	close(ob.stop)
+	ob.Lock()
+	ws := ob.wsClient
+	ob.Unlock()
+	if ws == nil {
+		return
+	}
+	if err := ws.Exit(); err != nil {
+		log.Println("[POLONIEX] Error destroying connection: ", err)
+	}
+}
+
+// stopped - checking group has been stopped
+func (ob *OrderBookGroup) stopped() bool {
+	select {
+	case <-ob.stop:
+		return true
+	default:
+		return false
+	}
+}
+
 func (ob *OrderBookGroup) connect() {
+	ob.Lock()
 	ob.wsClient = websocket.NewClient(wsURL, ob.httpProxy)
+	ob.Unlock()
 	ob.wsClient.UsePingMessage(".")
 	if err := ob.wsClient.Connect(); err != nil {
 		log.Println("[POLONIEX] Error connecting to poloniex WS API: ", err)
@@ -138,7 +175,11 @@ func (ob *OrderBookGroup) subscribe() {
 func (ob *OrderBookGroup) collectSnapshots() {
 	go func() {
 		for {
-			time.Sleep(snapshotInterval)
+			select {
+			case <-ob.stop:
+				return
+			case <-time.After(snapshotInterval):
+			}
 
 			data, err := ob.Get()
 			if err != nil {
@@ -176,6 +217,7 @@ func (ob *OrderBookGroup) listen() {
 							if dataType == "i" {
 								// handling snapshot
 								snapshot := c[1].(map[string]interface{})
+								ob.pairs.Set(int(pairID), snapshot["currencyPair"].(string))
 								symbol, _, _ := parseSymbol(snapshot["currencyPair"].(string))
 								book := snapshot["orderBook"].([]interface{})
 
@@ -351,8 +393,5 @@ func (ob *OrderBookGroup) mapHTTPSnapshot(symbol string, data orderbook) schemas
 }
 
 func (ob *OrderBookGroup) getSymbolByID(pairID int64) (string, error) {
-	if symbol, ok := ob.pairs[int(pairID)]; ok {
-		return symbol, nil
-	}
-	return "", fmt.Errorf("[POLONIEX] Symbol %d not found", pairID)
+	return ob.pairs.Get(int(pairID))
 }
@@ -5,6 +5,7 @@ import (
 	"crypto/sha512"
 	"encoding/hex"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -28,6 +29,7 @@ const (
 	orderBookSymbolsLimit = 300
 	tradesSymbolsLimit    = 10
 	quotesSymbolsLimit    = 10
+	quotesBufferSize      = 200 // ticker channel sends all pairs
 	defaultPrecision      = 8
 
 	commandSubscribe        = "subscribe"
@@ -35,6 +37,7 @@ const (
 	commandOrderBook        = "returnOrderBook"
 	commandVolumes          = "return24hVolume"
 	commandTicker           = "returnTicker"
+	commandCurrencies       = "returnCurrencies"
 	commandOpenOrders       = "returnOpenOrders"
 	commandTrades           = "returnTradeHistory"
 
@@ -46,6 +49,12 @@ const (
 	commandCancel        = "cancelOrder"
 )
 
+// Poloniex base tier fees
+const (
+	defaultMakerFee = 0.001
+	defaultTakerFee = 0.002
+)
+
 const (
 	typeSell = "SELL"
 	typeBuy  = "BUY"
@@ -69,7 +78,7 @@ func New(opts schemas.Options) *Poloniex {
 	}
 
 	opts.Credentials.Sign = sign
-	return &Poloniex{
+	poloniex := &Poloniex{
 		Exchange: schemas.Exchange{
 			Credentials:   opts.Credentials,
 			ProxyProvider: proxyProvider,
@@ -78,9 +87,14 @@ func New(opts schemas.Options) *Poloniex {
 			Trades:        NewTradesProvider(proxyProvider),
 			Quotes:        NewQuotesProvider(proxyProvider),
 			Candles:       NewCandlesProvider(proxyProvider),
-			Trading:       NewTradingProvider(opts.Credentials, proxyProvider),
 		},
 	}
+	symbols, err := poloniex.SymbolProvider().Get()
+	if err != nil {
+		log.Println("Error getting symbols", err)
+	}
+	poloniex.Trading = NewTradingProvider(opts.Credentials, proxyProvider).SetSymbols(symbols)
+	return poloniex
 }
 
 func parseSymbol(s string) (name, basecoin, quoteCoin string) {
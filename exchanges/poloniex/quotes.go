@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/internal/websocket"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
@@ -34,28 +35,30 @@ type QuotesProvider struct {
 	httpClient *httpclient.Client
 	httpProxy  proxy.Provider
 	bus        bus
+	subscribed *listing.Set
 
-	pairs map[int]string
+	pairs *currencyPairs
 }
 
 // NewQuotesProvider - QuotesProvider constructor
 func NewQuotesProvider(httpProxy proxy.Provider) *QuotesProvider {
 	proxyClient := httpProxy.NewClient(exchangeName)
-	pairs := currencPairs
 
 	return &QuotesProvider{
+		subscribed: listing.NewSet(),
 		httpProxy:  httpProxy,
 		httpClient: httpclient.New(proxyClient),
-		pairs:      pairs,
+		pairs:      newCurrencyPairs(httpProxy),
 		bus: bus{
-			dch: make(chan []byte, 2*len(pairs)),
-			ech: make(chan error, 2*len(pairs)),
+			dch: make(chan []byte, 2*quotesBufferSize),
+			ech: make(chan error, 2*quotesBufferSize),
 		},
 	}
 }
 
-// SetSymbols - setting symbols and creating groups by symbols chunks
+// SetSymbols - replacing symbols, ticker channel sends all pairs and data of other symbols is skipped
 func (qp *QuotesProvider) SetSymbols(symbols []schemas.Symbol) schemas.QuotesProvider {
+	qp.subscribed.Reset(symbols)
 	return qp
 }
 
@@ -91,10 +94,23 @@ func (qp *QuotesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan
 
 // SubscribeAll - subscribing to all quotes with interval
 func (qp *QuotesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
-	bufLength := len(qp.pairs)
+	bufLength := qp.pairs.Len()
 	ch := make(chan schemas.ResultChannel, 2*bufLength)
+	out := make(chan schemas.ResultChannel, 2*bufLength)
+	go qp.subscribed.Forward(ch, out)
 	go qp.start(ch)
-	return ch
+	return out
+}
+
+// AddSymbols - adding symbols to running subscription, i.e. new listings.
+// Ticker channel sends all pairs, updates come for pairs known by ID
+func (qp *QuotesProvider) AddSymbols(symbols []schemas.Symbol) {
+	qp.subscribed.Add(symbols)
+}
+
+// RemoveSymbols - removing symbols from subscription, i.e. delistings
+func (qp *QuotesProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	qp.subscribed.Remove(symbols)
 }
 
 // start - starting quotes updates
@@ -258,12 +274,13 @@ func (qp *QuotesProvider) mapUpdate(d []interface{}) schemas.Quote {
 	}
 }
 
-// getSymbol - loading symbol from map to match it with currencyPair ID
+// getSymbol - loading symbol from pairs table to match it with currencyPair ID
 func (qp *QuotesProvider) getSymbol(id int) string {
-	if smb, ok := qp.pairs[id]; ok {
-		return smb
+	smb, err := qp.pairs.Get(id)
+	if err != nil {
+		log.Println("[POLONIEX] Error getting symbol: ", err)
 	}
-	return ""
+	return smb
 }
 
 func parseFloat(s string) (d float64) {
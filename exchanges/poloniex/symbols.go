@@ -2,13 +2,24 @@ package poloniex
 
 import (
 	"encoding/json"
+	"math"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
+// orderTypes - limit orders with poloniex flags mapped to common order types
+var orderTypes = []string{
+	schemas.OrderTypeLimit,
+	schemas.OrderTypeLimitMaker,
+	schemas.OrderTypeFillOrKill,
+	schemas.OrderTypeImmediateOrCancel,
+}
+
+// SymbolsProvider - symbols provider structure
 type SymbolsProvider struct {
 	httpClient *httpclient.Client
 }
@@ -21,13 +32,19 @@ func NewSymbolsProvider(httpProxy proxy.Provider) *SymbolsProvider {
 	}
 }
 
+// tickerSymbol - symbol fields of returnTicker response
+type tickerSymbol struct {
+	ID       int    `json:"id"`
+	IsFrozen string `json:"isFrozen"`
+}
+
 // Get - getting symbols data
 func (sp *SymbolsProvider) Get() (symbols []schemas.Symbol, err error) {
 	var b []byte
-	resp := make(map[string]interface{})
+	var resp map[string]tickerSymbol
 
 	query := httpclient.Params()
-	query.Set("command", commandVolumes)
+	query.Set("command", commandTicker)
 	if b, err = sp.httpClient.Get(restURL, query, false); err != nil {
 		return
 	}
@@ -35,28 +52,49 @@ func (sp *SymbolsProvider) Get() (symbols []schemas.Symbol, err error) {
 		return
 	}
 	for k, v := range resp {
-		if smb, ok := v.(map[string]interface{}); ok {
-			symbols = append(symbols, sp.mapSymbol(k, smb))
-		}
+		symbols = append(symbols, sp.mapSymbol(k, v))
 	}
 
 	return
 }
 
 // mapSymbol - mapping incoming symbol data into common Symbol model
-func (sp *SymbolsProvider) mapSymbol(symbol string, data map[string]interface{}) schemas.Symbol {
+func (sp *SymbolsProvider) mapSymbol(symbol string, data tickerSymbol) schemas.Symbol {
 	name, baseCoin, quoteCoin := parseSymbol(symbol)
+	status := schemas.SymbolStatusTrading
+	if data.IsFrozen == "1" {
+		status = schemas.SymbolStatusHalted
+	}
 	smb := schemas.Symbol{
-		Name:           name,
-		OriginalName:   symbol,
-		BaseCoin:       baseCoin,
-		Coin:           quoteCoin,
-		PricePrecision: defaultPrecision,
+		Name:            name,
+		OriginalName:    symbol,
+		BaseCoin:        baseCoin,
+		Coin:            quoteCoin,
+		Status:          status,
+		OrderTypes:      orderTypes,
+		Fee:             defaultTakerFee,
+		MakerFee:        defaultMakerFee,
+		TakerFee:        defaultTakerFee,
+		DefaultFees:     true,
+		DefaultFilters:  true, // public API doesn't return trading rules, these are documented ones
+		TickSize:        math.Pow10(-defaultPrecision),
+		LotStep:         math.Pow10(-defaultPrecision),
+		MinNotional:     minTotal(quoteCoin),
+		PricePrecision:  defaultPrecision,
+		AmountPrecision: defaultPrecision,
 	}
 
 	return smb
 }
 
+// minTotal - poloniex min order total depends on market quote coin
+func minTotal(quoteCoin string) float64 {
+	if quoteCoin == "USDT" || quoteCoin == "USDC" {
+		return 1
+	}
+	return 0.0001
+}
+
 // Subscribe - getting all symbols from exchange with interval d
 func (sp *SymbolsProvider) Subscribe(d time.Duration) chan schemas.ResultChannel {
 	ch := make(chan schemas.ResultChannel, 300)
@@ -74,3 +112,8 @@ func (sp *SymbolsProvider) Subscribe(d time.Duration) chan schemas.ResultChannel
 	}()
 	return ch
 }
+
+// SubscribeChanges - subscribing to symbols changes: listings, delistings, status and filters changes
+func (sp *SymbolsProvider) SubscribeChanges(d time.Duration) chan schemas.SymbolEventsChannel {
+	return listing.Watch(sp.Get, d)
+}
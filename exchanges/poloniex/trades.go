@@ -1,56 +1,63 @@
 package poloniex
 
 import (
-	"sync"
 	"time"
 
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
 // TradesProvider - trades provider structure
 type TradesProvider struct {
-	symbols   []schemas.Symbol
-	groups    []*TradesGroup
 	httpProxy proxy.Provider
-
-	sync.Mutex
+	pairs     *currencyPairs
+	groups    *listing.Groups
 }
 
 // NewTradesProvider - TradesProvider constructor
 func NewTradesProvider(httpProxy proxy.Provider) *TradesProvider {
-	return &TradesProvider{
+	tp := &TradesProvider{
 		httpProxy: httpProxy,
+		pairs:     newCurrencyPairs(httpProxy),
 	}
+	tp.groups = listing.NewGroups(orderBookSymbolsLimit, tp.startGroup)
+	return tp
 }
 
-// SetSymbols - setting symbols to TradesProvider
+// SetSymbols - replacing symbols, they are split into groups by symbols chunks on subscription.
+// Symbols of running subscription are changed by AddSymbols and RemoveSymbols
 func (tp *TradesProvider) SetSymbols(symbols []schemas.Symbol) schemas.TradesProvider {
-	tp.symbols = symbols
-	slice := make([]schemas.Symbol, len(symbols))
-	copy(slice, symbols)
-	capacity := orderBookSymbolsLimit
-	for {
-		if len(slice) <= capacity {
-			tp.groups = append(
-				tp.groups,
-				NewTradesGroup(slice, tp.httpProxy),
-			)
-			break
-		}
-		tp.groups = append(
-			tp.groups,
-			NewTradesGroup(slice[0:capacity], tp.httpProxy),
-		)
-
-		slice = slice[capacity:]
-	}
+	tp.groups.Reset(symbols)
 	return tp
 }
 
+// SubscribeAll - subscribing all groups
+func (tp *TradesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return tp.groups.Subscribe(d)
+}
+
+// AddSymbols - adding symbols to running subscription, i.e. new listings
+func (tp *TradesProvider) AddSymbols(symbols []schemas.Symbol) {
+	tp.groups.Add(symbols)
+}
+
+// RemoveSymbols - removing symbols from subscription, i.e. delistings.
+// Groups subscribed to them are reconnected without them
+func (tp *TradesProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	tp.groups.Remove(symbols)
+}
+
+// startGroup - creating and starting group of symbols
+func (tp *TradesProvider) startGroup(symbols []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) listing.Group {
+	group := newTradesGroup(symbols, tp.pairs, tp.httpProxy)
+	go group.Start(ch)
+	return group
+}
+
 // Get - getting trades snapshot by symbol
 func (tp *TradesProvider) Get(symbol schemas.Symbol) (q []schemas.Trade, err error) {
-	group := NewTradesGroup([]schemas.Symbol{symbol}, tp.httpProxy)
+	group := newTradesGroup([]schemas.Symbol{symbol}, tp.pairs, tp.httpProxy)
 	d, err := group.Get()
 	if err != nil {
 		return
@@ -65,19 +72,7 @@ func (tp *TradesProvider) Get(symbol schemas.Symbol) (q []schemas.Trade, err err
 // Subscribe - subscribing to trades by one symbol
 func (tp *TradesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan schemas.ResultChannel {
 	ch := make(chan schemas.ResultChannel)
-	group := NewTradesGroup([]schemas.Symbol{symbol}, tp.httpProxy)
+	group := newTradesGroup([]schemas.Symbol{symbol}, tp.pairs, tp.httpProxy)
 	go group.Start(ch)
 	return ch
 }
-
-// SubscribeAll - subscribing all groups
-func (tp *TradesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
-	bufLength := len(tp.symbols)
-	ch := make(chan schemas.ResultChannel, 2*bufLength)
-
-	for _, group := range tp.groups {
-		go group.Start(ch)
-		time.Sleep(100 * time.Millisecond)
-	}
-	return ch
-}
@@ -3,9 +3,9 @@ package poloniex
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"log"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
@@ -27,7 +27,7 @@ type trade struct {
 // TradesGroup - trade group structure
 type TradesGroup struct {
 	symbols []schemas.Symbol
-	pairs   map[int]string
+	pairs   *currencyPairs
 
 	wsClient   *websocket.Client
 	httpClient *httpclient.Client
@@ -36,13 +36,19 @@ type TradesGroup struct {
 	outChannel chan schemas.ResultChannel
 	dch        chan []byte
 	ech        chan error
-	// bus        bus
+	stop       chan struct{}
+
+	sync.Mutex
 }
 
 // NewTradesGroup - TradesGroup constructor
 func NewTradesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *TradesGroup {
+	return newTradesGroup(symbols, newCurrencyPairs(httpProxy), httpProxy)
+}
+
+// newTradesGroup - TradesGroup constructor with pairs table of provider
+func newTradesGroup(symbols []schemas.Symbol, pairs *currencyPairs, httpProxy proxy.Provider) *TradesGroup {
 	proxyClient := httpProxy.NewClient(exchangeName)
-	pairs := currencPairs
 
 	return &TradesGroup{
 		symbols:    symbols,
@@ -51,6 +57,7 @@ func NewTradesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *TradesG
 		pairs:      pairs,
 		dch:        make(chan []byte, 2*len(symbols)),
 		ech:        make(chan error, 2*len(symbols)),
+		stop:       make(chan struct{}),
 	}
 }
 
@@ -99,14 +106,43 @@ func (tg *TradesGroup) Start(ch chan schemas.ResultChannel) {
 
 func (tg *TradesGroup) restart() {
 	time.Sleep(5 * time.Second)
+	if tg.stopped() {
+		return
+	}
 	if err := tg.wsClient.Exit(); err != nil {
 		log.Println("[POLONIEX] Error destroying connection: ", err)
 	}
 	tg.Start(tg.outChannel)
 }
 
+// Stop - closing connection, group isn't restarted
+func (tg *TradesGroup) Stop() {
+	close(tg.stop)
+	tg.Lock()
+	ws := tg.wsClient
+	tg.Unlock()
+	if ws == nil {
+		return
+	}
+	if err := ws.Exit(); err != nil {
+		log.Println("[POLONIEX] Error destroying connection: ", err)
+	}
+}
+
+// stopped - checking group has been stopped
+func (tg *TradesGroup) stopped() bool {
+	select {
+	case <-tg.stop:
+		return true
+	default:
+		return false
+	}
+}
+
 func (tg *TradesGroup) connect() {
+	tg.Lock()
 	tg.wsClient = websocket.NewClient(wsURL, tg.httpProxy)
+	tg.Unlock()
 	tg.wsClient.UsePingMessage(".")
 	if err := tg.wsClient.Connect(); err != nil {
 		log.Println("[POLONIEX] Error connecting to poloniex WS API: ", err)
@@ -134,7 +170,11 @@ func (tg *TradesGroup) subscribe() {
 func (tg *TradesGroup) collectSnapshots() {
 	go func() {
 		for {
-			time.Sleep(snapshotInterval)
+			select {
+			case <-tg.stop:
+				return
+			case <-time.After(snapshotInterval):
+			}
 
 			data, err := tg.Get()
 			if err != nil {
@@ -282,8 +322,5 @@ func (tg *TradesGroup) mapUpdate(pairID int64, data []interface{}) schemas.Trade
 
 // getSymbolByID - getting symbol name by it's id
 func (tg *TradesGroup) getSymbolByID(pairID int) (string, error) {
-	if symbol, ok := tg.pairs[pairID]; ok {
-		return symbol, nil
-	}
-	return "", fmt.Errorf("[POLONIEX] Symbol %d not found", pairID)
+	return tg.pairs.Get(pairID)
 }
@@ -4,21 +4,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/importer"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
+const (
+	importLimit  = 10000               // max trades in response
+	importWindow = 30 * 24 * time.Hour // first time window of trades request
+	importStart  = 1388534400          // 2014-01-01, Since of import when it's not set
+)
+
 // TradingProvider represents poloniex trading provider structure
 type TradingProvider struct {
 	credentials schemas.Credentials
 	httpProxy   proxy.Provider
 	httpClient  *httpclient.Client
 	symbols     []schemas.Symbol
+	pairs       *currencyPairs
 }
 
 // NewTradingProvider - TradingProvider constructor
@@ -28,6 +37,7 @@ func NewTradingProvider(credentials schemas.Credentials, httpProxy proxy.Provide
 		credentials: credentials,
 		httpProxy:   httpProxy,
 		httpClient:  httpclient.NewSigned(credentials, proxyClient),
+		pairs:       newCurrencyPairs(httpProxy),
 	}
 }
 
@@ -38,44 +48,16 @@ func (trading *TradingProvider) SetSymbols(symbols []schemas.Symbol) schemas.Tra
 	return trading
 }
 
-// Subscribe subscribing to user trade data updates: balance, orders, trades
+/*
+Subscribe subscribing to user trade data updates: balance, orders, trades.
+Snapshots are loaded by REST after every connection,
+updates come from account notifications websocket channel
+*/
 func (trading *TradingProvider) Subscribe(interval time.Duration) (chan schemas.UserInfoChannel, chan schemas.UserOrdersChannel, chan schemas.UserTradesChannel) {
-	uic := make(chan schemas.UserInfoChannel)
-	uoc := make(chan schemas.UserOrdersChannel)
-	utc := make(chan schemas.UserTradesChannel)
-
-	if interval < 5*time.Second {
-		interval = 5 * time.Second
-	}
-
-	go func() {
-		for {
-			ui, err := trading.Info()
-			uic <- schemas.UserInfoChannel{
-				DataType: dataTypeSnapshot,
-				Data:     ui,
-				Error:    err,
-			}
-
-			uo, err := trading.Orders([]schemas.Symbol{})
-			uoc <- schemas.UserOrdersChannel{
-				DataType: dataTypeSnapshot,
-				Data:     uo,
-				Error:    err,
-			}
-
-			ut, _, err := trading.Trades(schemas.FilterOptions{})
-			utc <- schemas.UserTradesChannel{
-				DataType: dataTypeSnapshot,
-				Data:     ut,
-				Error:    err,
-			}
-
-			time.Sleep(interval)
-		}
-	}()
+	stream := newTradingStream(trading)
+	go stream.Start()
 
-	return uic, uoc, utc
+	return stream.uic, stream.uoc, stream.utc
 }
 
 // Info provides user balance data
@@ -174,10 +156,102 @@ func (trading *TradingProvider) Trades(opts schemas.FilterOptions) (trades []sch
 	return trading.allTrades(opts)
 }
 
-// ImportTrades importing trades by params
+/*
+ImportTrades - importing trades of symbols, of all symbols if empty.
+Trades are paged by time windows from Since or checkpoint till Before or import start.
+Last message has DataTypeDone
+*/
 func (trading *TradingProvider) ImportTrades(opts schemas.FilterOptions) chan schemas.UserTradesChannel {
-	ch := make(chan schemas.UserTradesChannel)
-	return ch
+	// Poloniex time filter is in seconds
+	if len(fmt.Sprintf("%d", opts.Before)) >= 12 {
+		opts.Before = opts.Before / 1000
+	}
+	if len(fmt.Sprintf("%d", opts.Since)) >= 12 {
+		opts.Since = opts.Since / 1000
+	}
+	now := time.Now().Unix()
+	if opts.Since == 0 {
+		opts.Since = importStart
+	}
+	if opts.Before == 0 || opts.Before > now {
+		opts.Before = now
+	}
+	start := schemas.Checkpoint{Cursor: fmt.Sprintf("%d", opts.Since)}
+
+	tasks := []importer.Task{{
+		Symbol: "all",
+		Start:  start,
+		Page:   trading.tradesPage(nil, opts.Before),
+	}}
+	if len(opts.Symbols) > 0 {
+		tasks = nil
+		for _, s := range opts.Symbols {
+			tasks = append(tasks, importer.Task{
+				Symbol: s.Name,
+				Start:  start,
+				Page:   trading.tradesPage([]schemas.Symbol{s}, opts.Before),
+			})
+		}
+	}
+	return importer.Importer{
+		Exchange:    exchangeName,
+		APIKey:      trading.credentials.APIKey,
+		Checkpoints: opts.Checkpoints,
+		Acknowledge: opts.Acknowledge,
+		Delay:       time.Second,
+	}.Run(tasks)
+}
+
+/*
+tradesPage - loading trades of time window, cursor is start of next window in seconds.
+Response is limited and has newest trades, so window is halved while response is full.
+Trades are sent sorted from oldest
+*/
+func (trading *TradingProvider) tradesPage(symbols []schemas.Symbol, end int64) importer.Page {
+	return func(cursor schemas.Checkpoint) (trades []schemas.Trade, next schemas.Checkpoint, done bool, err error) {
+		var from int64
+		if from, err = strconv.ParseInt(cursor.Cursor, 10, 64); err != nil {
+			return
+		}
+		window := int64(importWindow / time.Second)
+		var to int64
+		for {
+			to = from + window - 1
+			done = to >= end
+			if done {
+				to = end
+			}
+			opts := schemas.FilterOptions{
+				Symbols: symbols,
+				Since:   from,
+				Before:  to,
+				Limit:   importLimit,
+			}
+			if trades, _, err = trading.Trades(opts); err != nil {
+				return
+			}
+			if len(trades) < importLimit {
+				break
+			}
+			if window == 1 {
+				log.Printf("[POLONIEX] More than %d trades in second %d, rest of them is not imported\n", importLimit, from)
+				break
+			}
+			window /= 2
+			time.Sleep(time.Second)
+		}
+		sort.SliceStable(trades, func(i, j int) bool {
+			return trades[i].Timestamp < trades[j].Timestamp
+		})
+		next = schemas.Checkpoint{
+			Cursor:    fmt.Sprintf("%d", to+1),
+			Timestamp: cursor.Timestamp,
+		}
+		if len(trades) > 0 {
+			next.Timestamp = trades[len(trades)-1].Timestamp
+		}
+		return
+	}
 }
 
 // Create creating new limit order
@@ -186,6 +260,10 @@ func (trading *TradingProvider) Create(order schemas.Order) (result schemas.Orde
 	var command string
 	var resp OrderCreate
 
+	if order, err = schemas.PrepareOrder(trading.symbols, order); err != nil {
+		return
+	}
+
 	if strings.ToUpper(order.Type) == typeBuy {
 		command = commandBuy
 	}
@@ -200,8 +278,8 @@ func (trading *TradingProvider) Create(order schemas.Order) (result schemas.Orde
 	payload.Set("command", command)
 	payload.Set("nonce", strconv.FormatInt(nonce, 10))
 	payload.Set("currencyPair", symbol)
-	payload.Set("rate", strconv.FormatFloat(order.Price, 'f', -1, 64))
-	payload.Set("amount", strconv.FormatFloat(order.Amount, 'f', -1, 64))
+	payload.Set("rate", order.PriceValue())
+	payload.Set("amount", order.AmountValue())
 
 	b, err = trading.httpClient.Post(tradingAPI, httpclient.Params(), payload, true)
 	if err != nil {
@@ -264,6 +342,31 @@ func (trading *TradingProvider) CancelAll() (err error) {
 	return
 }
 
+// CancelAllBy cancelling open orders by symbols and side
+func (trading *TradingProvider) CancelAllBy(symbols []schemas.Symbol, side string) (cancelled []schemas.Order, err error) {
+	if len(symbols) == 0 {
+		return nil, schemas.ErrEmptySymbols
+	}
+	for _, symb := range symbols {
+		var orders []schemas.Order
+		if orders, err = trading.ordersBySymbol(symb.OriginalName); err != nil {
+			return
+		}
+		for _, ord := range orders {
+			if !ord.IsSide(side) {
+				continue
+			}
+			if err = trading.Cancel(ord); err != nil {
+				return
+			}
+			ord.Status = schemas.StatusCancelled
+			cancelled = append(cancelled, ord)
+		}
+	}
+
+	return
+}
+
 func (trading *TradingProvider) allOrders() (orders []schemas.Order, err error) {
 	var resp map[string][]UserOrder
 	var b []byte
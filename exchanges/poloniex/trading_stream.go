@@ -0,0 +1,563 @@
+package poloniex
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/websocket"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+const (
+	channelAccount  = 1000
+	walletExchange  = "e"
+	orderCancelFlag = "c"
+	dateLayout      = "2006-01-02 15:04:05"
+)
+
+// account notifications handling order: placed orders and trades need order before it's closed
+var eventsPriority = map[string]int{
+	"n": 0,
+	"t": 1,
+	"o": 2,
+	"b": 3,
+}
+
+const (
+	// delay before reconnect, it's doubled after every failure
+	streamRetry    = 5 * time.Second
+	maxStreamRetry = 2 * time.Minute
+	// balances are read again while deltas come during reading, but not more times than this
+	maxBalanceReads = 3
+)
+
+type accountSubscribeMsg struct {
+	Command string `json:"command"`
+	Channel int    `json:"channel"`
+	Key     string `json:"key"`
+	Payload string `json:"payload"`
+	Sign    string `json:"sign"`
+}
+
+type currency struct {
+	ID int `json:"id"`
+}
+
+/*
+tradingStream - account notifications channel (1000).
+Poloniex sends balance deltas and order changes without symbols,
+so balances, open orders and currencies IDs of snapshot are kept to build full models.
+Events are buffered while snapshot is loading. Orders and trades already in snapshot
+are skipped by IDs, balances are read again when deltas came during loading,
+because it's unknown whether snapshot contains them
+*/
+type tradingStream struct {
+	trading  *TradingProvider
+	wsClient *websocket.Client
+
+	currencies map[int]string
+	balances   map[string]schemas.Balance
+	orders     map[string]schemas.Order
+	trades     map[string]bool
+
+	loading  bool
+	buffered []interface{}
+
+	uic chan schemas.UserInfoChannel
+	uoc chan schemas.UserOrdersChannel
+	utc chan schemas.UserTradesChannel
+	dch chan []byte
+	// messages waiting to be sent to subscription channels, in order of changes
+	outbox []interface{}
+	notify chan struct{}
+
+	sync.Mutex
+}
+
+func newTradingStream(trading *TradingProvider) *tradingStream {
+	return &tradingStream{
+		trading: trading,
+		uic:     make(chan schemas.UserInfoChannel),
+		uoc:     make(chan schemas.UserOrdersChannel),
+		utc:     make(chan schemas.UserTradesChannel),
+		dch:     make(chan []byte, 100),
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+// Start - listening account notifications, snapshots are sent after every connection
+func (ts *tradingStream) Start() {
+	go ts.send()
+	ts.listen()
+	ts.run()
+}
+
+/*
+run - reconnect loop: connecting and waiting for connection error.
+Delay before reconnect is doubled after every failure up to maxStreamRetry
+and reset when connection has been working longer than maxStreamRetry
+*/
+func (ts *tradingStream) run() {
+	wait := streamRetry
+	for {
+		started := time.Now()
+		ech, err := ts.connect()
+		if err == nil {
+			err = <-ech
+		}
+		log.Printf("[POLONIEX] Account stream error, reconnecting in %v: %v\n", wait, err)
+		if ts.wsClient != nil {
+			if err := ts.wsClient.Exit(); err != nil {
+				log.Println("[POLONIEX] Error destroying connection: ", err)
+			}
+		}
+		if time.Since(started) > maxStreamRetry {
+			wait = streamRetry
+		}
+		time.Sleep(wait)
+		if wait *= 2; wait > maxStreamRetry {
+			wait = maxStreamRetry
+		}
+	}
+}
+
+/*
+connect - connecting, subscribing to account notifications and loading snapshot.
+Returns errors channel of connection, first error means connection is broken
+*/
+func (ts *tradingStream) connect() (ech chan error, err error) {
+	ts.wsClient = websocket.NewClient(wsURL, ts.trading.httpProxy)
+	ts.wsClient.UsePingMessage(".")
+	if err = ts.wsClient.Connect(); err != nil {
+		return nil, fmt.Errorf("connecting to poloniex WS API: %v", err)
+	}
+	// errors channel by connection: closed connection errors don't restart new one
+	ech = make(chan error, 2)
+	ts.wsClient.Listen(ts.dch, ech)
+
+	if err = ts.subscribe(); err != nil {
+		return nil, fmt.Errorf("subscribing to account notifications: %v", err)
+	}
+	ts.snapshot()
+	return ech, nil
+}
+
+func (ts *tradingStream) subscribe() error {
+	payload := "nonce=" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	return ts.wsClient.Write(accountSubscribeMsg{
+		Command: commandSubscribe,
+		Channel: channelAccount,
+		Key:     ts.trading.credentials.APIKey,
+		Payload: payload,
+		Sign:    signRequest(payload, ts.trading.credentials.APISecret),
+	})
+}
+
+/*
+snapshot - loading balances, orders and trades by REST.
+Events received while it's loading are buffered and applied after snapshot is sent:
+placed orders and trades of snapshot are skipped by IDs, order updates not decreasing amount are stale.
+Balance deltas can't be matched, so balances are read again if deltas were buffered
+*/
+func (ts *tradingStream) snapshot() {
+	ts.Lock()
+	ts.loading = true
+	ts.buffered = nil
+	ts.Unlock()
+
+	currencies, err := ts.trading.currencies()
+	if err != nil {
+		log.Println("[POLONIEX] Error loading currencies: ", err)
+	}
+	ui, infoErr := ts.trading.Info()
+	orders, ordersErr := ts.trading.Orders([]schemas.Symbol{})
+	trades, _, tradesErr := ts.trading.Trades(schemas.FilterOptions{})
+
+	ts.Lock()
+	ts.currencies = currencies
+	ts.setBalances(ui, infoErr)
+
+	ts.orders = make(map[string]schemas.Order)
+	for _, o := range orders {
+		ts.orders[o.ID] = o
+	}
+	ts.push(schemas.UserOrdersChannel{
+		DataType: dataTypeSnapshot,
+		Data:     orders,
+		Error:    ordersErr,
+	})
+
+	ts.trades = make(map[string]bool)
+	for _, t := range trades {
+		ts.trades[t.ID] = true
+	}
+	ts.push(schemas.UserTradesChannel{
+		DataType: dataTypeSnapshot,
+		Data:     trades,
+		Error:    tradesErr,
+	})
+
+	for reads := 1; ; reads++ {
+		deltas := ts.handleBuffered()
+		if !deltas || reads > maxBalanceReads {
+			if deltas {
+				log.Println("[POLONIEX] Balances are changing while reading, last read is kept")
+			}
+			ts.loading = false
+			ts.buffered = nil
+			ts.Unlock()
+			return
+		}
+		ts.Unlock()
+		ui, infoErr := ts.trading.Info()
+		ts.Lock()
+		ts.setBalances(ui, infoErr)
+	}
+}
+
+// handleBuffered - handling buffered events except balance deltas, returns whether deltas were buffered.
+// State has to be locked
+func (ts *tradingStream) handleBuffered() (deltas bool) {
+	var events []interface{}
+	for _, e := range ts.buffered {
+		if kind, ok := eventKind(e); ok && kind == "b" {
+			deltas = true
+			continue
+		}
+		events = append(events, e)
+	}
+	ts.buffered = nil
+	ts.handle(events)
+	return
+}
+
+// setBalances - replacing balances by loaded ones and queueing snapshot, state has to be locked
+func (ts *tradingStream) setBalances(ui schemas.UserInfo, err error) {
+	if err == nil || ts.balances == nil {
+		ts.balances = ui.Balances
+	}
+	if ts.balances == nil {
+		ts.balances = make(map[string]schemas.Balance)
+	}
+	ts.push(schemas.UserInfoChannel{
+		DataType: dataTypeSnapshot,
+		Data:     ui,
+		Error:    err,
+	})
+}
+
+// receive - handling events, they are buffered while snapshot is loading
+func (ts *tradingStream) receive(events []interface{}) {
+	ts.Lock()
+	defer ts.Unlock()
+	if ts.loading {
+		ts.buffered = append(ts.buffered, events...)
+		return
+	}
+	ts.handle(events)
+}
+
+// push - queueing messages to subscription channels, has to be called under lock
+func (ts *tradingStream) push(messages ...interface{}) {
+	ts.outbox = append(ts.outbox, messages...)
+	select {
+	case ts.notify <- struct{}{}:
+	default:
+	}
+}
+
+// send - sending queued messages to subscription channels without lock, in order they were queued
+func (ts *tradingStream) send() {
+	for range ts.notify {
+		ts.Lock()
+		messages := ts.outbox
+		ts.outbox = nil
+		ts.Unlock()
+		for _, msg := range messages {
+			switch m := msg.(type) {
+			case schemas.UserInfoChannel:
+				ts.uic <- m
+			case schemas.UserOrdersChannel:
+				ts.uoc <- m
+			case schemas.UserTradesChannel:
+				ts.utc <- m
+			}
+		}
+	}
+}
+
+func (ts *tradingStream) listen() {
+	go func() {
+		for msg := range ts.dch {
+			var data []interface{}
+			if err := json.Unmarshal(msg, &data); err != nil {
+				log.Println("[POLONIEX] Error parsing message: ", err)
+				continue
+			}
+			// [1000, "", [events]], subscription ack is [1000, 1]
+			if len(data) < 3 {
+				continue
+			}
+			if ch, ok := data[0].(float64); !ok || int(ch) != channelAccount {
+				continue
+			}
+			if events, ok := data[2].([]interface{}); ok {
+				ts.receive(events)
+			}
+		}
+	}()
+}
+
+// eventKind - event type: "n", "t", "o" or "b"
+func eventKind(d interface{}) (kind string, ok bool) {
+	e, ok := d.([]interface{})
+	if !ok || len(e) < 2 {
+		return "", false
+	}
+	kind, ok = e[0].(string)
+	return
+}
+
+// handle - applying events by priority, state has to be locked
+func (ts *tradingStream) handle(data []interface{}) {
+	var events [][]interface{}
+	for _, d := range data {
+		if _, ok := eventKind(d); ok {
+			events = append(events, d.([]interface{}))
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventsPriority[events[i][0].(string)] < eventsPriority[events[j][0].(string)]
+	})
+
+	for _, e := range events {
+		switch e[0].(string) {
+		case "n":
+			ts.orderPlaced(e)
+		case "t":
+			ts.orderTrade(e)
+		case "o":
+			ts.orderUpdate(e)
+		case "b":
+			ts.balanceUpdate(e)
+		}
+	}
+}
+
+// orderPlaced - ["n", <pair id>, <order number>, <type 0 sell/1 buy>, "<rate>", "<amount>", "<date>", ...]
+func (ts *tradingStream) orderPlaced(e []interface{}) {
+	if len(e) < 7 {
+		return
+	}
+	if _, ok := ts.orders[toString(e[2])]; ok {
+		// order of snapshot
+		return
+	}
+	pair, err := ts.trading.pairs.Get(toInt(e[1]))
+	if err != nil {
+		log.Println("[POLONIEX] Error getting symbol: ", err)
+		return
+	}
+	symbol, _, _ := parseSymbol(pair)
+	rate, amount := toString(e[4]), toString(e[5])
+	orderType := typeSell
+	if toInt(e[3]) == 1 {
+		orderType = typeBuy
+	}
+	order := schemas.Order{
+		ID:        toString(e[2]),
+		Symbol:    symbol,
+		Type:      orderType,
+		Price:     parseFloat(rate),
+		Amount:    parseFloat(amount),
+		CreatedAt: parseDate(toString(e[6])),
+		Status:    schemas.StatusNew,
+
+		PriceDecimal:  schemas.Decimal(rate),
+		AmountDecimal: schemas.Decimal(amount),
+	}
+	ts.orders[order.ID] = order
+	ts.push(schemas.UserOrdersChannel{
+		DataType: dataTypeUpdate,
+		Data:     []schemas.Order{order},
+	})
+}
+
+/*
+orderUpdate - ["o", <order number>, "<new amount>", "<update type>"].
+Zero amount closes order: cancelled by "c" type, filled otherwise.
+Amount of order only decreases, so update not decreasing it is already applied
+*/
+func (ts *tradingStream) orderUpdate(e []interface{}) {
+	if len(e) < 3 {
+		return
+	}
+	id := toString(e[1])
+	order, ok := ts.orders[id]
+	if !ok {
+		log.Println("[POLONIEX] Update of unknown order: ", id)
+		return
+	}
+	amount := toString(e[2])
+	if parseFloat(amount) >= order.Amount {
+		return
+	}
+	order.Amount = parseFloat(amount)
+	order.AmountDecimal = schemas.Decimal(amount)
+	if order.Amount == 0 {
+		order.Status = schemas.StatusTrade
+		if len(e) > 3 && toString(e[3]) == orderCancelFlag {
+			order.Status = schemas.StatusCancelled
+		}
+		delete(ts.orders, id)
+	} else {
+		ts.orders[id] = order
+	}
+	ts.push(schemas.UserOrdersChannel{
+		DataType: dataTypeUpdate,
+		Data:     []schemas.Order{order},
+	})
+}
+
+// orderTrade - ["t", <trade id>, "<rate>", "<amount>", "<fee multiplier>", <funding type>, <order number>, "<total fee>", "<date>", ...]
+func (ts *tradingStream) orderTrade(e []interface{}) {
+	if len(e) < 9 {
+		return
+	}
+	id := toString(e[1])
+	if ts.trades[id] {
+		return
+	}
+	ts.trades[id] = true
+	orderID := toString(e[6])
+	order, ok := ts.orders[orderID]
+	if !ok {
+		log.Println("[POLONIEX] Trade of unknown order: ", orderID)
+	}
+	rate, amount, fee := toString(e[2]), toString(e[3]), toString(e[7])
+	trade := schemas.Trade{
+		ID:        id,
+		OrderID:   orderID,
+		Symbol:    order.Symbol,
+		Type:      order.Type,
+		Price:     parseFloat(rate),
+		Amount:    parseFloat(amount),
+		Fee:       parseFloat(fee),
+		Timestamp: parseDate(toString(e[8])),
+
+		PriceDecimal:  schemas.Decimal(rate),
+		AmountDecimal: schemas.Decimal(amount),
+		FeeDecimal:    schemas.Decimal(fee),
+	}
+	ts.push(schemas.UserTradesChannel{
+		DataType: dataTypeUpdate,
+		Data:     []schemas.Trade{trade},
+	})
+}
+
+/*
+balanceUpdate - ["b", <currency id>, "<wallet>", "<amount delta>"].
+Delta is applied to available amount of exchange wallet, in orders amount is taken from open orders
+*/
+func (ts *tradingStream) balanceUpdate(e []interface{}) {
+	if len(e) < 4 || toString(e[2]) != walletExchange {
+		return
+	}
+	coin, ok := ts.currencies[toInt(e[1])]
+	if !ok {
+		log.Println("[POLONIEX] Balance of unknown currency: ", e[1])
+		return
+	}
+	delta := toString(e[3])
+	b := ts.balances[coin]
+	b.Coin = coin
+	b.Available += parseFloat(delta)
+	b.InOrders = ts.inOrders(coin)
+	b.Total = b.Available + b.InOrders
+	if b.AvailableDecimal.IsSet() {
+		b.AvailableDecimal = b.AvailableDecimal.Add(schemas.Decimal(delta))
+	} else {
+		b.AvailableDecimal = schemas.Decimal(delta)
+	}
+	b.InOrdersDecimal = schemas.DecimalFromFloat(b.InOrders)
+	b.TotalDecimal = b.AvailableDecimal.Add(b.InOrdersDecimal)
+	ts.balances[coin] = b
+
+	ts.push(schemas.UserInfoChannel{
+		DataType: dataTypeUpdate,
+		Data: schemas.UserInfo{
+			Balances: map[string]schemas.Balance{coin: b},
+		},
+	})
+}
+
+// inOrders - coin amount locked by open orders: quote coin by buy orders, base coin by sell orders
+func (ts *tradingStream) inOrders(coin string) (amount float64) {
+	for _, o := range ts.orders {
+		_, baseCoin, quoteCoin := parseSymbol(unparseSymbol(o.Symbol))
+		if o.Type == typeBuy && quoteCoin == coin {
+			amount += o.Price * o.Amount
+		}
+		if o.Type == typeSell && baseCoin == coin {
+			amount += o.Amount
+		}
+	}
+	return
+}
+
+// currencies - loading currencies IDs, balance notifications contain IDs only
+func (trading *TradingProvider) currencies() (resp map[int]string, err error) {
+	var b []byte
+	var currencies map[string]currency
+
+	query := httpclient.Params()
+	query.Set("command", commandCurrencies)
+	if b, err = trading.httpClient.Get(restURL, query, false); err != nil {
+		return
+	}
+	if err = json.Unmarshal(b, &currencies); err != nil {
+		return
+	}
+	resp = make(map[int]string)
+	for coin, c := range currencies {
+		resp[c.ID] = coin
+	}
+	return
+}
+
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	}
+	return ""
+}
+
+func toInt(v interface{}) int {
+	switch val := v.(type) {
+	case float64:
+		return int(val)
+	case string:
+		i, _ := strconv.Atoi(val)
+		return i
+	}
+	return 0
+}
+
+// parseDate - parsing poloniex UTC date into milliseconds timestamp
+func parseDate(s string) int64 {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		log.Println("[POLONIEX] Error parsing time: ", err)
+		return 0
+	}
+	return t.Unix() * 1000
+}
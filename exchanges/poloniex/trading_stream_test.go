@@ -0,0 +1,62 @@
+package poloniex
+
+import (
+	"testing"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+func TestTradingStreamHandle(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []interface{}
+		orders int
+		trades int
+	}{
+		{
+			name:   "placed order of snapshot is skipped",
+			events: []interface{}{[]interface{}{"n", 148.0, "1", 1.0, "0.01", "2", "2019-01-01 00:00:00"}},
+		},
+		{
+			name:   "trade of snapshot is skipped",
+			events: []interface{}{[]interface{}{"t", "10", "0.01", "1", "0.0025", 0.0, "1", "0.00001", "2019-01-01 00:00:00"}},
+		},
+		{
+			name:   "update not decreasing amount is skipped",
+			events: []interface{}{[]interface{}{"o", "1", "2", ""}},
+		},
+		{
+			name: "new trade and decreased amount are sent once",
+			events: []interface{}{
+				[]interface{}{"t", "11", "0.01", "1", "0.0025", 0.0, "1", "0.00001", "2019-01-01 00:00:00"},
+				[]interface{}{"o", "1", "1", ""},
+				[]interface{}{"t", "11", "0.01", "1", "0.0025", 0.0, "1", "0.00001", "2019-01-01 00:00:00"},
+				[]interface{}{"o", "1", "1", ""},
+			},
+			orders: 1,
+			trades: 1,
+		},
+	}
+	for _, tt := range tests {
+		ts := newTradingStream(&TradingProvider{})
+		ts.orders = map[string]schemas.Order{
+			"1": {ID: "1", Symbol: "ETH-BTC", Type: typeBuy, Price: 0.01, Amount: 2},
+		}
+		ts.trades = map[string]bool{"10": true}
+
+		ts.handle(tt.events)
+
+		var orders, trades int
+		for _, msg := range ts.outbox {
+			switch msg.(type) {
+			case schemas.UserOrdersChannel:
+				orders++
+			case schemas.UserTradesChannel:
+				trades++
+			}
+		}
+		if orders != tt.orders || trades != tt.trades {
+			t.Errorf("%s: orders = %d, trades = %d, want %d, %d", tt.name, orders, trades, tt.orders, tt.trades)
+		}
+	}
+}
@@ -187,6 +187,7 @@ func (uo *UserOrdersResponse) Map() (orders []schemas.Order) {
 type UserTradesResponse struct {
 	Success int                  `json:"success"`
 	Return  map[string]UserTrade `json:"return"`
+	Error   string               `json:"error"`
 }
 
 // UserTrade - Tidex trade
@@ -1,10 +1,9 @@
 package tidex
 
 import (
-	"log"
-	"sync"
 	"time"
 
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -12,42 +11,48 @@ import (
 // OrdersProvider - order book provider
 type OrdersProvider struct {
 	httpProxy proxy.Provider
-	symbols   []schemas.Symbol
-	books     []*OrderBookGroup
-	sync.Mutex
+	groups    *listing.Groups
 }
 
 // NewOrdersProvider - OrdersProvider constructor
 func NewOrdersProvider(httpProxy proxy.Provider) *OrdersProvider {
-	return &OrdersProvider{
+	ob := &OrdersProvider{
 		httpProxy: httpProxy,
 	}
+	ob.groups = listing.NewGroups(orderBookSymbolsLimit, ob.startGroup)
+	return ob
 }
 
-// SetSymbols - getting all symbols from Exchange
+// SetSymbols - replacing symbols, they are split into groups by symbols chunks on subscription.
+// Symbols of running subscription are changed by AddSymbols and RemoveSymbols
 func (ob *OrdersProvider) SetSymbols(symbols []schemas.Symbol) schemas.OrdersProvider {
-	log.Println("Symbols: ", len(symbols))
-	slice := make([]schemas.Symbol, len(symbols))
-	copy(slice, symbols)
-	capacity := orderBookSymbolsLimit
-	for {
-		if len(slice) <= capacity {
-			ob.books = append(
-				ob.books,
-				NewOrderBookGroup(slice, ob.httpProxy),
-			)
-			break
-		}
-		ob.books = append(
-			ob.books,
-			NewOrderBookGroup(slice[0:capacity], ob.httpProxy),
-		)
-
-		slice = slice[capacity:]
-	}
+	ob.groups.Reset(symbols)
 	return ob
 }
 
+// SubscribeAll - subscribing all groups
+func (ob *OrdersProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return ob.groups.Subscribe(d)
+}
+
+// AddSymbols - adding symbols to running subscription, i.e. new listings
+func (ob *OrdersProvider) AddSymbols(symbols []schemas.Symbol) {
+	ob.groups.Add(symbols)
+}
+
+// RemoveSymbols - removing symbols from subscription, i.e. delistings.
+// Groups polling them are restarted without them
+func (ob *OrdersProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	ob.groups.Remove(symbols)
+}
+
+// startGroup - creating and starting group of symbols
+func (ob *OrdersProvider) startGroup(symbols []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) listing.Group {
+	group := NewOrderBookGroup(symbols, ob.httpProxy)
+	go group.subscribe(ch, d)
+	return group
+}
+
 // Get - getting all symbols from Exchange
 func (ob *OrdersProvider) Get(symbol schemas.Symbol) (book schemas.OrderBook, err error) {
 	orderBookGroup := NewOrderBookGroup([]schemas.Symbol{symbol}, ob.httpProxy)
@@ -59,15 +64,3 @@ func (ob *OrdersProvider) Get(symbol schemas.Symbol) (book schemas.OrderBook, er
 func (ob *OrdersProvider) Subscribe(symbol schemas.Symbol, d time.Duration) (r chan schemas.ResultChannel) {
 	return
 }
-
-// SubscribeAll - getting all symbols from Exchange
-func (ob *OrdersProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
-	bufLength := 2 * len(ob.symbols)
-	ch := make(chan schemas.ResultChannel, bufLength)
-
-	for _, orderBook := range ob.books {
-		go orderBook.subscribe(ch, d)
-		time.Sleep(100 * time.Millisecond)
-	}
-	return ch
-}
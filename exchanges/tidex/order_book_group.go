@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/syndicatedb/goex/internal/diff"
 	"github.com/syndicatedb/goex/internal/http"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
@@ -18,6 +19,8 @@ type OrderBookGroup struct {
 	symbols      []schemas.Symbol
 	httpClient   *httpclient.Client
 	emptySymbols map[string]string
+	books        *diff.Books
+	stop         chan struct{}
 }
 
 // NewOrderBookGroup - OrderBook constructor
@@ -28,12 +31,17 @@ func NewOrderBookGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *Orde
 		symbols:      symbols,
 		httpClient:   httpclient.New(proxyClient),
 		emptySymbols: make(map[string]string),
+		books:        diff.NewBooks(),
+		stop:         make(chan struct{}),
 	}
 }
 
-// SubscribeAll - getting all symbols from Exchange
+/*
+subscribe - polling order books.
+Tidex doesn't have updates, so first book of symbol is sent as snapshot
+and next ones as updates with changed levels only
+*/
 func (ob *OrderBookGroup) subscribe(ch chan schemas.ResultChannel, d time.Duration) {
-	// i := 0
 	for {
 		book, err := ob.Get()
 		if err != nil {
@@ -43,22 +51,28 @@ func (ob *OrderBookGroup) subscribe(ch chan schemas.ResultChannel, d time.Durati
 			}
 		}
 		for _, b := range book {
+			changes, snapshot := ob.books.Update(b)
+			if !snapshot && diff.IsEmpty(changes) {
+				continue
+			}
 			ch <- schemas.ResultChannel{
-				DataType: "s",
-				Data:     b,
-				Error:    err,
+				DataType: diff.DataType(snapshot),
+				Data:     changes,
 			}
 		}
-		// i++
-		// if i%5 == 0 {
-		// 	if len(ob.emptySymbols) > 0 {
-		// 		log.Println("Empty: ", ob.emptySymbols)
-		// 	}
-		// }
-		time.Sleep(d)
+		select {
+		case <-ob.stop:
+			return
+		case <-time.After(d):
+		}
 	}
 }
 
+// Stop - stopping polling
+func (ob *OrderBookGroup) Stop() {
+	close(ob.stop)
+}
+
 // Get - getting all symbols from Exchange
 func (ob *OrderBookGroup) Get() (book map[string]schemas.OrderBook, err error) {
 	// start := time.Now().UnixNano() / 1000000
@@ -3,47 +3,54 @@ package tidex
 import (
 	"time"
 
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
 // QuotesProvider - provides quotes/ticker
 type QuotesProvider struct {
-	symbols   []schemas.Symbol
-	groups    []*QuotesGroup
 	httpProxy proxy.Provider
+	groups    *listing.Groups
 }
 
 // NewQuotesProvider - QuotesProvider constructor
 func NewQuotesProvider(httpProxy proxy.Provider) *QuotesProvider {
-	return &QuotesProvider{
+	qp := &QuotesProvider{
 		httpProxy: httpProxy,
 	}
+	qp.groups = listing.NewGroups(quotesSymbolsLimit, qp.startGroup)
+	return qp
 }
 
-// SetSymbols - getting all symbols from Exchange
+// SetSymbols - replacing symbols, they are split into groups by symbols chunks on subscription.
+// Symbols of running subscription are changed by AddSymbols and RemoveSymbols
 func (qp *QuotesProvider) SetSymbols(symbols []schemas.Symbol) schemas.QuotesProvider {
-	qp.symbols = symbols
-	slice := make([]schemas.Symbol, len(symbols))
-	copy(slice, symbols)
-	capacity := quotesSymbolsLimit
-	for {
-		if len(slice) <= capacity {
-			qp.groups = append(
-				qp.groups,
-				NewQuotesGroup(slice, qp.httpProxy),
-			)
-			break
-		}
-		qp.groups = append(
-			qp.groups,
-			NewQuotesGroup(slice[0:capacity], qp.httpProxy),
-		)
+	qp.groups.Reset(symbols)
+	return qp
+}
 
-		slice = slice[capacity:]
-	}
+// SubscribeAll - subscribing all groups
+func (qp *QuotesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return qp.groups.Subscribe(d)
+}
 
-	return qp
+// AddSymbols - adding symbols to running subscription, i.e. new listings
+func (qp *QuotesProvider) AddSymbols(symbols []schemas.Symbol) {
+	qp.groups.Add(symbols)
+}
+
+// RemoveSymbols - removing symbols from subscription, i.e. delistings.
+// Groups polling them are restarted without them
+func (qp *QuotesProvider) RemoveSymbols(symbols []schemas.Symbol) {
+	qp.groups.Remove(symbols)
+}
+
+// startGroup - creating and starting group of symbols
+func (qp *QuotesProvider) startGroup(symbols []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) listing.Group {
+	group := NewQuotesGroup(symbols, qp.httpProxy)
+	go group.subscribe(ch, d)
+	return group
 }
 
 // Get - getting quotes by symbol
@@ -64,15 +71,3 @@ func (qp *QuotesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan
 	go group.subscribe(ch, d)
 	return ch
 }
-
-// SubscribeAll - subscribing to all quotes with interval
-func (qp *QuotesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
-	bufLength := 2 * len(qp.symbols)
-	ch := make(chan schemas.ResultChannel, bufLength)
-
-	for _, group := range qp.groups {
-		go group.subscribe(ch, d)
-		time.Sleep(100 * time.Millisecond)
-	}
-	return ch
-}
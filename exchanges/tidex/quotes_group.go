@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/syndicatedb/goex/internal/diff"
 	"github.com/syndicatedb/goex/internal/http"
 	"github.com/syndicatedb/goex/internal/state"
 	"github.com/syndicatedb/goex/schemas"
@@ -17,6 +18,8 @@ type QuotesGroup struct {
 	symbols    []schemas.Symbol
 	httpClient *httpclient.Client
 	data       *state.State
+	last       *diff.Quotes
+	stop       chan struct{}
 }
 
 // NewQuotesGroup - OrderBook constructor
@@ -27,10 +30,12 @@ func NewQuotesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *QuotesG
 		symbols:    symbols,
 		httpClient: httpclient.New(proxyClient),
 		data:       state.New(),
+		last:       diff.NewQuotes(),
+		stop:       make(chan struct{}),
 	}
 }
 
-// SubscribeAll - getting all symbols from Exchange
+// subscribe - polling quotes, sending first quote of symbol as snapshot and changed quotes as updates
 func (q *QuotesGroup) subscribe(ch chan schemas.ResultChannel, d time.Duration) {
 	for {
 		quotes, err := q.Get()
@@ -42,16 +47,28 @@ func (q *QuotesGroup) subscribe(ch chan schemas.ResultChannel, d time.Duration)
 			}
 		}
 		for _, b := range quotes {
+			changed, snapshot := q.last.Update(b)
+			if !changed {
+				continue
+			}
 			ch <- schemas.ResultChannel{
 				Data:     b,
-				Error:    err,
-				DataType: "s",
+				DataType: diff.DataType(snapshot),
 			}
 		}
-		time.Sleep(d)
+		select {
+		case <-q.stop:
+			return
+		case <-time.After(d):
+		}
 	}
 }
 
+// Stop - stopping polling
+func (q *QuotesGroup) Stop() {
+	close(q.stop)
+}
+
 // Get - getting all quotes from Exchange
 func (q *QuotesGroup) Get() (quotes []schemas.Quote, err error) {
 	var b []byte
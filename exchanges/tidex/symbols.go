@@ -2,9 +2,11 @@ package tidex
 
 import (
 	"encoding/json"
+	"math"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/listing"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
@@ -36,16 +38,24 @@ func (sp *SymbolsProvider) Get() (symbols []schemas.Symbol, err error) {
 		if d.Hidden == 0 {
 			name, coin, baseCoin := parseSymbol(sname)
 			symbols = append(symbols, schemas.Symbol{
-				Name:           name,
-				OriginalName:   sname,
-				Coin:           coin,
-				BaseCoin:       baseCoin,
-				Fee:            d.Fee,
-				MinPrice:       d.MinPrice,
-				MaxPrice:       d.MaxPrice,
-				MinAmount:      d.MinAmount,
-				MaxAmount:      d.MaxAmount,
-				PricePrecision: d.DecimalPlaces,
+				Name:            name,
+				OriginalName:    sname,
+				Coin:            coin,
+				BaseCoin:        baseCoin,
+				Status:          schemas.SymbolStatusTrading,
+				OrderTypes:      []string{schemas.OrderTypeLimit},
+				Fee:             d.Fee / 100, // fee is in percents
+				MakerFee:        d.Fee / 100,
+				TakerFee:        d.Fee / 100,
+				TickSize:        math.Pow10(-d.DecimalPlaces),
+				LotStep:         math.Pow10(-d.DecimalPlaces),
+				MinPrice:        d.MinPrice,
+				MaxPrice:        d.MaxPrice,
+				MinAmount:       d.MinAmount,
+				MaxAmount:       d.MaxAmount,
+				MinNotional:     d.MinTotal,
+				PricePrecision:  d.DecimalPlaces,
+				AmountPrecision: d.DecimalPlaces,
 			})
 		}
 	}
@@ -68,3 +78,8 @@ func (sp *SymbolsProvider) Subscribe(d time.Duration) chan schemas.ResultChannel
 	}()
 	return ch
 }
+
+// SubscribeChanges - subscribing to symbols changes: listings, delistings, status and filters changes
+func (sp *SymbolsProvider) SubscribeChanges(d time.Duration) chan schemas.SymbolEventsChannel {
+	return listing.Watch(sp.Get, d)
+}
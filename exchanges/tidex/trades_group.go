@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/syndicatedb/goex/internal/diff"
 	"github.com/syndicatedb/goex/internal/http"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
@@ -16,6 +17,8 @@ import (
 type TradesGroup struct {
 	symbols    []schemas.Symbol
 	httpClient *httpclient.Client
+	seen       *diff.Trades
+	stop       chan struct{}
 }
 
 // NewTradesGroup - OrderBook constructor
@@ -25,18 +28,17 @@ func NewTradesGroup(symbols []schemas.Symbol, httpProxy proxy.Provider) *TradesG
 	return &TradesGroup{
 		symbols:    symbols,
 		httpClient: httpclient.New(proxyClient),
+		seen:       diff.NewTrades(),
+		stop:       make(chan struct{}),
 	}
 }
 
-// SubscribeAll - getting all symbols from Exchange
+/*
+subscribe - polling trades.
+Tidex doesn't have updates, so first trades of symbol are sent as snapshot
+and next ones as updates with trades not seen before only
+*/
 func (q *TradesGroup) subscribe(ch chan schemas.ResultChannel, d time.Duration) {
-	// Local map to store incremental snapshot for some amount of time
-	// Tidex doesn't have updates, only snapshots
-
-	tradesMap := make(map[string]schemas.Trade)
-
-	// Iterator to clean up map from time to time
-	i := 0
 	for {
 		trades, err := q.Get()
 		if err != nil {
@@ -44,52 +46,33 @@ func (q *TradesGroup) subscribe(ch chan schemas.ResultChannel, d time.Duration)
 				Data:  trades,
 				Error: err,
 			}
-		} else {
-			for _, b := range trades {
-				// Cleaning up snapshot map every 300 iterations
-				if i > 300 {
-					tradesMap = make(map[string]schemas.Trade)
-					i = 0
-				}
-				// If there is trades
-				if len(b) > 0 {
-
-					// By default dataType is (s)napshot
-					dataType := "s"
-
-					// if trades map is not empty, then we have history
-					if len(tradesMap) > 0 {
-						dataType = "u" // dataType is (u)pdate
-					}
-
-					// For updates or snapshot
-					var t []schemas.Trade
-
-					for _, trade := range b {
-						if _, ok := tradesMap[trade.ID]; ok == false {
-							// Appending to update
-							t = append(t, trade)
-							// Filling snapshot map
-							tradesMap[trade.ID] = trade
-						}
-					}
-					// Sending to listener
-					if len(t) > 0 {
-						log.Println("[TIDEX] Trades updates trades / input / processed: ", len(tradesMap), "/", len(b), "/", len(t))
-						ch <- schemas.ResultChannel{
-							DataType: dataType,
-							Data:     b,
-							Error:    err,
-						}
-					}
-				}
+		}
+		for _, b := range trades {
+			if len(b) == 0 {
+				continue
+			}
+			fresh, snapshot := q.seen.Update(b[0].Symbol, b)
+			if len(fresh) == 0 {
+				continue
+			}
+			ch <- schemas.ResultChannel{
+				DataType: diff.DataType(snapshot),
+				Data:     fresh,
 			}
 		}
-		i++
-		time.Sleep(d)
+		select {
+		case <-q.stop:
+			return
+		case <-time.After(d):
+		}
 	}
 }
 
+// Stop - stopping polling
+func (q *TradesGroup) Stop() {
+	close(q.stop)
+}
+
 // Get - getting all quotes from Exchange
 func (q *TradesGroup) Get() (trades [][]schemas.Trade, err error) {
 	var b []byte
@@ -5,14 +5,23 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/syndicatedb/goex/internal/http"
+	"github.com/syndicatedb/goex/internal/importer"
 	"github.com/syndicatedb/goex/schemas"
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
+const (
+	importLimit = 1000 // max trades in page
+	// noTrades - error of trades history request without trades
+	noTrades = "no trades"
+)
+
 // TradingProvider - provides quotes/ticker
 type TradingProvider struct {
 	credentials schemas.Credentials
@@ -153,13 +162,96 @@ func (trading *TradingProvider) Orders(symbols []schemas.Symbol) (orders []schem
 	return resp.Map(), nil
 }
 
+/*
+ImportTrades - importing trades of symbols, of all symbols if empty.
+Trades are paged by ID from first trade or checkpoint. Last message has DataTypeDone
+*/
 func (trading *TradingProvider) ImportTrades(opts schemas.FilterOptions) chan schemas.UserTradesChannel {
-	ch := make(chan schemas.UserTradesChannel)
-
-	trades, paging, err := trading.Trades(opts)
-	log.Println("paging: ", len(trades), paging, err)
+	// Tidex time filter is in seconds
+	if len(fmt.Sprintf("%d", opts.Before)) >= 12 {
+		opts.Before = opts.Before / 1000
+	}
+	if len(fmt.Sprintf("%d", opts.Since)) >= 12 {
+		opts.Since = opts.Since / 1000
+	}
+	// trade IDs are common for all pairs, so all symbols are imported by one cursor
+	tasks := []importer.Task{{
+		Symbol: "all",
+		Page:   trading.tradesPage(nil, opts),
+	}}
+	if len(opts.Symbols) > 0 {
+		tasks = nil
+		for _, s := range opts.Symbols {
+			tasks = append(tasks, importer.Task{
+				Symbol: s.Name,
+				Page:   trading.tradesPage([]schemas.Symbol{s}, opts),
+			})
+		}
+	}
+	return importer.Importer{
+		Exchange:    exchangeName,
+		APIKey:      trading.credentials.APIKey,
+		Checkpoints: opts.Checkpoints,
+		Acknowledge: opts.Acknowledge,
+		Delay:       time.Second,
+	}.Run(tasks)
+}
 
-	return ch
+/*
+tradesPage - loading trades from ID in ascending order: cursor is ID of next trade.
+Nonce is time in seconds, so pages are loaded with importer delay
+*/
+func (trading *TradingProvider) tradesPage(symbols []schemas.Symbol, opts schemas.FilterOptions) importer.Page {
+	return func(cursor schemas.Checkpoint) (trades []schemas.Trade, next schemas.Checkpoint, done bool, err error) {
+		var b []byte
+		next = cursor
+		payload := httpclient.Params()
+		payload.Set("method", "TradeHistory")
+		payload.Set("nonce", fmt.Sprintf("%d", time.Now().Unix()))
+		payload.Set("order", "ASC")
+		payload.Set("count", strconv.Itoa(importLimit))
+		if cursor.Cursor != "" {
+			payload.Set("from_id", cursor.Cursor)
+		}
+		if len(symbols) > 0 {
+			payload.Set("pair", symbolToPair(symbols[0].Name))
+		}
+		if opts.Since > 0 {
+			payload.Set("since", strconv.FormatInt(opts.Since, 10))
+		}
+		if opts.Before > 0 {
+			// end is inclusive
+			payload.Set("end", strconv.FormatInt(opts.Before-1, 10))
+		}
+		if b, err = trading.httpClient.Post(apiUserInfo, httpclient.Params(), payload, true); err != nil {
+			return
+		}
+		var resp UserTradesResponse
+		if err = json.Unmarshal(b, &resp); err != nil {
+			return
+		}
+		if resp.Success == 0 {
+			if strings.Contains(resp.Error, noTrades) {
+				return nil, next, true, nil
+			}
+			err = errors.New(resp.Error)
+			return
+		}
+		trades = resp.Map()
+		ids := make(map[string]int64, len(trades))
+		for _, t := range trades {
+			ids[t.ID], _ = strconv.ParseInt(t.ID, 10, 64)
+		}
+		sort.Slice(trades, func(i, j int) bool {
+			return ids[trades[i].ID] < ids[trades[j].ID]
+		})
+		if len(trades) > 0 {
+			last := trades[len(trades)-1]
+			next.Cursor = strconv.FormatInt(ids[last.ID]+1, 10)
+			next.Timestamp = last.Timestamp
+		}
+		return trades, next, len(resp.Return) < importLimit, nil
+	}
 }
 
 // Trades - getting user trades
@@ -199,6 +291,10 @@ func (trading *TradingProvider) Trades(opts schemas.FilterOptions) (trades []sch
 func (trading *TradingProvider) Create(order schemas.Order) (result schemas.Order, err error) {
 	var b []byte
 
+	if order, err = schemas.PrepareOrder(trading.symbols, order); err != nil {
+		return
+	}
+
 	payload := httpclient.Params()
 	payload.Set("method", "Trade")
 	payload.Set("nonce", fmt.Sprintf("%d", time.Now().Unix()))
@@ -206,8 +302,8 @@ func (trading *TradingProvider) Create(order schemas.Order) (result schemas.Orde
 	pair := symbolToPair(order.Symbol)
 	payload.Set("pair", pair)
 	payload.Set("type", strings.ToLower(order.Type))
-	payload.Set("rate", fmt.Sprintf("%.10f", order.Price))
-	payload.Set("amount", fmt.Sprintf("%.10f", order.Amount))
+	payload.Set("rate", order.PriceValue())
+	payload.Set("amount", order.AmountValue())
 
 	b, err = trading.httpClient.Post(apiUserInfo, httpclient.Params(), payload, true)
 	if err != nil {
@@ -256,3 +352,22 @@ func (trading *TradingProvider) CancelAll() (err error) {
 	}
 	return
 }
+
+// CancelAllBy - cancelling orders by symbols and side
+func (trading *TradingProvider) CancelAllBy(symbols []schemas.Symbol, side string) (cancelled []schemas.Order, err error) {
+	var orders []schemas.Order
+	if len(symbols) == 0 {
+		return nil, schemas.ErrEmptySymbols
+	}
+	if orders, err = trading.Orders(symbols); err != nil {
+		return
+	}
+	for _, o := range schemas.FilterOrders(orders, symbols, side) {
+		if err = trading.Cancel(o); err != nil {
+			return
+		}
+		o.Status = schemas.StatusCancelled
+		cancelled = append(cancelled, o)
+	}
+	return
+}
@@ -0,0 +1,190 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/syndicatedb/goex/internal/parquet"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// Trades layouts
+const (
+	// LayoutTrades - trade fields, columns can be chosen
+	LayoutTrades = "trades"
+	// LayoutTax - generic tax report: what was sent and received for every trade
+	LayoutTax = "tax"
+)
+
+// column - exported field: name, parquet type and value of row
+type column struct {
+	name  string
+	typ   int
+	value func(r row) interface{}
+}
+
+// row - exported item with exchange name
+type row struct {
+	exchange string
+	at       time.Time // snapshot time of balances
+	trade    schemas.Trade
+	order    schemas.Order
+	wallet   string
+	balance  schemas.Balance
+}
+
+func stringColumn(name string, value func(r row) string) column {
+	return column{name: name, typ: parquet.String, value: func(r row) interface{} { return value(r) }}
+}
+
+func floatColumn(name string, value func(r row) float64) column {
+	return column{name: name, typ: parquet.Double, value: func(r row) interface{} { return value(r) }}
+}
+
+func intColumn(name string, value func(r row) int64) column {
+	return column{name: name, typ: parquet.Int64, value: func(r row) interface{} { return value(r) }}
+}
+
+// tradeColumns - columns of trades layout
+var tradeColumns = []column{
+	stringColumn("exchange", func(r row) string { return r.exchange }),
+	stringColumn("id", func(r row) string { return r.trade.ID }),
+	stringColumn("order_id", func(r row) string { return r.trade.OrderID }),
+	stringColumn("symbol", func(r row) string { return r.trade.Symbol }),
+	stringColumn("base", func(r row) string { return schemas.BaseCoinOf(r.trade.Symbol) }),
+	stringColumn("quote", func(r row) string { return schemas.QuoteCoinOf(r.trade.Symbol) }),
+	stringColumn("side", func(r row) string { return side(r.trade.Type) }),
+	floatColumn("price", func(r row) float64 { return r.trade.Price }),
+	floatColumn("amount", func(r row) float64 { return r.trade.Amount }),
+	floatColumn("total", func(r row) float64 { return r.trade.Price * r.trade.Amount }),
+	floatColumn("fee", func(r row) float64 { return r.trade.Fee }),
+	stringColumn("fee_coin", func(r row) string { return feeCoin(r.trade) }),
+	stringColumn("time", func(r row) string { return formatTime(schemas.UnixTime(r.trade.Timestamp)) }),
+	intColumn("timestamp", func(r row) int64 { return schemas.UnixTime(r.trade.Timestamp).UnixNano() / int64(time.Millisecond) }),
+}
+
+/*
+taxColumns - columns of tax layout. Buying sends quote coin and receives base coin, selling is opposite.
+Fee currency is quote coin when exchange doesn't send fee coin
+*/
+var taxColumns = []column{
+	stringColumn("Date", func(r row) string { return formatTime(schemas.UnixTime(r.trade.Timestamp)) }),
+	stringColumn("Type", func(r row) string {
+		switch side(r.trade.Type) {
+		case schemas.TypeBuy:
+			return "Buy"
+		case schemas.TypeSell:
+			return "Sell"
+		}
+		return r.trade.Type
+	}),
+	floatColumn("Sent Amount", func(r row) float64 {
+		if side(r.trade.Type) == schemas.TypeBuy {
+			return r.trade.Price * r.trade.Amount
+		}
+		return r.trade.Amount
+	}),
+	stringColumn("Sent Currency", func(r row) string {
+		if side(r.trade.Type) == schemas.TypeBuy {
+			return schemas.QuoteCoinOf(r.trade.Symbol)
+		}
+		return schemas.BaseCoinOf(r.trade.Symbol)
+	}),
+	floatColumn("Received Amount", func(r row) float64 {
+		if side(r.trade.Type) == schemas.TypeBuy {
+			return r.trade.Amount
+		}
+		return r.trade.Price * r.trade.Amount
+	}),
+	stringColumn("Received Currency", func(r row) string {
+		if side(r.trade.Type) == schemas.TypeBuy {
+			return schemas.BaseCoinOf(r.trade.Symbol)
+		}
+		return schemas.QuoteCoinOf(r.trade.Symbol)
+	}),
+	floatColumn("Fee Amount", func(r row) float64 { return r.trade.Fee }),
+	stringColumn("Fee Currency", func(r row) string { return feeCoin(r.trade) }),
+	stringColumn("Exchange", func(r row) string { return r.exchange }),
+	stringColumn("Trade ID", func(r row) string { return r.trade.ID }),
+}
+
+var orderColumns = []column{
+	stringColumn("exchange", func(r row) string { return r.exchange }),
+	stringColumn("id", func(r row) string { return r.order.ID }),
+	stringColumn("symbol", func(r row) string { return r.order.Symbol }),
+	stringColumn("side", func(r row) string { return side(r.order.Type) }),
+	floatColumn("price", func(r row) float64 { return r.order.Price }),
+	floatColumn("amount", func(r row) float64 { return r.order.Amount }),
+	floatColumn("amount_filled", func(r row) float64 { return r.order.AmountFilled }),
+	stringColumn("status", func(r row) string { return r.order.Status }),
+	stringColumn("created_at", func(r row) string {
+		if r.order.CreatedAt == 0 {
+			return ""
+		}
+		return formatTime(schemas.UnixTime(r.order.CreatedAt))
+	}),
+}
+
+var balanceColumns = []column{
+	stringColumn("time", func(r row) string { return formatTime(r.at) }),
+	stringColumn("exchange", func(r row) string { return r.exchange }),
+	stringColumn("wallet", func(r row) string { return r.wallet }),
+	stringColumn("coin", func(r row) string { return r.balance.Coin }),
+	floatColumn("available", func(r row) float64 { return r.balance.Available }),
+	floatColumn("in_orders", func(r row) float64 { return r.balance.InOrders }),
+	floatColumn("total", func(r row) float64 { return r.balance.Total }),
+}
+
+// TradeColumns - column names of trades layout
+func TradeColumns() []string {
+	names := make([]string, len(tradeColumns))
+	for i, c := range tradeColumns {
+		names[i] = c.name
+	}
+	return names
+}
+
+// selectColumns - columns by names in given order, all columns if names are empty
+func selectColumns(all []column, names []string) ([]column, error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+	result := make([]column, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, c := range all {
+			if c.name == name {
+				result = append(result, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Unknown column: %s", name)
+		}
+	}
+	return result, nil
+}
+
+// side - BUY or SELL, some exchanges send bid and ask
+func side(t string) string {
+	switch t = strings.ToUpper(t); t {
+	case "BID":
+		return schemas.TypeBuy
+	case "ASK":
+		return schemas.TypeSell
+	}
+	return t
+}
+
+func feeCoin(t schemas.Trade) string {
+	if t.FeeCoin != "" {
+		return t.FeeCoin
+	}
+	return schemas.QuoteCoinOf(t.Symbol)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,195 @@
+/*
+Package export writes user trades, orders and balances snapshots
+to CSV, JSON lines and Parquet files
+*/
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/syndicatedb/goex/internal/parquet"
+)
+
+// Formats
+const (
+	FormatCSV     = "csv"
+	FormatJSONL   = "jsonl"
+	FormatParquet = "parquet"
+)
+
+// ErrParquetExists - parquet file can't be appended, and previous export isn't overwritten
+var ErrParquetExists = errors.New("Parquet file exists, it can't be appended")
+
+// table - file of rows with columns
+type table interface {
+	write(r row) error
+	// flush - writing buffered rows to file, parquet rows are written on Close only
+	flush() error
+	Close() error
+}
+
+/*
+openTable - opening file of format.
+CSV and JSON lines files are appended, CSV header is written to new file only.
+Parquet file can't be appended, it's created and existing file isn't overwritten
+*/
+func openTable(path, format string, columns []column) (table, error) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if format == FormatParquet {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_EXCL
+	} else if format != FormatCSV && format != FormatJSONL {
+		return nil, fmt.Errorf("Unknown format: %s", format)
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if format == FormatParquet && os.IsExist(err) {
+		return nil, ErrParquetExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatCSV:
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		t := &csvTable{
+			file:    f,
+			w:       csv.NewWriter(f),
+			columns: columns,
+		}
+		if info.Size() == 0 {
+			header := make([]string, len(columns))
+			for i, c := range columns {
+				header[i] = c.name
+			}
+			if err := t.w.Write(header); err != nil {
+				f.Close()
+				return nil, err
+			}
+		}
+		return t, nil
+	case FormatJSONL:
+		return &jsonlTable{
+			file:    f,
+			w:       bufio.NewWriter(f),
+			columns: columns,
+		}, nil
+	}
+	parquetColumns := make([]parquet.Column, len(columns))
+	for i, c := range columns {
+		parquetColumns[i] = parquet.Column{Name: c.name, Type: c.typ}
+	}
+	return &parquetTable{
+		file:    f,
+		w:       parquet.NewWriter(f, parquetColumns),
+		columns: columns,
+	}, nil
+}
+
+type csvTable struct {
+	file    *os.File
+	w       *csv.Writer
+	columns []column
+}
+
+func (t *csvTable) write(r row) error {
+	record := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		switch v := c.value(r).(type) {
+		case string:
+			record[i] = v
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case int64:
+			record[i] = strconv.FormatInt(v, 10)
+		}
+	}
+	return t.w.Write(record)
+}
+
+func (t *csvTable) flush() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+func (t *csvTable) Close() error {
+	t.w.Flush()
+	if err := t.w.Error(); err != nil {
+		t.file.Close()
+		return err
+	}
+	return t.file.Close()
+}
+
+type jsonlTable struct {
+	file    *os.File
+	w       *bufio.Writer
+	columns []column
+}
+
+func (t *jsonlTable) write(r row) error {
+	// keys are written in columns order
+	line := []byte{'{'}
+	for i, c := range t.columns {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		key, _ := json.Marshal(c.name)
+		value, err := json.Marshal(c.value(r))
+		if err != nil {
+			return err
+		}
+		line = append(line, key...)
+		line = append(line, ':')
+		line = append(line, value...)
+	}
+	line = append(line, '}', '\n')
+	_, err := t.w.Write(line)
+	return err
+}
+
+func (t *jsonlTable) flush() error {
+	return t.w.Flush()
+}
+
+func (t *jsonlTable) Close() error {
+	if err := t.w.Flush(); err != nil {
+		t.file.Close()
+		return err
+	}
+	return t.file.Close()
+}
+
+type parquetTable struct {
+	file    *os.File
+	w       *parquet.Writer
+	columns []column
+}
+
+func (t *parquetTable) write(r row) error {
+	values := make([]interface{}, len(t.columns))
+	for i, c := range t.columns {
+		values[i] = c.value(r)
+	}
+	return t.w.Write(values)
+}
+
+func (t *parquetTable) flush() error {
+	return nil
+}
+
+func (t *parquetTable) Close() error {
+	if err := t.w.Close(); err != nil {
+		t.file.Close()
+		return err
+	}
+	return t.file.Close()
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"sort"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// WriteOrders - writing orders of exchange, i.e. open orders snapshot
+func WriteOrders(path, format, exchange string, orders []schemas.Order) (err error) {
+	t, err := openTable(path, format, orderColumns)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if cerr := t.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	for _, o := range orders {
+		if err = t.write(row{exchange: exchange, order: o}); err != nil {
+			return
+		}
+	}
+	return
+}
+
+/*
+WriteBalances - writing balances snapshot of exchange taken at time:
+balances of every wallet, exchange wallet only if exchange has no wallets. Coins are sorted
+*/
+func WriteBalances(path, format, exchange string, info schemas.UserInfo, at time.Time) (err error) {
+	t, err := openTable(path, format, balanceColumns)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if cerr := t.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	wallets := info.Wallets
+	if wallets == nil {
+		wallets = map[string]map[string]schemas.Balance{schemas.WalletExchange: info.Balances}
+	}
+	names := make([]string, 0, len(wallets))
+	for name := range wallets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, wallet := range names {
+		coins := make([]string, 0, len(wallets[wallet]))
+		for coin := range wallets[wallet] {
+			coins = append(coins, coin)
+		}
+		sort.Strings(coins)
+		for _, coin := range coins {
+			b := wallets[wallet][coin]
+			if b.Coin == "" {
+				b.Coin = coin
+			}
+			if err = t.write(row{exchange: exchange, at: at, wallet: wallet, balance: b}); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,207 @@
+package export
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// ErrParquetIndex - parquet file is written once by every export, so index would skip trades of other files
+var ErrParquetIndex = errors.New("Index can't be used with parquet format")
+
+/*
+Index - exported trades keys file, one key per line.
+It's kept between import runs, so trades exported before are skipped
+*/
+type Index struct {
+	file *os.File
+	seen map[string]bool
+
+	sync.Mutex
+}
+
+// OpenIndex - loading keys file, it's created if it doesn't exist
+func OpenIndex(path string) (*Index, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	idx := &Index{
+		file: f,
+		seen: make(map[string]bool),
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if key := scanner.Text(); key != "" {
+			idx.seen[key] = true
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return idx, nil
+}
+
+// Has - key is exported
+func (idx *Index) Has(key string) bool {
+	idx.Lock()
+	defer idx.Unlock()
+	return idx.seen[key]
+}
+
+// Add - saving exported key
+func (idx *Index) Add(key string) error {
+	idx.Lock()
+	defer idx.Unlock()
+	if idx.seen[key] {
+		return nil
+	}
+	if _, err := idx.file.WriteString(key + "\n"); err != nil {
+		return err
+	}
+	idx.seen[key] = true
+	return nil
+}
+
+// Close - closing keys file
+func (idx *Index) Close() error {
+	return idx.file.Close()
+}
+
+/*
+TradeKey - exchange, symbol and trade ID, IDs of some exchanges are unique by symbol only.
+Trades without ID are compared by order ID, price, amount and time
+*/
+func TradeKey(exchange string, t schemas.Trade) string {
+	id := t.ID
+	if id == "" {
+		id = strings.Join([]string{
+			t.OrderID,
+			schemas.DecimalFromFloat(t.Price).String(),
+			schemas.DecimalFromFloat(t.Amount).String(),
+			schemas.DecimalFromFloat(float64(t.Timestamp)).String(),
+		}, ":")
+	}
+	return exchange + ":" + t.Symbol + ":" + id
+}
+
+// TradesOptions - trades file options
+type TradesOptions struct {
+	Format   string
+	Exchange string // exchange name written to every row
+	Layout   string // LayoutTrades by default
+	// columns of trades layout in order, all columns if empty. See TradeColumns
+	Columns []string
+	// exported trades keys, trades which are in index are skipped.
+	// Optional, can't be used with parquet format
+	Index *Index
+}
+
+// TradesExporter - writing user trades of exchange to file
+type TradesExporter struct {
+	opts  TradesOptions
+	table table
+	seen  map[string]bool
+	// keys of written trades, they're added to index when file is closed
+	written []string
+}
+
+// NewTradesExporter - opening trades file
+func NewTradesExporter(path string, opts TradesOptions) (*TradesExporter, error) {
+	if opts.Format == FormatParquet && opts.Index != nil {
+		return nil, ErrParquetIndex
+	}
+	var columns []column
+	var err error
+	switch opts.Layout {
+	case "", LayoutTrades:
+		if columns, err = selectColumns(tradeColumns, opts.Columns); err != nil {
+			return nil, err
+		}
+	case LayoutTax:
+		columns = taxColumns
+	default:
+		return nil, fmt.Errorf("Unknown layout: %s", opts.Layout)
+	}
+	t, err := openTable(path, opts.Format, columns)
+	if err != nil {
+		return nil, err
+	}
+	return &TradesExporter{
+		opts:  opts,
+		table: t,
+		seen:  make(map[string]bool),
+	}, nil
+}
+
+// Write - writing trades which aren't exported yet, count of written trades is returned
+func (e *TradesExporter) Write(trades []schemas.Trade) (written int, err error) {
+	for _, t := range trades {
+		key := TradeKey(e.opts.Exchange, t)
+		if e.seen[key] || (e.opts.Index != nil && e.opts.Index.Has(key)) {
+			continue
+		}
+		if err = e.table.write(row{exchange: e.opts.Exchange, trade: t}); err != nil {
+			return
+		}
+		e.seen[key] = true
+		e.written = append(e.written, key)
+		written++
+	}
+	return
+}
+
+/*
+Import - writing trades batches of ImportTrades channel until it's closed.
+Batches with error are skipped, last error is returned: error of final status if import failed.
+Batch is flushed to file and acknowledged (ImportTrades with Acknowledge option),
+so checkpoint isn't saved before trades are written. Write error cancels acknowledged import.
+After write error channel is drained without writing, so import isn't blocked, and write error is returned
+*/
+func (e *TradesExporter) Import(ch chan schemas.UserTradesChannel) (written int, err error) {
+	var werr error
+	for msg := range ch {
+		if msg.Error != nil {
+			err = msg.Error
+			continue
+		}
+		if werr == nil {
+			var n int
+			n, werr = e.Write(msg.Data)
+			written += n
+			if werr == nil {
+				werr = e.table.flush()
+			}
+		}
+		if msg.Ack != nil {
+			msg.Ack(werr)
+		}
+	}
+	if werr != nil {
+		err = werr
+	}
+	return
+}
+
+// Close - flushing and closing file, keys of written trades are added to index when file is written
+func (e *TradesExporter) Close() error {
+	if err := e.table.Close(); err != nil {
+		return err
+	}
+	if e.opts.Index == nil {
+		return nil
+	}
+	for _, key := range e.written {
+		if err := e.opts.Index.Add(key); err != nil {
+			return err
+		}
+	}
+	e.written = nil
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+Package diff turns polled snapshots into updates for exchanges without websocket API.
+First data of symbol is a snapshot, next ones contain changes only
+*/
+package diff
+
+import (
+	"sync"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// seenTradesLimit - trade IDs kept by symbol, older IDs are forgotten first
+const seenTradesLimit = 5000
+
+// Books - last polled order books by symbol
+type Books struct {
+	books map[string]levels
+	sync.Mutex
+}
+
+// levels - book side levels by price
+type levels struct {
+	buy  map[float64]schemas.Order
+	sell map[float64]schemas.Order
+}
+
+// NewBooks - Books constructor
+func NewBooks() *Books {
+	return &Books{
+		books: make(map[string]levels),
+	}
+}
+
+/*
+Update - storing polled book and returning changed levels:
+new and changed levels as is, removed levels with zero amount and Remove flag.
+Snapshot flag is set for first book of symbol, book is returned as is then
+*/
+func (b *Books) Update(book schemas.OrderBook) (changes schemas.OrderBook, snapshot bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	next := levels{
+		buy:  byPrice(book.Buy),
+		sell: byPrice(book.Sell),
+	}
+	prev, ok := b.books[book.Symbol]
+	b.books[book.Symbol] = next
+	if !ok {
+		return book, true
+	}
+	return schemas.OrderBook{
+		Symbol: book.Symbol,
+		Buy:    changedLevels(book.Symbol, prev.buy, next.buy),
+		Sell:   changedLevels(book.Symbol, prev.sell, next.sell),
+	}, false
+}
+
+// Reset - forgetting symbol book, next polled book will be a snapshot
+func (b *Books) Reset(symbol string) {
+	b.Lock()
+	defer b.Unlock()
+	delete(b.books, symbol)
+}
+
+// IsEmpty - checking book has no levels
+func IsEmpty(book schemas.OrderBook) bool {
+	return len(book.Buy) == 0 && len(book.Sell) == 0
+}
+
+func byPrice(orders []schemas.Order) map[float64]schemas.Order {
+	m := make(map[float64]schemas.Order, len(orders))
+	for _, o := range orders {
+		// exchanges could send same price twice, amounts are summed
+		if prev, ok := m[o.Price]; ok {
+			o.Amount += prev.Amount
+			o.Count += prev.Count
+		}
+		m[o.Price] = o
+	}
+	return m
+}
+
+func changedLevels(symbol string, prev, next map[float64]schemas.Order) (changes []schemas.Order) {
+	for price, o := range next {
+		if p, ok := prev[price]; !ok || p.Amount != o.Amount {
+			changes = append(changes, o)
+		}
+	}
+	for price := range prev {
+		if _, ok := next[price]; !ok {
+			changes = append(changes, schemas.Order{
+				Symbol: symbol,
+				Price:  price,
+				Remove: 1,
+			})
+		}
+	}
+	return
+}
+
+// Trades - IDs of polled trades by symbol
+type Trades struct {
+	seen map[string]*seenTrades
+	sync.Mutex
+}
+
+type seenTrades struct {
+	ids   map[string]struct{}
+	order []string
+}
+
+// NewTrades - Trades constructor
+func NewTrades() *Trades {
+	return &Trades{
+		seen: make(map[string]*seenTrades),
+	}
+}
+
+/*
+Update - returning trades not seen before.
+Snapshot flag is set for first trades of symbol, all of them are returned then
+*/
+func (t *Trades) Update(symbol string, trades []schemas.Trade) (fresh []schemas.Trade, snapshot bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	s, ok := t.seen[symbol]
+	if !ok {
+		s = &seenTrades{ids: make(map[string]struct{})}
+		t.seen[symbol] = s
+	}
+	for _, trade := range trades {
+		if _, exists := s.ids[trade.ID]; exists {
+			continue
+		}
+		s.ids[trade.ID] = struct{}{}
+		s.order = append(s.order, trade.ID)
+		fresh = append(fresh, trade)
+	}
+	for len(s.order) > seenTradesLimit {
+		delete(s.ids, s.order[0])
+		s.order = s.order[1:]
+	}
+	return fresh, !ok
+}
+
+// Quotes - last polled quotes by symbol
+type Quotes struct {
+	quotes map[string]schemas.Quote
+	sync.Mutex
+}
+
+// NewQuotes - Quotes constructor
+func NewQuotes() *Quotes {
+	return &Quotes{
+		quotes: make(map[string]schemas.Quote),
+	}
+}
+
+/*
+Update - checking quote is changed since last poll.
+Snapshot flag is set for first quote of symbol
+*/
+func (q *Quotes) Update(quote schemas.Quote) (changed, snapshot bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	prev, ok := q.quotes[quote.Symbol]
+	q.quotes[quote.Symbol] = quote
+	return !ok || prev != quote, !ok
+}
+
+// DataType - snapshot or update marker of result channel
+func DataType(snapshot bool) string {
+	if snapshot {
+		return schemas.DataTypeSnapshot
+	}
+	return schemas.DataTypeUpdate
+}
@@ -0,0 +1,163 @@
+package diff
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+func level(price, amount float64) schemas.Order {
+	return schemas.Order{Symbol: "BTC-USDT", Price: price, Amount: amount}
+}
+
+func removed(price float64) schemas.Order {
+	return schemas.Order{Symbol: "BTC-USDT", Price: price, Remove: 1}
+}
+
+func sortedLevels(orders []schemas.Order) []schemas.Order {
+	res := append([]schemas.Order(nil), orders...)
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Price < res[j].Price
+	})
+	return res
+}
+
+func equalLevels(a, b []schemas.Order) bool {
+	a, b = sortedLevels(a), sortedLevels(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBooksUpdate(t *testing.T) {
+	first := schemas.OrderBook{
+		Symbol: "BTC-USDT",
+		Buy:    []schemas.Order{level(99, 1), level(98, 2)},
+		Sell:   []schemas.Order{level(101, 1), level(102, 3)},
+	}
+	tests := []struct {
+		name     string
+		book     schemas.OrderBook
+		snapshot bool
+		buy      []schemas.Order
+		sell     []schemas.Order
+	}{
+		{
+			name:     "first book is snapshot",
+			book:     first,
+			snapshot: true,
+			buy:      first.Buy,
+			sell:     first.Sell,
+		},
+		{
+			name: "same book has no changes",
+			book: first,
+		},
+		{
+			name: "changed, new and removed levels",
+			book: schemas.OrderBook{
+				Symbol: "BTC-USDT",
+				Buy:    []schemas.Order{level(99, 1.5), level(98, 2), level(97, 4)},
+				Sell:   []schemas.Order{level(102, 3)},
+			},
+			buy:  []schemas.Order{level(99, 1.5), level(97, 4)},
+			sell: []schemas.Order{removed(101)},
+		},
+		{
+			name: "duplicate prices are summed",
+			book: schemas.OrderBook{
+				Symbol: "BTC-USDT",
+				Buy:    []schemas.Order{level(99, 1), level(99, 0.5), level(98, 2), level(97, 4)},
+				Sell:   []schemas.Order{level(102, 3)},
+			},
+		},
+		{
+			name: "empty book removes all levels",
+			book: schemas.OrderBook{Symbol: "BTC-USDT"},
+			buy:  []schemas.Order{removed(99), removed(98), removed(97)},
+			sell: []schemas.Order{removed(102)},
+		},
+	}
+	books := NewBooks()
+	for _, tt := range tests {
+		changes, snapshot := books.Update(tt.book)
+		if snapshot != tt.snapshot {
+			t.Errorf("%s: snapshot = %v, want %v", tt.name, snapshot, tt.snapshot)
+		}
+		if !equalLevels(changes.Buy, tt.buy) {
+			t.Errorf("%s: buy = %v, want %v", tt.name, changes.Buy, tt.buy)
+		}
+		if !equalLevels(changes.Sell, tt.sell) {
+			t.Errorf("%s: sell = %v, want %v", tt.name, changes.Sell, tt.sell)
+		}
+		if empty := len(tt.buy)+len(tt.sell) == 0; IsEmpty(changes) != empty {
+			t.Errorf("%s: IsEmpty = %v, want %v", tt.name, IsEmpty(changes), empty)
+		}
+	}
+
+	books.Reset("BTC-USDT")
+	if _, snapshot := books.Update(first); !snapshot {
+		t.Error("book after Reset is not snapshot")
+	}
+}
+
+func TestTradesUpdate(t *testing.T) {
+	trade := func(id string) schemas.Trade {
+		return schemas.Trade{ID: id, Symbol: "BTC-USDT"}
+	}
+	tests := []struct {
+		trades   []schemas.Trade
+		fresh    []string
+		snapshot bool
+	}{
+		{[]schemas.Trade{trade("1"), trade("2")}, []string{"1", "2"}, true},
+		{[]schemas.Trade{trade("1"), trade("2")}, nil, false},
+		{[]schemas.Trade{trade("2"), trade("3"), trade("3")}, []string{"3"}, false},
+	}
+	trades := NewTrades()
+	for i, tt := range tests {
+		fresh, snapshot := trades.Update("BTC-USDT", tt.trades)
+		var ids []string
+		for _, f := range fresh {
+			ids = append(ids, f.ID)
+		}
+		if snapshot != tt.snapshot || len(ids) != len(tt.fresh) {
+			t.Errorf("#%d: fresh = %v, snapshot = %v, want %v, %v", i, ids, snapshot, tt.fresh, tt.snapshot)
+			continue
+		}
+		for j := range ids {
+			if ids[j] != tt.fresh[j] {
+				t.Errorf("#%d: fresh = %v, want %v", i, ids, tt.fresh)
+			}
+		}
+	}
+}
+
+func TestQuotesUpdate(t *testing.T) {
+	q := schemas.Quote{Symbol: "BTC-USDT", Price: 100}
+	quotes := NewQuotes()
+	tests := []struct {
+		quote             schemas.Quote
+		changed, snapshot bool
+	}{
+		{q, true, true},
+		{q, false, false},
+		{schemas.Quote{Symbol: "BTC-USDT", Price: 101}, true, false},
+	}
+	for i, tt := range tests {
+		changed, snapshot := quotes.Update(tt.quote)
+		if changed != tt.changed || snapshot != tt.snapshot {
+			t.Errorf("#%d: changed, snapshot = %v, %v, want %v, %v", i, changed, snapshot, tt.changed, tt.snapshot)
+		}
+	}
+	if DataType(true) != schemas.DataTypeSnapshot || DataType(false) != schemas.DataTypeUpdate {
+		t.Error("DataType markers are not schemas data types")
+	}
+}
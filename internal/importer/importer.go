@@ -0,0 +1,204 @@
+/*
+Package importer runs trades import of exchange: symbols are paged by cursor,
+cursor is saved to checkpoint after every page, failed pages are retried with backoff.
+Checkpoint of page can wait for consumer acknowledge. Channel is closed after final status message
+*/
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// Defaults
+const (
+	attempts   = 5
+	backoff    = time.Second
+	maxBackoff = time.Minute
+)
+
+/*
+Page - loading trades from cursor.
+Returns cursor of next page and done when there is nothing to load after it
+*/
+type Page func(cursor schemas.Checkpoint) (trades []schemas.Trade, next schemas.Checkpoint, done bool, err error)
+
+// Task - import of one symbol
+type Task struct {
+	Symbol string             // symbol name of checkpoint key
+	Start  schemas.Checkpoint // cursor when there is no checkpoint
+	Page   Page
+}
+
+// Importer - trades import of exchange account
+type Importer struct {
+	Exchange    string
+	APIKey      string // account of checkpoint keys, it's hashed
+	Checkpoints schemas.Checkpoints
+	// checkpoint of page is saved after consumer acknowledges it, see schemas.UserTradesChannel
+	Acknowledge bool
+	Delay       time.Duration // between pages
+
+	// retrying failed page, defaults are used when zero
+	Attempts   int
+	Backoff    time.Duration // doubled after every attempt
+	MaxBackoff time.Duration
+}
+
+/*
+Key - checkpoint key of exchange, account and symbol.
+Account is short hash of API key, so keys file doesn't keep credentials
+*/
+func Key(exchange, apiKey, symbol string) string {
+	h := sha256.Sum256([]byte(apiKey))
+	return exchange + ":" + hex.EncodeToString(h[:8]) + ":" + symbol
+}
+
+// cancelled - error of page acknowledge, import is stopped
+type cancelled struct {
+	err error
+}
+
+func (c cancelled) Error() string {
+	return c.err.Error()
+}
+
+/*
+Run - importing tasks one by one and sending pages of trades.
+Task which failed on all attempts is stopped, its checkpoint stays on last imported page
+and next tasks are imported. Page acknowledged with error stops whole import before its checkpoint.
+Last message has DataTypeDone and error of failed tasks
+*/
+func (im Importer) Run(tasks []Task) chan schemas.UserTradesChannel {
+	ch := make(chan schemas.UserTradesChannel)
+	go func() {
+		var failed []string
+		var lastErr error
+		var stopped error
+		for _, task := range tasks {
+			err := im.run(task, ch)
+			if c, ok := err.(cancelled); ok {
+				stopped = fmt.Errorf("Trades import cancelled on %s: %v", task.Symbol, c.err)
+				break
+			}
+			if err != nil {
+				log.Printf("[%s] Error importing trades of %s: %v\n", strings.ToUpper(im.Exchange), task.Symbol, err)
+				failed = append(failed, task.Symbol)
+				lastErr = err
+			}
+		}
+		err := stopped
+		if err == nil && lastErr != nil {
+			err = fmt.Errorf("Trades import failed for %s: %v", strings.Join(failed, ", "), lastErr)
+		}
+		ch <- schemas.UserTradesChannel{
+			DataType: schemas.DataTypeDone,
+			Error:    err,
+		}
+		close(ch)
+	}()
+	return ch
+}
+
+// Fail - channel with final status only, i.e. when import can't be started
+func Fail(err error) chan schemas.UserTradesChannel {
+	ch := make(chan schemas.UserTradesChannel, 1)
+	ch <- schemas.UserTradesChannel{
+		DataType: schemas.DataTypeDone,
+		Error:    err,
+	}
+	close(ch)
+	return ch
+}
+
+func (im Importer) run(task Task, ch chan schemas.UserTradesChannel) (err error) {
+	key := Key(im.Exchange, im.APIKey, task.Symbol)
+	cursor := task.Start
+	if im.Checkpoints != nil {
+		c, ok, err := im.Checkpoints.Get(key)
+		if err != nil {
+			return err
+		}
+		if ok {
+			cursor = c
+		}
+	}
+	for {
+		trades, next, done, err := im.page(task, cursor)
+		if err != nil {
+			return err
+		}
+		if len(trades) > 0 {
+			if err = im.send(trades, ch); err != nil {
+				return err
+			}
+		}
+		// trades are sent before checkpoint is saved, page can be sent twice after crash
+		if im.Checkpoints != nil {
+			if err = im.Checkpoints.Set(key, next); err != nil {
+				return err
+			}
+		}
+		if done {
+			return nil
+		}
+		cursor = next
+		time.Sleep(im.Delay)
+	}
+}
+
+// send - sending page, with Acknowledge it returns when page is acknowledged
+func (im Importer) send(trades []schemas.Trade, ch chan schemas.UserTradesChannel) error {
+	msg := schemas.UserTradesChannel{
+		DataType: schemas.DataTypeUpdate,
+		Data:     trades,
+	}
+	if !im.Acknowledge {
+		ch <- msg
+		return nil
+	}
+	ack := make(chan error, 1)
+	msg.Ack = func(err error) {
+		select {
+		case ack <- err:
+		default:
+		}
+	}
+	ch <- msg
+	if err := <-ack; err != nil {
+		return cancelled{err}
+	}
+	return nil
+}
+
+// page - loading page with retries
+func (im Importer) page(task Task, cursor schemas.Checkpoint) (trades []schemas.Trade, next schemas.Checkpoint, done bool, err error) {
+	n := im.Attempts
+	if n <= 0 {
+		n = attempts
+	}
+	wait := im.Backoff
+	if wait <= 0 {
+		wait = backoff
+	}
+	max := im.MaxBackoff
+	if max <= 0 {
+		max = maxBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		if trades, next, done, err = task.Page(cursor); err == nil || attempt >= n {
+			return
+		}
+		log.Printf("[%s] Error loading trades of %s, attempt %d of %d: %v\n", strings.ToUpper(im.Exchange), task.Symbol, attempt, n, err)
+		time.Sleep(wait)
+		if wait *= 2; wait > max {
+			wait = max
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/syndicatedb/goex/checkpoint"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// pages - task of 3 pages, cursor is page number
+func pages(symbol string) Task {
+	return Task{
+		Symbol: symbol,
+		Start:  schemas.Checkpoint{Cursor: "0"},
+		Page: func(cursor schemas.Checkpoint) (trades []schemas.Trade, next schemas.Checkpoint, done bool, err error) {
+			n, _ := strconv.Atoi(cursor.Cursor)
+			trades = []schemas.Trade{{ID: symbol + cursor.Cursor, Symbol: symbol}}
+			next = schemas.Checkpoint{Cursor: strconv.Itoa(n + 1)}
+			return trades, next, n+1 == 3, nil
+		},
+	}
+}
+
+func TestRunAcknowledge(t *testing.T) {
+	failed := errors.New("disk is full")
+	tests := []struct {
+		name    string
+		acks    []error // by page
+		pages   int     // received pages
+		cursors map[string]string
+		err     bool
+	}{
+		{
+			name:    "all acknowledged",
+			acks:    []error{nil, nil, nil, nil, nil, nil},
+			pages:   6,
+			cursors: map[string]string{"A": "3", "B": "3"},
+		},
+		{
+			name:    "cancelled on second page",
+			acks:    []error{nil, failed},
+			pages:   2,
+			cursors: map[string]string{"A": "1"},
+			err:     true,
+		},
+	}
+	for _, tt := range tests {
+		checkpoints := checkpoint.NewMemory()
+		im := Importer{
+			Exchange:    "test",
+			APIKey:      "key",
+			Checkpoints: checkpoints,
+			Acknowledge: true,
+		}
+		var received int
+		var err error
+		for msg := range im.Run([]Task{pages("A"), pages("B")}) {
+			if msg.DataType == schemas.DataTypeDone {
+				if msg.Ack != nil {
+					t.Errorf("%s: final status has Ack", tt.name)
+				}
+				err = msg.Error
+				continue
+			}
+			if received >= len(tt.acks) {
+				t.Fatalf("%s: page %d is sent after cancel", tt.name, received+1)
+			}
+			// checkpoint of page isn't saved before it's acknowledged
+			c, _, _ := checkpoints.Get(Key("test", "key", msg.Data[0].Symbol))
+			if c.Cursor == strconv.Itoa(received%3+1) {
+				t.Errorf("%s: checkpoint of page %d is saved before Ack", tt.name, received+1)
+			}
+			msg.Ack(tt.acks[received])
+			received++
+		}
+		if received != tt.pages {
+			t.Errorf("%s: %d pages received, expected %d", tt.name, received, tt.pages)
+		}
+		if (err != nil) != tt.err {
+			t.Errorf("%s: final error %v", tt.name, err)
+		}
+		for _, symbol := range []string{"A", "B"} {
+			c, ok, _ := checkpoints.Get(Key("test", "key", symbol))
+			if expected, saved := tt.cursors[symbol]; saved != ok || c.Cursor != expected {
+				t.Errorf("%s: checkpoint of %s %v, expected %q", tt.name, symbol, c, expected)
+			}
+		}
+	}
+}
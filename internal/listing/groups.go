@@ -0,0 +1,209 @@
+package listing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// Group - running group of symbols, it's stopped when its symbols are delisted
+type Group interface {
+	Stop()
+}
+
+// Resizable - group changing its symbols on running connection.
+// It's stopped only when all of its symbols are removed
+type Resizable interface {
+	AddSymbols(symbols []schemas.Symbol)
+	RemoveSymbols(symbols []schemas.Symbol)
+}
+
+// Starter - creating group of symbols and starting it, group data is sent to ch
+type Starter func(symbols []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) Group
+
+// running - started group with its symbols
+type running struct {
+	symbols []schemas.Symbol
+	group   Group
+}
+
+/*
+Groups - provider symbols split into groups of limited size.
+Groups are started by Subscribe, after that listings are subscribed by new groups
+or by resizable groups having room, and groups owning delisted symbols are stopped
+and started again with the rest of their symbols
+*/
+type Groups struct {
+	limit int
+	start Starter
+
+	symbols    []schemas.Symbol
+	groups     []*running
+	subscribed *Set
+	resultCh   chan schemas.ResultChannel
+	interval   time.Duration
+
+	sync.Mutex
+}
+
+// NewGroups - Groups constructor, limit is max count of group symbols
+func NewGroups(limit int, start Starter) *Groups {
+	return &Groups{
+		limit:      limit,
+		start:      start,
+		subscribed: NewSet(),
+	}
+}
+
+// Reset - replacing symbols, running subscription is restarted with new ones
+func (g *Groups) Reset(symbols []schemas.Symbol) {
+	g.Lock()
+	defer g.Unlock()
+	g.subscribed.Reset(symbols)
+	g.symbols = append([]schemas.Symbol(nil), symbols...)
+	if g.resultCh == nil {
+		return
+	}
+	g.stopAll()
+	g.startGroups(g.symbols)
+}
+
+// Symbols - current symbols
+func (g *Groups) Symbols() []schemas.Symbol {
+	g.Lock()
+	defer g.Unlock()
+	return append([]schemas.Symbol(nil), g.symbols...)
+}
+
+// Subscribe - starting groups, returns channel of their data without data of removed symbols
+func (g *Groups) Subscribe(d time.Duration) chan schemas.ResultChannel {
+	g.Lock()
+	defer g.Unlock()
+	g.stopAll()
+	bufLength := 2 * len(g.symbols)
+	ch := make(chan schemas.ResultChannel, bufLength)
+	out := make(chan schemas.ResultChannel, bufLength)
+	go g.subscribed.Forward(ch, out)
+	g.resultCh = ch
+	g.interval = d
+
+	for _, chunk := range chunks(g.symbols, g.limit) {
+		g.groups = append(g.groups, &running{
+			symbols: chunk,
+			group:   g.start(chunk, ch, d),
+		})
+		time.Sleep(100 * time.Millisecond)
+	}
+	return out
+}
+
+// Add - adding symbols to running subscription, i.e. new listings
+func (g *Groups) Add(symbols []schemas.Symbol) {
+	g.Lock()
+	defer g.Unlock()
+	fresh := g.subscribed.Add(symbols)
+	if len(fresh) == 0 {
+		return
+	}
+	g.symbols = append(g.symbols, fresh...)
+	if g.resultCh == nil {
+		return
+	}
+	for _, r := range g.groups {
+		if len(fresh) == 0 {
+			return
+		}
+		group, ok := r.group.(Resizable)
+		room := g.limit - len(r.symbols)
+		if !ok || room <= 0 {
+			continue
+		}
+		if room > len(fresh) {
+			room = len(fresh)
+		}
+		group.AddSymbols(fresh[:room])
+		r.symbols = append(r.symbols, fresh[:room]...)
+		fresh = fresh[room:]
+	}
+	g.startGroups(fresh)
+}
+
+// Remove - removing symbols from subscription, i.e. delistings.
+// Groups owning them are stopped, resizable ones unsubscribe them
+func (g *Groups) Remove(symbols []schemas.Symbol) {
+	g.Lock()
+	defer g.Unlock()
+	g.subscribed.Remove(symbols)
+	g.symbols, _ = remove(g.symbols, symbols)
+	if g.resultCh == nil {
+		return
+	}
+
+	var rest []schemas.Symbol
+	groups := g.groups[:0]
+	for _, r := range g.groups {
+		left, removed := remove(r.symbols, symbols)
+		if len(removed) == 0 {
+			groups = append(groups, r)
+			continue
+		}
+		if group, ok := r.group.(Resizable); ok && len(left) > 0 {
+			group.RemoveSymbols(removed)
+			r.symbols = left
+			groups = append(groups, r)
+			continue
+		}
+		r.group.Stop()
+		rest = append(rest, left...)
+	}
+	g.groups = groups
+	g.startGroups(rest)
+}
+
+// startGroups - starting new groups of symbols, has to be called under lock
+func (g *Groups) startGroups(symbols []schemas.Symbol) {
+	for _, chunk := range chunks(symbols, g.limit) {
+		g.groups = append(g.groups, &running{
+			symbols: chunk,
+			group:   g.start(chunk, g.resultCh, g.interval),
+		})
+	}
+}
+
+// stopAll - stopping running groups, has to be called under lock
+func (g *Groups) stopAll() {
+	for _, r := range g.groups {
+		r.group.Stop()
+	}
+	g.groups = nil
+}
+
+// chunks - splitting symbols into slices of limit size
+func chunks(symbols []schemas.Symbol, limit int) (result [][]schemas.Symbol) {
+	for len(symbols) > 0 {
+		size := limit
+		if size > len(symbols) {
+			size = len(symbols)
+		}
+		result = append(result, append([]schemas.Symbol(nil), symbols[:size]...))
+		symbols = symbols[size:]
+	}
+	return
+}
+
+// remove - splitting symbols into left ones and removed ones, matched by name
+func remove(symbols, removing []schemas.Symbol) (left, removed []schemas.Symbol) {
+	names := make(map[string]bool)
+	for _, s := range removing {
+		names[s.Name] = true
+	}
+	for _, s := range symbols {
+		if names[s.Name] {
+			removed = append(removed, s)
+			continue
+		}
+		left = append(left, s)
+	}
+	return
+}
@@ -0,0 +1,189 @@
+package listing
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+type fakeGroup struct {
+	symbols []string
+	stopped bool
+}
+
+func (g *fakeGroup) Stop() {
+	g.stopped = true
+}
+
+type fakeResizable struct {
+	fakeGroup
+}
+
+func (g *fakeResizable) AddSymbols(symbols []schemas.Symbol) {
+	g.symbols = append(g.symbols, names(symbols)...)
+}
+
+func (g *fakeResizable) RemoveSymbols(symbols []schemas.Symbol) {
+	removing := strings.Join(names(symbols), ",")
+	var left []string
+	for _, s := range g.symbols {
+		if !strings.Contains(removing, s) {
+			left = append(left, s)
+		}
+	}
+	g.symbols = left
+}
+
+func names(symbols []schemas.Symbol) (res []string) {
+	for _, s := range symbols {
+		res = append(res, s.Name)
+	}
+	return
+}
+
+func symbols(list ...string) (res []schemas.Symbol) {
+	for _, name := range list {
+		res = append(res, schemas.Symbol{Name: name})
+	}
+	return
+}
+
+// runningSymbols - symbols of running groups, sorted
+func runningSymbols(started []*fakeGroup) (res []string) {
+	for _, g := range started {
+		if !g.stopped {
+			res = append(res, strings.Join(g.symbols, ","))
+		}
+	}
+	sort.Strings(res)
+	return
+}
+
+func TestGroups(t *testing.T) {
+	tests := []struct {
+		name      string
+		resizable bool
+		steps     func(g *Groups)
+		running   []string
+		stopped   int
+	}{
+		{
+			name: "subscribe splits symbols by limit",
+			steps: func(g *Groups) {
+				g.Reset(symbols("A", "B", "C"))
+				g.Subscribe(time.Second)
+			},
+			running: []string{"A,B", "C"},
+		},
+		{
+			name: "added symbols start new group",
+			steps: func(g *Groups) {
+				g.Reset(symbols("A", "B", "C"))
+				g.Subscribe(time.Second)
+				g.Add(symbols("C", "D"))
+			},
+			running: []string{"A,B", "C", "D"},
+		},
+		{
+			name: "group of removed symbol is restarted with the rest",
+			steps: func(g *Groups) {
+				g.Reset(symbols("A", "B", "C"))
+				g.Subscribe(time.Second)
+				g.Remove(symbols("A"))
+			},
+			running: []string{"B", "C"},
+			stopped: 1,
+		},
+		{
+			name: "group without symbols left is stopped",
+			steps: func(g *Groups) {
+				g.Reset(symbols("A", "B", "C"))
+				g.Subscribe(time.Second)
+				g.Remove(symbols("C"))
+			},
+			running: []string{"A,B"},
+			stopped: 1,
+		},
+		{
+			name: "symbols removed before subscription are not started",
+			steps: func(g *Groups) {
+				g.Reset(symbols("A", "B", "C"))
+				g.Remove(symbols("B"))
+				g.Subscribe(time.Second)
+			},
+			running: []string{"A,C"},
+		},
+		{
+			name:      "resizable group unsubscribes removed symbol",
+			resizable: true,
+			steps: func(g *Groups) {
+				g.Reset(symbols("A", "B", "C"))
+				g.Subscribe(time.Second)
+				g.Remove(symbols("A"))
+			},
+			running: []string{"B", "C"},
+		},
+		{
+			name:      "resizable group with room gets added symbols",
+			resizable: true,
+			steps: func(g *Groups) {
+				g.Reset(symbols("A", "B", "C"))
+				g.Subscribe(time.Second)
+				g.Add(symbols("D", "E"))
+			},
+			running: []string{"A,B", "C,D", "E"},
+		},
+		{
+			name:      "resizable group without symbols left is stopped",
+			resizable: true,
+			steps: func(g *Groups) {
+				g.Reset(symbols("A", "B", "C"))
+				g.Subscribe(time.Second)
+				g.Remove(symbols("A", "B"))
+			},
+			running: []string{"C"},
+			stopped: 1,
+		},
+		{
+			name: "reset restarts running subscription",
+			steps: func(g *Groups) {
+				g.Reset(symbols("A", "B", "C"))
+				g.Subscribe(time.Second)
+				g.Reset(symbols("D"))
+			},
+			running: []string{"D"},
+			stopped: 2,
+		},
+	}
+	for _, tt := range tests {
+		var started []*fakeGroup
+		g := NewGroups(2, func(s []schemas.Symbol, ch chan schemas.ResultChannel, d time.Duration) Group {
+			if tt.resizable {
+				group := &fakeResizable{fakeGroup{symbols: names(s)}}
+				started = append(started, &group.fakeGroup)
+				return group
+			}
+			group := &fakeGroup{symbols: names(s)}
+			started = append(started, group)
+			return group
+		})
+		tt.steps(g)
+
+		if running := runningSymbols(started); !reflect.DeepEqual(running, tt.running) {
+			t.Errorf("%s: running = %v, want %v", tt.name, running, tt.running)
+		}
+		stopped := 0
+		for _, group := range started {
+			if group.stopped {
+				stopped++
+			}
+		}
+		if stopped != tt.stopped {
+			t.Errorf("%s: stopped = %d, want %d", tt.name, stopped, tt.stopped)
+		}
+	}
+}
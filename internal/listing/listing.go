@@ -0,0 +1,148 @@
+package listing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// Watch - loading symbols with interval and sending changes between loads.
+// First load is sent as snapshot: every symbol is listed
+func Watch(get func() ([]schemas.Symbol, error), d time.Duration) chan schemas.SymbolEventsChannel {
+	ch := make(chan schemas.SymbolEventsChannel)
+
+	go func() {
+		var prev []schemas.Symbol
+		dataType := schemas.DataTypeSnapshot
+		for {
+			symbols, err := get()
+			if err != nil {
+				ch <- schemas.SymbolEventsChannel{
+					Error: err,
+				}
+				time.Sleep(d)
+				continue
+			}
+			// empty response is an API failure, not delisting of everything
+			if len(symbols) > 0 {
+				if events := schemas.DiffSymbols(prev, symbols); len(events) > 0 {
+					ch <- schemas.SymbolEventsChannel{
+						Data:     events,
+						DataType: dataType,
+					}
+				}
+				prev = symbols
+				dataType = schemas.DataTypeUpdate
+			}
+			time.Sleep(d)
+		}
+	}()
+	return ch
+}
+
+// Set - symbols subscribed by provider.
+// Data of removed symbols, i.e. received before their groups are stopped, is skipped by Forward
+type Set struct {
+	known   map[string]bool
+	removed map[string]bool
+	sync.Mutex
+}
+
+// NewSet - Set constructor
+func NewSet() *Set {
+	return &Set{
+		known:   make(map[string]bool),
+		removed: make(map[string]bool),
+	}
+}
+
+// Add - adding symbols, returns symbols which are not subscribed yet
+func (s *Set) Add(symbols []schemas.Symbol) (fresh []schemas.Symbol) {
+	s.Lock()
+	defer s.Unlock()
+	for _, smb := range symbols {
+		delete(s.removed, smb.Name)
+		delete(s.removed, smb.OriginalName)
+		if s.known[smb.Name] {
+			continue
+		}
+		s.known[smb.Name] = true
+		fresh = append(fresh, smb)
+	}
+	return
+}
+
+// Reset - replacing subscribed symbols
+func (s *Set) Reset(symbols []schemas.Symbol) {
+	s.Lock()
+	defer s.Unlock()
+	s.known = make(map[string]bool)
+	s.removed = make(map[string]bool)
+	for _, smb := range symbols {
+		s.known[smb.Name] = true
+	}
+}
+
+// Remove - removing symbols from subscription
+func (s *Set) Remove(symbols []schemas.Symbol) {
+	s.Lock()
+	defer s.Unlock()
+	for _, smb := range symbols {
+		s.removed[smb.Name] = true
+		if smb.OriginalName != "" {
+			s.removed[smb.OriginalName] = true
+		}
+	}
+}
+
+// Forward - forwarding results into out channel, skipping data of removed symbols
+func (s *Set) Forward(in, out chan schemas.ResultChannel) {
+	for r := range in {
+		if r.Data = s.filter(r.Data); r.Data != nil || r.Error != nil {
+			out <- r
+		}
+	}
+}
+
+// filter - returns nil if data belongs to removed symbols only
+func (s *Set) filter(data interface{}) interface{} {
+	s.Lock()
+	defer s.Unlock()
+	if len(s.removed) == 0 {
+		return data
+	}
+	switch d := data.(type) {
+	case schemas.OrderBook:
+		if s.removed[d.Symbol] {
+			return nil
+		}
+	case schemas.Quote:
+		if s.removed[d.Symbol] {
+			return nil
+		}
+	case []schemas.Quote:
+		var quotes []schemas.Quote
+		for _, q := range d {
+			if !s.removed[q.Symbol] {
+				quotes = append(quotes, q)
+			}
+		}
+		if len(quotes) == 0 && len(d) > 0 {
+			return nil
+		}
+		return quotes
+	case []schemas.Trade:
+		var trades []schemas.Trade
+		for _, t := range d {
+			if !s.removed[t.Symbol] {
+				trades = append(trades, t)
+			}
+		}
+		if len(trades) == 0 && len(d) > 0 {
+			return nil
+		}
+		return trades
+	}
+	return data
+}
@@ -0,0 +1,87 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thrift - Thrift compact protocol encoder of parquet metadata structs
+type thrift struct {
+	buf  bytes.Buffer
+	last []int16 // last field ID of every open struct
+}
+
+func (t *thrift) structBegin() {
+	t.last = append(t.last, 0)
+}
+
+func (t *thrift) structEnd() {
+	t.buf.WriteByte(0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+func (t *thrift) field(id int16, typ byte) {
+	last := &t.last[len(t.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(uint64(zigzag(int64(id))))
+	}
+	*last = id
+}
+
+func (t *thrift) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(uint64(zigzag(int64(v))))
+}
+
+func (t *thrift) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(uint64(zigzag(v)))
+}
+
+func (t *thrift) str(id int16, v string) {
+	t.field(id, thriftBinary)
+	t.binary(v)
+}
+
+func (t *thrift) binary(v string) {
+	t.varint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
+
+func (t *thrift) list(id int16, elemType byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	t.buf.WriteByte(0xF0 | elemType)
+	t.varint(uint64(size))
+}
+
+// structField - nested struct field, it has to be ended with structEnd
+func (t *thrift) structField(id int16) {
+	t.field(id, thriftStruct)
+	t.structBegin()
+}
+
+func (t *thrift) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	t.buf.Write(b[:n])
+}
+
+func zigzag(v int64) int64 {
+	return (v << 1) ^ (v >> 63)
+}
@@ -0,0 +1,232 @@
+/*
+Package parquet writes flat tables to Parquet files:
+required columns, plain encoding without compression, one row group.
+Rows are kept in memory until Close
+*/
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Column types
+const (
+	String = iota
+	Double
+	Int64
+)
+
+// Parquet enums
+const (
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	repetitionRequired = 0
+	convertedUTF8      = 0
+	encodingPlain      = 0
+	encodingRLE        = 3
+	pageData           = 0
+	codecUncompressed  = 0
+)
+
+const magic = "PAR1"
+
+// Column - column name and type
+type Column struct {
+	Name string
+	Type int
+}
+
+// Writer - buffering rows and writing them as parquet file on Close
+type Writer struct {
+	w       io.Writer
+	columns []Column
+	values  [][]byte // plain encoded values by column
+	rows    int64
+	closed  bool
+}
+
+// NewWriter - Writer constructor
+func NewWriter(w io.Writer, columns []Column) *Writer {
+	return &Writer{
+		w:       w,
+		columns: columns,
+		values:  make([][]byte, len(columns)),
+	}
+}
+
+// Write - adding row, values have to be string, float64 or int64 by column types
+func (w *Writer) Write(row []interface{}) error {
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("Row has %d values, %d columns expected", len(row), len(w.columns))
+	}
+	for i, c := range w.columns {
+		b, err := encode(c, row[i])
+		if err != nil {
+			return err
+		}
+		w.values[i] = append(w.values[i], b...)
+	}
+	w.rows++
+	return nil
+}
+
+func encode(c Column, v interface{}) ([]byte, error) {
+	switch c.Type {
+	case String:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("Column %s: string expected, got %T", c.Name, v)
+		}
+		b := make([]byte, 4+len(s))
+		binary.LittleEndian.PutUint32(b, uint32(len(s)))
+		copy(b[4:], s)
+		return b, nil
+	case Double:
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("Column %s: float64 expected, got %T", c.Name, v)
+		}
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, math.Float64bits(f))
+		return b, nil
+	case Int64:
+		n, ok := v.(int64)
+		if !ok {
+			return nil, fmt.Errorf("Column %s: int64 expected, got %T", c.Name, v)
+		}
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(n))
+		return b, nil
+	}
+	return nil, fmt.Errorf("Column %s: unknown type %d", c.Name, c.Type)
+}
+
+// chunk - written column chunk position
+type chunk struct {
+	offset int64
+	size   int64
+}
+
+// Close - writing file: column chunks of one data page each and metadata footer
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	offset := int64(len(magic))
+	if _, err := io.WriteString(w.w, magic); err != nil {
+		return err
+	}
+	chunks := make([]chunk, len(w.columns))
+	for i := range w.columns {
+		header := w.pageHeader(len(w.values[i]))
+		if _, err := w.w.Write(header); err != nil {
+			return err
+		}
+		if _, err := w.w.Write(w.values[i]); err != nil {
+			return err
+		}
+		chunks[i] = chunk{
+			offset: offset,
+			size:   int64(len(header) + len(w.values[i])),
+		}
+		offset += chunks[i].size
+	}
+	footer := w.fileMetaData(chunks)
+	if _, err := w.w.Write(footer); err != nil {
+		return err
+	}
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(footer)))
+	if _, err := w.w.Write(size[:]); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.w, magic)
+	return err
+}
+
+func (w *Writer) pageHeader(size int) []byte {
+	var t thrift
+	t.structBegin()
+	t.i32(1, pageData)
+	t.i32(2, int32(size))
+	t.i32(3, int32(size))
+	t.structField(5)
+	t.i32(1, int32(w.rows))
+	t.i32(2, encodingPlain)
+	t.i32(3, encodingRLE)
+	t.i32(4, encodingRLE)
+	t.structEnd()
+	t.structEnd()
+	return t.buf.Bytes()
+}
+
+func (w *Writer) fileMetaData(chunks []chunk) []byte {
+	var t thrift
+	var total int64
+	for _, c := range chunks {
+		total += c.size
+	}
+	t.structBegin()
+	t.i32(1, 1)
+
+	t.list(2, thriftStruct, len(w.columns)+1)
+	t.structBegin()
+	t.str(4, "schema")
+	t.i32(5, int32(len(w.columns)))
+	t.structEnd()
+	for _, c := range w.columns {
+		t.structBegin()
+		t.i32(1, physicalType(c.Type))
+		t.i32(3, repetitionRequired)
+		t.str(4, c.Name)
+		if c.Type == String {
+			t.i32(6, convertedUTF8)
+		}
+		t.structEnd()
+	}
+	t.i64(3, w.rows)
+
+	t.list(4, thriftStruct, 1)
+	t.structBegin()
+	t.list(1, thriftStruct, len(w.columns))
+	for i, c := range w.columns {
+		t.structBegin()
+		t.i64(2, chunks[i].offset)
+		t.structField(3)
+		t.i32(1, physicalType(c.Type))
+		t.list(2, thriftI32, 1)
+		t.varint(uint64(zigzag(encodingPlain)))
+		t.list(3, thriftBinary, 1)
+		t.binary(c.Name)
+		t.i32(4, codecUncompressed)
+		t.i64(5, w.rows)
+		t.i64(6, chunks[i].size)
+		t.i64(7, chunks[i].size)
+		t.i64(9, chunks[i].offset)
+		t.structEnd()
+		t.structEnd()
+	}
+	t.i64(2, total)
+	t.i64(3, w.rows)
+	t.structEnd()
+
+	t.str(6, "goex")
+	t.structEnd()
+	return t.buf.Bytes()
+}
+
+func physicalType(columnType int) int32 {
+	switch columnType {
+	case Double:
+		return typeDouble
+	case Int64:
+		return typeInt64
+	}
+	return typeByteArray
+}
@@ -0,0 +1,245 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+/*
+decoder - Thrift compact protocol decoder independent from encoder:
+structs are read as maps of field ID to value, i32 and i64 as int64, binary as string
+*/
+type decoder struct {
+	b   []byte
+	pos int
+	err error
+}
+
+func (d *decoder) byte() byte {
+	if d.pos >= len(d.b) {
+		d.err = fmt.Errorf("Unexpected end at %d", d.pos)
+		return 0
+	}
+	d.pos++
+	return d.b[d.pos-1]
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.b[d.pos:])
+	if n <= 0 {
+		d.err = fmt.Errorf("Broken varint at %d", d.pos)
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+func (d *decoder) zigzag() int64 {
+	v := d.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (d *decoder) value(typ byte) interface{} {
+	switch typ {
+	case thriftI32, thriftI64:
+		return d.zigzag()
+	case thriftBinary:
+		n := int(d.uvarint())
+		if d.pos+n > len(d.b) {
+			d.err = fmt.Errorf("Binary of %d bytes at %d is out of data", n, d.pos)
+			return ""
+		}
+		d.pos += n
+		return string(d.b[d.pos-n : d.pos])
+	case thriftList:
+		h := d.byte()
+		size := int(h >> 4)
+		if size == 15 {
+			size = int(d.uvarint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = d.value(h & 0x0F)
+		}
+		return list
+	case thriftStruct:
+		return d.structure()
+	}
+	d.err = fmt.Errorf("Unexpected type %d at %d", typ, d.pos)
+	return nil
+}
+
+func (d *decoder) structure() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var last int16
+	for d.err == nil {
+		h := d.byte()
+		if h == 0 {
+			break
+		}
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			id = int16(d.zigzag())
+		}
+		fields[id] = d.value(h & 0x0F)
+		last = id
+	}
+	return fields
+}
+
+// read - reading file by parquet format spec, values of columns are returned in order
+func read(file []byte) (schema []Column, rows int64, values [][]interface{}, err error) {
+	if len(file) < 12 || string(file[:4]) != magic || string(file[len(file)-4:]) != magic {
+		return nil, 0, nil, fmt.Errorf("No magic")
+	}
+	size := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := &decoder{b: file[len(file)-8-size : len(file)-8]}
+	meta := footer.structure()
+	if footer.err != nil {
+		return nil, 0, nil, footer.err
+	}
+	if footer.pos != size {
+		return nil, 0, nil, fmt.Errorf("Footer is %d bytes, %d read", size, footer.pos)
+	}
+	rows = meta[3].(int64)
+	elements := meta[2].([]interface{})
+	root := elements[0].(map[int16]interface{})
+	if root[5].(int64) != int64(len(elements)-1) {
+		return nil, 0, nil, fmt.Errorf("Root has %d children, %d elements", root[5], len(elements)-1)
+	}
+	for _, e := range elements[1:] {
+		element := e.(map[int16]interface{})
+		if element[3].(int64) != repetitionRequired {
+			return nil, 0, nil, fmt.Errorf("Column %s isn't required", element[4])
+		}
+		c := Column{Name: element[4].(string)}
+		switch element[1].(int64) {
+		case typeByteArray:
+			c.Type = String
+		case typeDouble:
+			c.Type = Double
+		case typeInt64:
+			c.Type = Int64
+		}
+		schema = append(schema, c)
+	}
+	groups := meta[4].([]interface{})
+	if len(groups) != 1 {
+		return nil, 0, nil, fmt.Errorf("%d row groups", len(groups))
+	}
+	group := groups[0].(map[int16]interface{})
+	if group[3].(int64) != rows {
+		return nil, 0, nil, fmt.Errorf("Row group has %d rows, file has %d", group[3], rows)
+	}
+	for i, ch := range group[1].([]interface{}) {
+		chunk := ch.(map[int16]interface{})[3].(map[int16]interface{})
+		if path := chunk[3].([]interface{}); len(path) != 1 || path[0] != schema[i].Name {
+			return nil, 0, nil, fmt.Errorf("Chunk %d path %v", i, path)
+		}
+		if chunk[4].(int64) != codecUncompressed || chunk[5].(int64) != rows {
+			return nil, 0, nil, fmt.Errorf("Chunk %d codec %d, %d values", i, chunk[4], chunk[5])
+		}
+		offset := int(chunk[9].(int64))
+		page := &decoder{b: file, pos: offset}
+		header := page.structure()
+		if page.err != nil {
+			return nil, 0, nil, page.err
+		}
+		if header[1].(int64) != pageData {
+			return nil, 0, nil, fmt.Errorf("Chunk %d page type %d", i, header[1])
+		}
+		if int64(page.pos-offset)+header[3].(int64) != chunk[7].(int64) {
+			return nil, 0, nil, fmt.Errorf("Chunk %d size %d doesn't match page", i, chunk[7])
+		}
+		data := header[5].(map[int16]interface{})
+		if data[1].(int64) != rows || data[2].(int64) != encodingPlain {
+			return nil, 0, nil, fmt.Errorf("Chunk %d page has %d values of encoding %d", i, data[1], data[2])
+		}
+		b := file[page.pos : page.pos+int(header[3].(int64))]
+		var column []interface{}
+		for n := int64(0); n < rows; n++ {
+			switch schema[i].Type {
+			case String:
+				size := int(binary.LittleEndian.Uint32(b))
+				column = append(column, string(b[4:4+size]))
+				b = b[4+size:]
+			case Double:
+				column = append(column, math.Float64frombits(binary.LittleEndian.Uint64(b)))
+				b = b[8:]
+			case Int64:
+				column = append(column, int64(binary.LittleEndian.Uint64(b)))
+				b = b[8:]
+			}
+		}
+		if len(b) != 0 {
+			return nil, 0, nil, fmt.Errorf("Chunk %d has %d bytes after values", i, len(b))
+		}
+		values = append(values, column)
+	}
+	return
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	columns := []Column{
+		{Name: "symbol", Type: String},
+		{Name: "price", Type: Double},
+		{Name: "time", Type: Int64},
+	}
+	rows := [][]interface{}{
+		{"ETH-BTC", 0.0312, int64(1546300800000)},
+		{"", -1.5, int64(-1)},
+		{"ЁЖ-USDT", math.MaxFloat64, int64(math.MaxInt64)},
+	}
+	tests := []struct {
+		name string
+		rows [][]interface{}
+	}{
+		{"rows", rows},
+		{"no rows", nil},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, columns)
+		for _, r := range tt.rows {
+			if err := w.Write(r); err != nil {
+				t.Fatalf("%s: write error: %v", tt.name, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: close error: %v", tt.name, err)
+		}
+		schema, count, values, err := read(buf.Bytes())
+		if err != nil {
+			t.Fatalf("%s: read error: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(schema, columns) {
+			t.Errorf("%s: schema %v, expected %v", tt.name, schema, columns)
+		}
+		if count != int64(len(tt.rows)) {
+			t.Errorf("%s: %d rows, expected %d", tt.name, count, len(tt.rows))
+		}
+		for i := range columns {
+			var expected []interface{}
+			for _, r := range tt.rows {
+				expected = append(expected, r[i])
+			}
+			if !reflect.DeepEqual(values[i], expected) {
+				t.Errorf("%s: column %s values %v, expected %v", tt.name, columns[i].Name, values[i], expected)
+			}
+		}
+	}
+}
+
+func TestWriterTypes(t *testing.T) {
+	w := NewWriter(&bytes.Buffer{}, []Column{{Name: "price", Type: Double}})
+	if err := w.Write([]interface{}{"1.5"}); err == nil {
+		t.Error("string is written to double column")
+	}
+	if err := w.Write([]interface{}{1.5, 2.5}); err == nil {
+		t.Error("row of 2 values is written to 1 column")
+	}
+}
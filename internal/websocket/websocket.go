@@ -77,6 +77,14 @@ func (c *Client) ChangeKeepAlive(f bool) *Client {
 	return c
 }
 
+/*
+SetKeepAliveTimeout - setting interval between ping messages
+*/
+func (c *Client) SetKeepAliveTimeout(d time.Duration) *Client {
+	c.keepaliveTimeout = d
+	return c
+}
+
 // Connect - connecting to Websocket server
 func (c *Client) Connect() (err error) {
 	log.Println(logConnecting)
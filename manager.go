@@ -0,0 +1,253 @@
+package goex
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+/*
+defaultAliases - exchange coin names differing from common ones by exchange.
+Symbols are compared by common names, i.e. Bitfinex DSH-BTC and Binance DASH-BTC are one market
+*/
+var defaultAliases = map[string]map[string]string{
+	Binance: {
+		"BCHABC": "BCH",
+		"BCHSV":  "BSV",
+	},
+	Bitfinex: {
+		"AIO": "AION",
+		"DAT": "DATA",
+		"DSH": "DASH",
+		"IOT": "IOTA",
+		"MNA": "MANA",
+		"QSH": "QASH",
+		"QTM": "QTUM",
+		"SNG": "SNGLS",
+		"SPK": "SPANK",
+		"STJ": "STORJ",
+		"UST": "USDT",
+		"YYW": "YOYOW",
+	},
+	Kucoin: {
+		"BCHSV": "BSV",
+	},
+	Poloniex: {
+		"STR": "XLM",
+	},
+}
+
+// ManagerConfig - exchanges of Manager
+type ManagerConfig struct {
+	Exchanges []schemas.Options
+	// exchange coin name to common one by exchange, i.e. {"bitfinex": {"DSH": "DASH"}}.
+	// Added to default aliases, replaces default alias of same coin
+	Aliases map[string]map[string]string
+}
+
+// ExchangesError - errors by exchange name, data of other exchanges is returned with it
+type ExchangesError map[string]error
+
+// Error - to implement error interface
+func (e ExchangesError) Error() string {
+	var names []string
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var msgs []string
+	for _, name := range names {
+		msgs = append(msgs, name+": "+e[name].Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+/*
+Manager - several exchanges working as one:
+symbols are compared by common names, market data is sent into one channel by type
+tagged with exchange name, balances and open orders are loaded from every exchange with credentials
+*/
+type Manager struct {
+	names     []string
+	exchanges map[string]API
+	trading   map[string]bool
+	aliases   map[string]map[string]string
+
+	symbols  map[string][]schemas.Symbol          // exchange symbols by exchange
+	common   map[string]map[string]string         // exchange symbol name (and original) to common name
+	byCommon map[string]map[string]schemas.Symbol // common name to exchange symbol
+
+	feeds     map[string]*feed // market data subscriptions by exchange and data kind
+	feedsLock sync.Mutex
+
+	sync.RWMutex
+}
+
+/*
+NewManager - creating exchanges in config order and loading their symbols.
+Returns error for unknown or repeated exchange names only:
+symbols load errors are logged, LoadSymbols can be called again
+*/
+func NewManager(config ManagerConfig) (*Manager, error) {
+	m := &Manager{
+		exchanges: make(map[string]API),
+		trading:   make(map[string]bool),
+		aliases:   make(map[string]map[string]string),
+		symbols:   make(map[string][]schemas.Symbol),
+		common:    make(map[string]map[string]string),
+		byCommon:  make(map[string]map[string]schemas.Symbol),
+		feeds:     make(map[string]*feed),
+	}
+	for _, aliases := range []map[string]map[string]string{defaultAliases, config.Aliases} {
+		for name, coins := range aliases {
+			if m.aliases[name] == nil {
+				m.aliases[name] = make(map[string]string)
+			}
+			for coin, common := range coins {
+				m.aliases[name][strings.ToUpper(coin)] = strings.ToUpper(common)
+			}
+		}
+	}
+	for _, opts := range config.Exchanges {
+		if _, ok := m.exchanges[opts.Name]; ok {
+			return nil, fmt.Errorf("Exchange %s is set twice", opts.Name)
+		}
+		api := New(opts)
+		if api == nil {
+			return nil, fmt.Errorf("Unknown exchange %s", opts.Name)
+		}
+		m.names = append(m.names, opts.Name)
+		m.exchanges[opts.Name] = api
+		m.trading[opts.Name] = opts.Credentials.APIKey != "" || opts.Paper != nil
+	}
+	if err := m.LoadSymbols(); err != nil {
+		log.Println("Error loading symbols:", err)
+	}
+	return m, nil
+}
+
+// Exchanges - exchange names in config order
+func (m *Manager) Exchanges() []string {
+	names := make([]string, len(m.names))
+	copy(names, m.names)
+	return names
+}
+
+// Exchange - exchange API by name, nil if manager hasn't such exchange
+func (m *Manager) Exchange(name string) API {
+	return m.exchanges[name]
+}
+
+// LoadSymbols - loading symbols of every exchange. Symbols of failed exchanges are kept
+func (m *Manager) LoadSymbols() error {
+	errs := make(ExchangesError)
+	for _, name := range m.names {
+		symbols, err := m.exchanges[name].SymbolProvider().Get()
+		if err != nil {
+			errs[name] = err
+			continue
+		}
+		m.setSymbols(name, symbols)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (m *Manager) setSymbols(exchange string, symbols []schemas.Symbol) {
+	common := make(map[string]string, 2*len(symbols))
+	byCommon := make(map[string]schemas.Symbol, len(symbols))
+	for _, s := range symbols {
+		name := m.Normalize(exchange, s.Name)
+		common[s.Name] = name
+		common[s.OriginalName] = name
+		byCommon[name] = s
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.symbols[exchange] = symbols
+	m.common[exchange] = common
+	m.byCommon[exchange] = byCommon
+}
+
+/*
+Normalize - common name of exchange symbol name (i.e. DSH-BTC of Bitfinex is DASH-BTC).
+Coins of names without "-" separator are not recognized, name is returned in upper case
+*/
+func (m *Manager) Normalize(exchange, name string) string {
+	coins := strings.Split(strings.ToUpper(name), "-")
+	for i, coin := range coins {
+		coins[i] = m.NormalizeCoin(exchange, coin)
+	}
+	return strings.Join(coins, "-")
+}
+
+// NormalizeCoin - common name of exchange coin
+func (m *Manager) NormalizeCoin(exchange, coin string) string {
+	coin = strings.ToUpper(coin)
+	if common, ok := m.aliases[exchange][coin]; ok {
+		return common
+	}
+	return coin
+}
+
+// Symbols - exchange symbols as they are, to be used with exchange providers
+func (m *Manager) Symbols(exchange string) []schemas.Symbol {
+	m.RLock()
+	defer m.RUnlock()
+	symbols := make([]schemas.Symbol, len(m.symbols[exchange]))
+	copy(symbols, m.symbols[exchange])
+	return symbols
+}
+
+// Symbol - exchange symbol by common name
+func (m *Manager) Symbol(exchange, name string) (symbol schemas.Symbol, ok bool) {
+	m.RLock()
+	defer m.RUnlock()
+	symbol, ok = m.byCommon[exchange][strings.ToUpper(name)]
+	return
+}
+
+// Markets - exchanges by common symbol name, exchanges are in config order
+func (m *Manager) Markets() map[string][]string {
+	m.RLock()
+	defer m.RUnlock()
+	markets := make(map[string][]string)
+	for _, exchange := range m.names {
+		for name := range m.byCommon[exchange] {
+			markets[name] = append(markets[name], exchange)
+		}
+	}
+	return markets
+}
+
+// CommonName - common name of symbol name or original name sent by exchange
+func (m *Manager) CommonName(exchange, symbol string) string {
+	m.RLock()
+	name, ok := m.common[exchange][symbol]
+	m.RUnlock()
+	if ok {
+		return name
+	}
+	return m.Normalize(exchange, symbol)
+}
+
+// resolve - exchange symbols by common names, all exchange symbols if names are empty.
+// Symbols exchange doesn't list are skipped
+func (m *Manager) resolve(exchange string, names []string) (symbols []schemas.Symbol) {
+	if len(names) == 0 {
+		return m.Symbols(exchange)
+	}
+	for _, name := range names {
+		if s, ok := m.Symbol(exchange, name); ok {
+			symbols = append(symbols, s)
+		}
+	}
+	return
+}
@@ -0,0 +1,315 @@
+package goex
+
+import (
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// managerBufferSize - buffer of fan-in channels by exchange
+const managerBufferSize = 100
+
+/*
+SubscribeQuotes - quotes of symbols (common names) from every exchange listing them, all symbols if empty.
+Quotes provider of exchange is subscribed once and shared by all subscriptions, see fanIn
+*/
+func (m *Manager) SubscribeQuotes(symbols []string, d time.Duration) chan schemas.ExchangeChannel {
+	return m.fanIn(symbols, feedSource{
+		kind: feedQuotes,
+		subscribe: func(api API, s []schemas.Symbol) chan schemas.ResultChannel {
+			return api.QuotesProvider().SetSymbols(s).SubscribeAll(d)
+		},
+		add: func(api API, s []schemas.Symbol) []chan schemas.ResultChannel {
+			api.QuotesProvider().AddSymbols(s)
+			return nil
+		},
+		snapshot: func(api API, s schemas.Symbol) (interface{}, error) {
+			return api.QuotesProvider().Get(s)
+		},
+	})
+}
+
+/*
+SubscribeTrades - public trades of symbols (common names) from every exchange listing them, all symbols if empty.
+Trades provider of exchange is subscribed once and shared by all subscriptions, see fanIn
+*/
+func (m *Manager) SubscribeTrades(symbols []string, d time.Duration) chan schemas.ExchangeChannel {
+	return m.fanIn(symbols, feedSource{
+		kind: feedTrades,
+		subscribe: func(api API, s []schemas.Symbol) chan schemas.ResultChannel {
+			return api.TradesProvider().SetSymbols(s).SubscribeAll(d)
+		},
+		add: func(api API, s []schemas.Symbol) []chan schemas.ResultChannel {
+			api.TradesProvider().AddSymbols(s)
+			return nil
+		},
+	})
+}
+
+/*
+SubscribeBooks - order books of symbols (common names) from every exchange listing them, all symbols if empty.
+Order books provider of exchange is subscribed once and shared by all subscriptions, see fanIn
+*/
+func (m *Manager) SubscribeBooks(symbols []string, d time.Duration) chan schemas.ExchangeChannel {
+	return m.fanIn(symbols, feedSource{
+		kind: feedBooks,
+		subscribe: func(api API, s []schemas.Symbol) chan schemas.ResultChannel {
+			return api.OrdersProvider().SetSymbols(s).SubscribeAll(d)
+		},
+		add: func(api API, s []schemas.Symbol) []chan schemas.ResultChannel {
+			api.OrdersProvider().AddSymbols(s)
+			return nil
+		},
+		snapshot: func(api API, s schemas.Symbol) (interface{}, error) {
+			return api.OrdersProvider().Get(s)
+		},
+	})
+}
+
+/*
+SubscribeCandles - candles of symbols (common names) from every exchange listing them, all symbols if empty.
+Candles provider of exchange is subscribed once and shared by all subscriptions, see fanIn
+*/
+func (m *Manager) SubscribeCandles(symbols []string, d time.Duration) chan schemas.ExchangeChannel {
+	return m.fanIn(symbols, feedSource{
+		kind: feedCandles,
+		subscribe: func(api API, s []schemas.Symbol) chan schemas.ResultChannel {
+			return api.CandlesProvider().SetSymbols(s).SubscribeAll(d)
+		},
+		add: func(api API, s []schemas.Symbol) (chans []chan schemas.ResultChannel) {
+			provider := api.CandlesProvider()
+			if subscriber, ok := provider.(schemas.SymbolsSubscriber); ok {
+				subscriber.AddSymbols(s)
+				return nil
+			}
+			// provider can't change running subscription, symbols are subscribed one by one
+			for _, symbol := range s {
+				chans = append(chans, provider.Subscribe(symbol, d))
+			}
+			return
+		},
+	})
+}
+
+/*
+fanIn - subscribing every exchange listing symbols and sending their data into one channel.
+Symbols of data are replaced with common names.
+Provider of exchange and data kind is subscribed by first subscription only, next ones
+add their missing symbols to it and get data of their symbols. Symbols subscribed before
+get snapshot loaded by REST, if data kind has snapshots (books and quotes)
+*/
+func (m *Manager) fanIn(names []string, source feedSource) chan schemas.ExchangeChannel {
+	out := make(chan schemas.ExchangeChannel, managerBufferSize*len(m.names))
+	for _, exchange := range m.names {
+		symbols := m.resolve(exchange, names)
+		if len(symbols) == 0 {
+			continue
+		}
+		c := &feedConsumer{out: out}
+		if len(names) > 0 {
+			c.symbols = make(map[string]bool, len(symbols))
+			for _, s := range symbols {
+				c.symbols[m.CommonName(exchange, s.Name)] = true
+			}
+		}
+		m.feed(exchange, source.kind).join(c, symbols, source)
+	}
+	return out
+}
+
+// normalizeData - copy of market data with common symbol names, unknown data types are returned as is
+func (m *Manager) normalizeData(exchange string, data interface{}) interface{} {
+	switch v := data.(type) {
+	case schemas.OrderBook:
+		return m.normalizeBook(exchange, v)
+	case []schemas.OrderBook:
+		books := make([]schemas.OrderBook, len(v))
+		for i, b := range v {
+			books[i] = m.normalizeBook(exchange, b)
+		}
+		return books
+	case schemas.Quote:
+		v.Symbol = m.CommonName(exchange, v.Symbol)
+		return v
+	case []schemas.Quote:
+		quotes := make([]schemas.Quote, len(v))
+		for i, q := range v {
+			q.Symbol = m.CommonName(exchange, q.Symbol)
+			quotes[i] = q
+		}
+		return quotes
+	case []schemas.Trade:
+		return m.normalizeTrades(exchange, v)
+	case [][]schemas.Trade:
+		trades := make([][]schemas.Trade, len(v))
+		for i, t := range v {
+			trades[i] = m.normalizeTrades(exchange, t)
+		}
+		return trades
+	case schemas.Candle:
+		v.Symbol = m.CommonName(exchange, v.Symbol)
+		return v
+	case []schemas.Candle:
+		return m.normalizeCandles(exchange, v)
+	case [][]schemas.Candle:
+		candles := make([][]schemas.Candle, len(v))
+		for i, c := range v {
+			candles[i] = m.normalizeCandles(exchange, c)
+		}
+		return candles
+	}
+	return data
+}
+
+func (m *Manager) normalizeBook(exchange string, book schemas.OrderBook) schemas.OrderBook {
+	book.Symbol = m.CommonName(exchange, book.Symbol)
+	book.Buy = m.normalizeOrders(exchange, book.Buy)
+	book.Sell = m.normalizeOrders(exchange, book.Sell)
+	return book
+}
+
+func (m *Manager) normalizeOrders(exchange string, orders []schemas.Order) []schemas.Order {
+	if orders == nil {
+		return nil
+	}
+	result := make([]schemas.Order, len(orders))
+	for i, o := range orders {
+		o.Symbol = m.CommonName(exchange, o.Symbol)
+		result[i] = o
+	}
+	return result
+}
+
+func (m *Manager) normalizeTrades(exchange string, trades []schemas.Trade) []schemas.Trade {
+	if trades == nil {
+		return nil
+	}
+	result := make([]schemas.Trade, len(trades))
+	for i, t := range trades {
+		t.Symbol = m.CommonName(exchange, t.Symbol)
+		result[i] = t
+	}
+	return result
+}
+
+func (m *Manager) normalizeCandles(exchange string, candles []schemas.Candle) []schemas.Candle {
+	if candles == nil {
+		return nil
+	}
+	result := make([]schemas.Candle, len(candles))
+	for i, c := range candles {
+		c.Symbol = m.CommonName(exchange, c.Symbol)
+		result[i] = c
+	}
+	return result
+}
+
+// TradingExchanges - exchanges with credentials or paper trading, in config order
+func (m *Manager) TradingExchanges() (names []string) {
+	for _, name := range m.names {
+		if m.trading[name] {
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+/*
+Balances - exchange wallet balances of exchanges with credentials by exchange and common coin name.
+Exchanges are loaded in parallel, failed ones are in ExchangesError
+*/
+func (m *Manager) Balances() (map[string]map[string]schemas.Balance, error) {
+	balances := make(map[string]map[string]schemas.Balance)
+	var mu sync.Mutex
+	err := m.eachTrading(func(exchange string, api API) error {
+		info, err := api.TradingProvider().Info()
+		if err != nil {
+			return err
+		}
+		wallet := make(map[string]schemas.Balance)
+		for coin, b := range info.Wallet(schemas.WalletExchange) {
+			common := m.NormalizeCoin(exchange, coin)
+			b.Coin = common
+			wallet[common] = addBalance(wallet[common], b)
+		}
+		mu.Lock()
+		balances[exchange] = wallet
+		mu.Unlock()
+		return nil
+	})
+	return balances, err
+}
+
+// TotalBalances - exchange wallet balances of exchanges with credentials summed by common coin name
+func (m *Manager) TotalBalances() (map[string]schemas.Balance, error) {
+	balances, err := m.Balances()
+	total := make(map[string]schemas.Balance)
+	for _, wallet := range balances {
+		for coin, b := range wallet {
+			total[coin] = addBalance(total[coin], b)
+		}
+	}
+	return total, err
+}
+
+/*
+OpenOrders - open orders of exchanges with credentials by exchange, symbols are common names.
+Exchanges are loaded in parallel, failed ones are in ExchangesError
+*/
+func (m *Manager) OpenOrders() (map[string][]schemas.Order, error) {
+	orders := make(map[string][]schemas.Order)
+	var mu sync.Mutex
+	err := m.eachTrading(func(exchange string, api API) error {
+		o, err := api.TradingProvider().Orders(m.Symbols(exchange))
+		if err != nil {
+			return err
+		}
+		o = m.normalizeOrders(exchange, o)
+		mu.Lock()
+		orders[exchange] = o
+		mu.Unlock()
+		return nil
+	})
+	return orders, err
+}
+
+// eachTrading - calling fn for every exchange with credentials in parallel
+func (m *Manager) eachTrading(fn func(exchange string, api API) error) error {
+	errs := make(ExchangesError)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range m.TradingExchanges() {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if err := fn(name, m.exchanges[name]); err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// addBalance - sum of balances, exact values are summed while both balances have them
+func addBalance(a, b schemas.Balance) schemas.Balance {
+	empty := a.Total == 0 && a.Available == 0 && a.InOrders == 0 && !a.TotalDecimal.IsSet()
+	sum := schemas.Balance{
+		Coin:      b.Coin,
+		Available: a.Available + b.Available,
+		InOrders:  a.InOrders + b.InOrders,
+		Total:     a.Total + b.Total,
+	}
+	if (empty || a.TotalDecimal.IsSet()) && b.TotalDecimal.IsSet() {
+		sum.AvailableDecimal = a.AvailableDecimal.Add(b.AvailableDecimal)
+		sum.InOrdersDecimal = a.InOrdersDecimal.Add(b.InOrdersDecimal)
+		sum.TotalDecimal = a.TotalDecimal.Add(b.TotalDecimal)
+	}
+	return sum
+}
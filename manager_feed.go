@@ -0,0 +1,369 @@
+package goex
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// Market data kinds of manager feeds
+const (
+	feedQuotes  = "quotes"
+	feedTrades  = "trades"
+	feedBooks   = "books"
+	feedCandles = "candles"
+)
+
+// feedSource - provider methods of market data kind
+type feedSource struct {
+	kind string
+	// subscribe - first subscription of provider
+	subscribe func(api API, symbols []schemas.Symbol) chan schemas.ResultChannel
+	// add - adding symbols to running subscription, returns channels of separate subscriptions if any
+	add func(api API, symbols []schemas.Symbol) []chan schemas.ResultChannel
+	// snapshot - loading data of symbol for consumer joining running subscription, nil if kind has no snapshots
+	snapshot func(api API, symbol schemas.Symbol) (interface{}, error)
+}
+
+/*
+ErrFeedOverflow - sent to subscription which hasn't read its channel in time:
+its data has been dropped since previous message, so books have to be reloaded
+*/
+var ErrFeedOverflow = errors.New("Subscription channel is full, data has been dropped")
+
+/*
+feedConsumer - manager subscription getting data of feed.
+Data is never blocked by consumer: when its channel is full, data is dropped and
+ErrFeedOverflow is sent before next data
+*/
+type feedConsumer struct {
+	symbols map[string]bool // common names, nil for all symbols
+	out     chan schemas.ExchangeChannel
+
+	// symbols waiting for snapshot with their data received meanwhile, it's sent after snapshot
+	syncing    map[string][]schemas.ResultChannel
+	overflowed bool
+	closed     bool
+	sync.Mutex
+}
+
+/*
+feed - one subscription of exchange provider shared by manager subscriptions.
+Data is normalized once and sent to every consumer, filtered by consumer symbols
+*/
+type feed struct {
+	manager   *Manager
+	exchange  string
+	started   bool
+	symbols   map[string]bool // subscribed exchange symbol names
+	consumers []*feedConsumer
+
+	subscribing sync.Mutex // provider calls are serialized, consumers are sent data meanwhile
+	sync.Mutex
+}
+
+// feed - feed of exchange and data kind, it's created on first call
+func (m *Manager) feed(exchange, kind string) *feed {
+	m.feedsLock.Lock()
+	defer m.feedsLock.Unlock()
+	key := exchange + ":" + kind
+	f, ok := m.feeds[key]
+	if !ok {
+		f = &feed{
+			manager:  m,
+			exchange: exchange,
+			symbols:  make(map[string]bool),
+		}
+		m.feeds[key] = f
+	}
+	return f
+}
+
+/*
+join - adding consumer of symbols. Provider is subscribed by first consumer,
+symbols missing in subscription are added to it, subscribed symbols get snapshots
+*/
+func (f *feed) join(c *feedConsumer, symbols []schemas.Symbol, source feedSource) {
+	api := f.manager.exchanges[f.exchange]
+
+	f.subscribing.Lock()
+	defer f.subscribing.Unlock()
+
+	f.Lock()
+	var fresh, subscribed []schemas.Symbol
+	for _, s := range symbols {
+		if f.symbols[s.Name] {
+			subscribed = append(subscribed, s)
+			continue
+		}
+		f.symbols[s.Name] = true
+		fresh = append(fresh, s)
+	}
+	if source.snapshot != nil && len(subscribed) > 0 {
+		// data of subscribed symbols is held until their snapshots are sent
+		c.syncing = make(map[string][]schemas.ResultChannel)
+		for _, s := range subscribed {
+			c.syncing[f.manager.CommonName(f.exchange, s.Name)] = nil
+		}
+	}
+	f.consumers = append(f.consumers, c)
+	started := f.started
+	f.started = true
+	f.Unlock()
+
+	if !started {
+		go f.forward(source.subscribe(api, fresh))
+		return
+	}
+	if len(fresh) > 0 {
+		for _, ch := range source.add(api, fresh) {
+			go f.forward(ch)
+		}
+	}
+	if source.snapshot != nil && len(subscribed) > 0 {
+		go f.snapshots(c, api, subscribed, source.snapshot)
+	}
+}
+
+// forward - sending normalized data of subscription to consumers
+func (f *feed) forward(ch chan schemas.ResultChannel) {
+	for msg := range ch {
+		msg.Data = f.manager.normalizeData(f.exchange, msg.Data)
+		f.Lock()
+		consumers := make([]*feedConsumer, len(f.consumers))
+		copy(consumers, f.consumers)
+		f.Unlock()
+		for _, c := range consumers {
+			c.send(f.exchange, msg)
+		}
+	}
+}
+
+/*
+snapshots - sending snapshots of symbols subscribed before consumer has joined.
+Symbol data held before snapshot request is older than snapshot and is dropped,
+data received while it's loaded is sent after it
+*/
+func (f *feed) snapshots(c *feedConsumer, api API, symbols []schemas.Symbol, get func(api API, symbol schemas.Symbol) (interface{}, error)) {
+	for _, s := range symbols {
+		name := f.manager.CommonName(f.exchange, s.Name)
+		c.resync(name)
+		data, err := get(api, s)
+		if err != nil {
+			log.Printf("[%s] Error loading snapshot of %s: %v\n", strings.ToUpper(f.exchange), s.Name, err)
+			c.synced(f.exchange, name, schemas.ResultChannel{DataType: schemas.DataTypeSnapshot, Error: err})
+			continue
+		}
+		c.synced(f.exchange, name, schemas.ResultChannel{
+			DataType: schemas.DataTypeSnapshot,
+			Data:     f.manager.normalizeData(f.exchange, data),
+		})
+	}
+}
+
+/*
+unsubscribe - removing consumers sending into out from feed.
+Returns removed consumers, they are closed by caller
+*/
+func (f *feed) unsubscribe(out chan schemas.ExchangeChannel) (removed []*feedConsumer) {
+	f.Lock()
+	defer f.Unlock()
+	consumers := f.consumers[:0]
+	for _, c := range f.consumers {
+		if c.out == out {
+			removed = append(removed, c)
+			continue
+		}
+		consumers = append(consumers, c)
+	}
+	f.consumers = consumers
+	return
+}
+
+/*
+Unsubscribe - stopping manager subscription, its channel is closed.
+Exchange providers are still subscribed, they are shared with other subscriptions
+*/
+func (m *Manager) Unsubscribe(ch chan schemas.ExchangeChannel) {
+	m.feedsLock.Lock()
+	var removed []*feedConsumer
+	for _, f := range m.feeds {
+		removed = append(removed, f.unsubscribe(ch)...)
+	}
+	m.feedsLock.Unlock()
+	if len(removed) == 0 {
+		return
+	}
+	// channel is closed when no consumer is sending into it
+	for _, c := range removed {
+		c.Lock()
+		c.closed = true
+		c.Unlock()
+	}
+	close(ch)
+}
+
+// send - sending data of consumer symbols, errors are sent to every consumer
+func (c *feedConsumer) send(exchange string, msg schemas.ResultChannel) {
+	c.Lock()
+	defer c.Unlock()
+	if msg.Data != nil && (c.symbols != nil || len(c.syncing) > 0) {
+		// unknown data types pass any filter, they aren't held
+		if known := filterSymbols(msg.Data, func(string) bool { return false }) == nil; known {
+			c.hold(msg)
+		}
+		msg.Data = filterSymbols(msg.Data, c.live)
+		if msg.Data == nil && msg.Error == nil {
+			return
+		}
+	}
+	c.deliver(exchange, msg)
+}
+
+// hold - keeping data of symbols waiting for snapshot, has to be called under lock
+func (c *feedConsumer) hold(msg schemas.ResultChannel) {
+	for name := range c.syncing {
+		if data := filterSymbols(msg.Data, func(s string) bool { return s == name }); data != nil {
+			held := msg
+			held.Data = data
+			c.syncing[name] = append(c.syncing[name], held)
+		}
+	}
+}
+
+// live - checking symbol data is sent to consumer now, has to be called under lock
+func (c *feedConsumer) live(symbol string) bool {
+	if _, ok := c.syncing[symbol]; ok {
+		return false
+	}
+	return c.symbols == nil || c.symbols[symbol]
+}
+
+// resync - dropping symbol data held before snapshot request
+func (c *feedConsumer) resync(symbol string) {
+	c.Lock()
+	defer c.Unlock()
+	c.syncing[symbol] = nil
+}
+
+// synced - sending symbol snapshot and data held while it was loaded, symbol data is sent as is then
+func (c *feedConsumer) synced(exchange, symbol string, snapshot schemas.ResultChannel) {
+	c.Lock()
+	defer c.Unlock()
+	c.deliver(exchange, snapshot)
+	for _, msg := range c.syncing[symbol] {
+		c.deliver(exchange, msg)
+	}
+	delete(c.syncing, symbol)
+}
+
+// deliver - sending into consumer channel without blocking, has to be called under lock
+func (c *feedConsumer) deliver(exchange string, msg schemas.ResultChannel) {
+	if c.closed {
+		return
+	}
+	if c.overflowed {
+		select {
+		case c.out <- schemas.ExchangeChannel{Exchange: exchange, ResultChannel: schemas.ResultChannel{Error: ErrFeedOverflow}}:
+			c.overflowed = false
+		default:
+			return
+		}
+	}
+	select {
+	case c.out <- schemas.ExchangeChannel{Exchange: exchange, ResultChannel: msg}:
+	default:
+		log.Printf("[%s] Subscription channel is full, data is dropped\n", strings.ToUpper(exchange))
+		c.overflowed = true
+	}
+}
+
+// filterSymbols - data of symbols matching filter only, nil if there is no such data. Unknown data types are returned as is
+func filterSymbols(data interface{}, symbols func(symbol string) bool) interface{} {
+	switch v := data.(type) {
+	case schemas.OrderBook:
+		if symbols(v.Symbol) {
+			return v
+		}
+	case []schemas.OrderBook:
+		var books []schemas.OrderBook
+		for _, b := range v {
+			if symbols(b.Symbol) {
+				books = append(books, b)
+			}
+		}
+		if len(books) > 0 {
+			return books
+		}
+	case schemas.Quote:
+		if symbols(v.Symbol) {
+			return v
+		}
+	case []schemas.Quote:
+		var quotes []schemas.Quote
+		for _, q := range v {
+			if symbols(q.Symbol) {
+				quotes = append(quotes, q)
+			}
+		}
+		if len(quotes) > 0 {
+			return quotes
+		}
+	case []schemas.Trade:
+		if trades := filterTrades(v, symbols); len(trades) > 0 {
+			return trades
+		}
+	case [][]schemas.Trade:
+		var groups [][]schemas.Trade
+		for _, t := range v {
+			if trades := filterTrades(t, symbols); len(trades) > 0 {
+				groups = append(groups, trades)
+			}
+		}
+		if len(groups) > 0 {
+			return groups
+		}
+	case schemas.Candle:
+		if symbols(v.Symbol) {
+			return v
+		}
+	case []schemas.Candle:
+		if candles := filterCandles(v, symbols); len(candles) > 0 {
+			return candles
+		}
+	case [][]schemas.Candle:
+		var groups [][]schemas.Candle
+		for _, c := range v {
+			if candles := filterCandles(c, symbols); len(candles) > 0 {
+				groups = append(groups, candles)
+			}
+		}
+		if len(groups) > 0 {
+			return groups
+		}
+	default:
+		return data
+	}
+	return nil
+}
+
+func filterTrades(trades []schemas.Trade, symbols func(symbol string) bool) (result []schemas.Trade) {
+	for _, t := range trades {
+		if symbols(t.Symbol) {
+			result = append(result, t)
+		}
+	}
+	return
+}
+
+func filterCandles(candles []schemas.Candle, symbols func(symbol string) bool) (result []schemas.Candle) {
+	for _, c := range candles {
+		if symbols(c.Symbol) {
+			result = append(result, c)
+		}
+	}
+	return
+}
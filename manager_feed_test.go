@@ -0,0 +1,98 @@
+package goex
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+func book(symbol string, price float64) schemas.OrderBook {
+	return schemas.OrderBook{Symbol: symbol, Buy: []schemas.Order{{Symbol: symbol, Price: price, Amount: 1}}}
+}
+
+func received(out chan schemas.ExchangeChannel) (res []interface{}) {
+	for len(out) > 0 {
+		msg := <-out
+		if msg.Error != nil {
+			res = append(res, msg.Error)
+			continue
+		}
+		res = append(res, msg.Data)
+	}
+	return
+}
+
+func TestFeedConsumer(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps func(c *feedConsumer)
+		want  []interface{}
+	}{
+		{
+			name: "data of other symbols is filtered",
+			steps: func(c *feedConsumer) {
+				c.send("binance", schemas.ResultChannel{Data: []schemas.OrderBook{book("ETH-BTC", 1), book("LTC-BTC", 2)}})
+			},
+			want: []interface{}{[]schemas.OrderBook{book("ETH-BTC", 1)}},
+		},
+		{
+			name: "data held before snapshot request is dropped, data after it is sent after snapshot",
+			steps: func(c *feedConsumer) {
+				c.syncing = map[string][]schemas.ResultChannel{"ETH-BTC": nil}
+				c.send("binance", schemas.ResultChannel{Data: book("ETH-BTC", 1)})
+				c.resync("ETH-BTC")
+				c.send("binance", schemas.ResultChannel{Data: book("ETH-BTC", 2)})
+				c.synced("binance", "ETH-BTC", schemas.ResultChannel{Data: book("ETH-BTC", 3)})
+				c.send("binance", schemas.ResultChannel{Data: book("ETH-BTC", 4)})
+			},
+			want: []interface{}{book("ETH-BTC", 3), book("ETH-BTC", 2), book("ETH-BTC", 4)},
+		},
+		{
+			name: "full channel drops data and reports overflow",
+			steps: func(c *feedConsumer) {
+				for i := 0; i < 4; i++ {
+					c.send("binance", schemas.ResultChannel{Data: book("ETH-BTC", float64(i))})
+				}
+				received(c.out)
+				c.send("binance", schemas.ResultChannel{Data: book("ETH-BTC", 5)})
+			},
+			want: []interface{}{ErrFeedOverflow, book("ETH-BTC", 5)},
+		},
+		{
+			name: "closed consumer isn't sent data",
+			steps: func(c *feedConsumer) {
+				c.closed = true
+				c.send("binance", schemas.ResultChannel{Data: book("ETH-BTC", 1)})
+			},
+		},
+	}
+	for _, tt := range tests {
+		c := &feedConsumer{
+			symbols: map[string]bool{"ETH-BTC": true},
+			out:     make(chan schemas.ExchangeChannel, 3),
+		}
+		tt.steps(c)
+		if got := received(c.out); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: received %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestManagerUnsubscribe(t *testing.T) {
+	m := &Manager{feeds: make(map[string]*feed)}
+	out := make(chan schemas.ExchangeChannel, 1)
+	other := make(chan schemas.ExchangeChannel, 1)
+	f := m.feed("binance", feedBooks)
+	f.consumers = []*feedConsumer{{out: out}, {out: other}}
+
+	m.Unsubscribe(out)
+	if len(f.consumers) != 1 || f.consumers[0].out != other {
+		t.Fatalf("consumers = %d, want other one only", len(f.consumers))
+	}
+	if _, ok := <-out; ok {
+		t.Error("unsubscribed channel isn't closed")
+	}
+	// second call doesn't close channel again
+	m.Unsubscribe(out)
+}
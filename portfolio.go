@@ -0,0 +1,368 @@
+package goex
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// defaultMaxHops - conversion path length, i.e. XYZ-ETH, ETH-BTC, BTC-USDT
+const defaultMaxHops = 3
+
+// PortfolioConfig - valuation currency
+type PortfolioConfig struct {
+	Currency string // common coin name, i.e. USD, USDT or BTC
+	// coins valued 1:1 to currency, i.e. USDT and USDC for USD
+	Pegged  []string
+	MaxHops int // max symbols in conversion path, 3 by default
+}
+
+// CoinValuation - amount of coin on all exchanges valued in currency
+type CoinValuation struct {
+	Coin   string   `json:"coin"`
+	Amount float64  `json:"amount"`
+	Price  float64  `json:"price"`
+	Value  float64  `json:"value"`
+	Path   []string `json:"path"` // symbols (common names) of conversion, empty for currency and pegged coins
+}
+
+// Valuation - balances of exchanges valued in currency
+type Valuation struct {
+	Currency  string                   `json:"currency"`
+	Total     float64                  `json:"total"` // equity: value of every priced coin
+	Coins     map[string]CoinValuation `json:"coins"`
+	Exchanges map[string]float64       `json:"exchanges"` // value by exchange
+	Unpriced  []string                 `json:"unpriced"`  // coins without conversion path, they aren't in total
+	Time      time.Time                `json:"time"`
+}
+
+// ValuationChannel - valuation changes, exchange is set for errors
+type ValuationChannel struct {
+	Exchange string
+	Data     Valuation
+	Error    error
+}
+
+// hop - symbol of conversion path, inverted when coin is quote coin of symbol
+type hop struct {
+	symbol string
+	invert bool
+}
+
+// edge - symbol to other coin
+type edge struct {
+	coin string
+	hop
+}
+
+/*
+Portfolio - exchange wallet balances of manager exchanges with credentials
+valued in one currency by live quotes. Coin without symbol of currency
+is converted through other coins by shortest path of quoted symbols
+*/
+type Portfolio struct {
+	manager  *Manager
+	config   PortfolioConfig
+	targets  map[string]bool                       // currency and pegged coins
+	balances map[string]map[string]schemas.Balance // by exchange and common coin
+	prices   map[string]map[string]float64         // by common symbol and exchange
+	edges    map[string][]edge                     // quoted symbols by coin
+	paths    map[string][]hop                      // conversion paths cache by coin, nil if there is no path
+
+	sync.Mutex
+}
+
+// NewPortfolio - Portfolio constructor
+func NewPortfolio(manager *Manager, config PortfolioConfig) *Portfolio {
+	config.Currency = strings.ToUpper(config.Currency)
+	if config.MaxHops <= 0 {
+		config.MaxHops = defaultMaxHops
+	}
+	targets := map[string]bool{config.Currency: true}
+	for _, coin := range config.Pegged {
+		targets[strings.ToUpper(coin)] = true
+	}
+	return &Portfolio{
+		manager:  manager,
+		config:   config,
+		targets:  targets,
+		balances: make(map[string]map[string]schemas.Balance),
+		prices:   make(map[string]map[string]float64),
+		edges:    make(map[string][]edge),
+		paths:    make(map[string][]hop),
+	}
+}
+
+/*
+Subscribe - subscribing to quotes of all manager symbols and loading balances with interval.
+Valuation is sent every time it changes. Balances of failed exchange are kept, its error is sent
+*/
+func (p *Portfolio) Subscribe(d time.Duration) chan ValuationChannel {
+	quotes := p.manager.SubscribeQuotes(nil, d)
+	ch := make(chan ValuationChannel, cap(quotes))
+	go func() {
+		var last Valuation
+		send := func() {
+			v := p.Valuation()
+			if v.Time = last.Time; reflect.DeepEqual(v, last) {
+				return
+			}
+			v.Time = time.Now()
+			last = v
+			ch <- ValuationChannel{
+				Data: v,
+			}
+		}
+		load := func() {
+			if errs, ok := p.LoadBalances().(ExchangesError); ok {
+				for exchange, err := range errs {
+					ch <- ValuationChannel{
+						Exchange: exchange,
+						Error:    err,
+					}
+				}
+			}
+			send()
+		}
+		load()
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+		for {
+			select {
+			case msg, ok := <-quotes:
+				if !ok {
+					return
+				}
+				if msg.Error != nil {
+					ch <- ValuationChannel{
+						Exchange: msg.Exchange,
+						Error:    msg.Error,
+					}
+					continue
+				}
+				p.ApplyQuotes(msg.Exchange, msg.ResultChannel)
+				send()
+			case <-ticker.C:
+				load()
+			}
+		}
+	}()
+	return ch
+}
+
+// LoadBalances - loading balances of manager exchanges with credentials, balances of failed ones are kept
+func (p *Portfolio) LoadBalances() error {
+	balances, err := p.manager.Balances()
+	for exchange, wallet := range balances {
+		p.SetBalances(exchange, wallet)
+	}
+	return err
+}
+
+// SetBalances - replacing balances of exchange, coins have to be common names
+func (p *Portfolio) SetBalances(exchange string, balances map[string]schemas.Balance) {
+	p.Lock()
+	defer p.Unlock()
+	p.balances[exchange] = balances
+}
+
+/*
+ApplyQuotes - applying quotes message of exchange, symbols have to be common names.
+Last price is used, middle of bid and ask if quote hasn't it
+*/
+func (p *Portfolio) ApplyQuotes(exchange string, msg schemas.ResultChannel) {
+	var quotes []schemas.Quote
+	switch v := msg.Data.(type) {
+	case schemas.Quote:
+		quotes = []schemas.Quote{v}
+	case []schemas.Quote:
+		quotes = v
+	default:
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	for _, q := range quotes {
+		price := q.Price
+		if price <= 0 && q.Buy > 0 && q.Sell > 0 {
+			price = (q.Buy + q.Sell) / 2
+		}
+		if price <= 0 {
+			continue
+		}
+		if p.prices[q.Symbol] == nil {
+			if !p.addSymbol(q.Symbol) {
+				continue
+			}
+			p.prices[q.Symbol] = make(map[string]float64)
+		}
+		p.prices[q.Symbol][exchange] = price
+	}
+}
+
+// SetPrice - setting price of symbol (common name) on exchange, i.e. from order book
+func (p *Portfolio) SetPrice(exchange, symbol string, price float64) {
+	p.ApplyQuotes(exchange, schemas.ResultChannel{
+		Data: schemas.Quote{Symbol: symbol, Price: price},
+	})
+}
+
+// addSymbol - adding symbol to conversion graph, cached paths are reset
+func (p *Portfolio) addSymbol(symbol string) bool {
+	coins := strings.Split(symbol, "-")
+	if len(coins) != 2 {
+		return false
+	}
+	base, quote := coins[0], coins[1]
+	p.edges[base] = append(p.edges[base], edge{coin: quote, hop: hop{symbol: symbol}})
+	p.edges[quote] = append(p.edges[quote], edge{coin: base, hop: hop{symbol: symbol, invert: true}})
+	p.paths = make(map[string][]hop)
+	return true
+}
+
+// Price - price of coin in currency and symbols of conversion
+func (p *Portfolio) Price(coin string) (price float64, path []string, ok bool) {
+	p.Lock()
+	defer p.Unlock()
+	return p.price(strings.ToUpper(coin))
+}
+
+func (p *Portfolio) price(coin string) (price float64, path []string, ok bool) {
+	if p.targets[coin] {
+		return 1, nil, true
+	}
+	hops, cached := p.paths[coin]
+	if !cached {
+		hops = p.findPath(coin)
+		p.paths[coin] = hops
+	}
+	if hops == nil {
+		return 0, nil, false
+	}
+	price = 1
+	for _, h := range hops {
+		rate := p.symbolPrice(h.symbol)
+		if h.invert {
+			rate = 1 / rate
+		}
+		price *= rate
+		path = append(path, h.symbol)
+	}
+	return price, path, true
+}
+
+// symbolPrice - price of symbol on first exchange in manager order which has it
+func (p *Portfolio) symbolPrice(symbol string) float64 {
+	for _, exchange := range p.manager.Exchanges() {
+		if price, ok := p.prices[symbol][exchange]; ok {
+			return price
+		}
+	}
+	// exchanges which aren't in manager, i.e. prices set with SetPrice
+	var exchanges []string
+	for exchange := range p.prices[symbol] {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	if len(exchanges) == 0 {
+		return 0
+	}
+	return p.prices[symbol][exchanges[0]]
+}
+
+// findPath - shortest path of quoted symbols from coin to currency or pegged coin, coins are visited sorted
+func (p *Portfolio) findPath(coin string) []hop {
+	type node struct {
+		coin string
+		path []hop
+	}
+	visited := map[string]bool{coin: true}
+	queue := []node{{coin: coin}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if len(n.path) >= p.config.MaxHops {
+			continue
+		}
+		edges := append([]edge(nil), p.edges[n.coin]...)
+		sort.Slice(edges, func(i, j int) bool {
+			return edges[i].symbol < edges[j].symbol
+		})
+		for _, e := range edges {
+			if visited[e.coin] {
+				continue
+			}
+			visited[e.coin] = true
+			path := append(append([]hop(nil), n.path...), e.hop)
+			if p.targets[e.coin] {
+				return path
+			}
+			queue = append(queue, node{coin: e.coin, path: path})
+		}
+	}
+	return nil
+}
+
+// Valuation - current balances valued by current prices, coins without price are in Unpriced
+func (p *Portfolio) Valuation() Valuation {
+	p.Lock()
+	defer p.Unlock()
+	v := Valuation{
+		Currency:  p.config.Currency,
+		Coins:     make(map[string]CoinValuation),
+		Exchanges: make(map[string]float64),
+		Time:      time.Now(),
+	}
+	// sorted, so sums are the same for the same balances and prices
+	exchanges := make([]string, 0, len(p.balances))
+	for exchange := range p.balances {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	unpriced := make(map[string]bool)
+	for _, exchange := range exchanges {
+		wallet := p.balances[exchange]
+		coins := make([]string, 0, len(wallet))
+		for coin := range wallet {
+			coins = append(coins, coin)
+		}
+		sort.Strings(coins)
+		for _, coin := range coins {
+			b := wallet[coin]
+			if b.Total == 0 {
+				continue
+			}
+			cv := v.Coins[coin]
+			cv.Coin = coin
+			cv.Amount += b.Total
+			price, path, ok := p.price(coin)
+			if !ok {
+				unpriced[coin] = true
+				v.Coins[coin] = cv
+				continue
+			}
+			cv.Price = price
+			cv.Path = path
+			cv.Value = cv.Amount * price
+			v.Coins[coin] = cv
+			v.Exchanges[exchange] += b.Total * price
+		}
+	}
+	coins := make([]string, 0, len(v.Coins))
+	for coin := range v.Coins {
+		coins = append(coins, coin)
+	}
+	sort.Strings(coins)
+	for _, coin := range coins {
+		v.Total += v.Coins[coin].Value
+		if unpriced[coin] {
+			v.Unpriced = append(v.Unpriced, coin)
+		}
+	}
+	return v
+}
@@ -0,0 +1,151 @@
+package recorder
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxLineSize - order book snapshots of big symbols are long lines
+const maxLineSize = 64 << 20
+
+// Files - journal files of directory in recording order, all prefixes if prefix is empty
+func Files(dir, prefix string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, fileExtension) {
+			continue
+		}
+		if prefix != "" && !strings.HasPrefix(name, prefix+"-") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	// file names end with creation time, so they are sorted by it within prefix
+	sort.Slice(files, func(i, j int) bool {
+		return fileTime(files[i]) < fileTime(files[j]) ||
+			(fileTime(files[i]) == fileTime(files[j]) && files[i] < files[j])
+	})
+	return files, nil
+}
+
+func fileTime(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), fileExtension)
+	parts := strings.Split(name, "-")
+	if len(parts) < 3 {
+		return name
+	}
+	return strings.Join(parts[len(parts)-2:], "-")
+}
+
+/*
+Reader - reading journal files one by one.
+Unfinished gzip tail of file (i.e. recorder was killed) ends that file without error,
+cut last line of last file is skipped. Other broken lines are returned as errors
+*/
+type Reader struct {
+	files   []string
+	current int
+	line    int
+	file    *os.File
+	gz      *gzip.Reader
+	scanner *bufio.Scanner
+}
+
+// NewReader - Reader of files in given order
+func NewReader(files ...string) *Reader {
+	return &Reader{
+		files:   files,
+		current: -1,
+	}
+}
+
+// OpenDir - Reader of all journal files of directory
+func OpenDir(dir string) (*Reader, error) {
+	files, err := Files(dir, "")
+	if err != nil {
+		return nil, err
+	}
+	return NewReader(files...), nil
+}
+
+// Next - next record, io.EOF after last file
+func (r *Reader) Next() (rec Record, err error) {
+	for {
+		if r.scanner == nil {
+			if err = r.open(); err != nil {
+				return
+			}
+		}
+		if r.scanner.Scan() {
+			r.line++
+			if err = json.Unmarshal(r.scanner.Bytes(), &rec); err != nil {
+				// last line of file being recorded can be cut
+				if r.current == len(r.files)-1 && !r.scanner.Scan() {
+					if serr := r.scanner.Err(); serr == nil || serr == io.ErrUnexpectedEOF {
+						continue
+					}
+				}
+				err = fmt.Errorf("%s line %d: %v", r.files[r.current], r.line, err)
+				r.Close()
+				return
+			}
+			return
+		}
+		if err = r.scanner.Err(); err != nil && err != io.ErrUnexpectedEOF {
+			r.Close()
+			return
+		}
+		r.Close()
+	}
+}
+
+// Close - closing current file, next record is read from next file
+func (r *Reader) Close() error {
+	r.scanner = nil
+	if r.gz != nil {
+		r.gz.Close()
+		r.gz = nil
+	}
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// open - opening next file, io.EOF if there are no files left
+func (r *Reader) open() (err error) {
+	r.current++
+	if r.current >= len(r.files) {
+		return io.EOF
+	}
+	if r.file, err = os.Open(r.files[r.current]); err != nil {
+		return
+	}
+	if r.gz, err = gzip.NewReader(r.file); err != nil {
+		r.Close()
+		if err == io.EOF {
+			// file is created, but nothing is flushed yet
+			return r.open()
+		}
+		return
+	}
+	r.line = 0
+	r.scanner = bufio.NewScanner(r.gz)
+	r.scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return
+}
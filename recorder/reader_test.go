@@ -0,0 +1,85 @@
+package recorder
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile - gzip journal file of lines
+func writeFile(t *testing.T, dir, name string, lines ...string) string {
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	if _, err = gz.Write([]byte(strings.Join(lines, "\n"))); err != nil {
+		t.Fatal(err)
+	}
+	gz.Close()
+	f.Close()
+	return path
+}
+
+func TestReaderNext(t *testing.T) {
+	good := `{"t":1,"e":"binance","k":"trades"}`
+	cut := `{"t":2,"e":"bin`
+	tests := []struct {
+		name    string
+		files   [][]string
+		records int
+		fails   bool
+	}{
+		{
+			name:    "records of all files are read",
+			files:   [][]string{{good, good}, {good}},
+			records: 3,
+		},
+		{
+			name:    "cut last line of last file is skipped",
+			files:   [][]string{{good}, {good, cut}},
+			records: 2,
+		},
+		{
+			name:    "cut last line of previous file is error",
+			files:   [][]string{{good, cut}, {good}},
+			records: 1,
+			fails:   true,
+		},
+		{
+			name:    "broken line inside file is error",
+			files:   [][]string{{good, cut, good}},
+			records: 1,
+			fails:   true,
+		},
+	}
+	for _, tt := range tests {
+		dir, err := ioutil.TempDir("", "reader")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var files []string
+		for i, lines := range tt.files {
+			files = append(files, writeFile(t, dir, string(rune('a'+i))+fileExtension, lines...))
+		}
+		r := NewReader(files...)
+		records := 0
+		for {
+			if _, err = r.Next(); err != nil {
+				break
+			}
+			records++
+		}
+		r.Close()
+		os.RemoveAll(dir)
+
+		if records != tt.records || (err != io.EOF) != tt.fails {
+			t.Errorf("%s: records = %d, error = %v, want %d records, error %v", tt.name, records, err, tt.records, tt.fails)
+		}
+	}
+}
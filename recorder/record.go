@@ -0,0 +1,158 @@
+package recorder
+
+import (
+	"errors"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// Market data kinds
+const (
+	KindBooks   = "books"
+	KindTrades  = "trades"
+	KindQuotes  = "quotes"
+	KindCandles = "candles"
+)
+
+/*
+Record - one journal line: market data of one symbol as it was received.
+One of data fields is filled by kind, error messages have Error only
+*/
+type Record struct {
+	Time     int64  `json:"t"` // receive time, unix nanoseconds
+	Exchange string `json:"e"`
+	Kind     string `json:"k"`
+	Symbol   string `json:"s"` // symbol as exchange sends it in data
+	DataType string `json:"dt,omitempty"`
+	Error    string `json:"err,omitempty"`
+
+	Book    *schemas.OrderBook `json:"b,omitempty"`
+	Trades  []schemas.Trade    `json:"tr,omitempty"`
+	Quote   *schemas.Quote     `json:"q,omitempty"`
+	Candles []schemas.Candle   `json:"c,omitempty"`
+}
+
+// At - receive time
+func (r Record) At() time.Time {
+	return time.Unix(0, r.Time)
+}
+
+// Message - record as provider subscription message
+func (r Record) Message() schemas.ResultChannel {
+	msg := schemas.ResultChannel{
+		DataType: r.DataType,
+	}
+	if r.Error != "" {
+		msg.Error = errors.New(r.Error)
+		return msg
+	}
+	switch r.Kind {
+	case KindBooks:
+		if r.Book != nil {
+			msg.Data = *r.Book
+		}
+	case KindTrades:
+		msg.Data = r.Trades
+	case KindQuotes:
+		if r.Quote != nil {
+			msg.Data = *r.Quote
+		}
+	case KindCandles:
+		msg.Data = r.Candles
+	}
+	return msg
+}
+
+/*
+split - message data as records by symbol, data of unknown type is skipped.
+Several books or trades batches of one message become separate records
+*/
+func split(at time.Time, exchange, kind string, msg schemas.ResultChannel) (records []Record) {
+	base := Record{
+		Time:     at.UnixNano(),
+		Exchange: exchange,
+		Kind:     kind,
+		DataType: msg.DataType,
+	}
+	if msg.Error != nil {
+		base.Error = msg.Error.Error()
+		return []Record{base}
+	}
+	addTrades := func(trades []schemas.Trade) {
+		index := make(map[string]int)
+		for _, t := range trades {
+			i, ok := index[t.Symbol]
+			if !ok {
+				r := base
+				r.Kind = KindTrades
+				r.Symbol = t.Symbol
+				records = append(records, r)
+				i = len(records) - 1
+				index[t.Symbol] = i
+			}
+			records[i].Trades = append(records[i].Trades, t)
+		}
+	}
+	addCandles := func(candles []schemas.Candle) {
+		index := make(map[string]int)
+		for _, c := range candles {
+			i, ok := index[c.Symbol]
+			if !ok {
+				r := base
+				r.Kind = KindCandles
+				r.Symbol = c.Symbol
+				records = append(records, r)
+				i = len(records) - 1
+				index[c.Symbol] = i
+			}
+			records[i].Candles = append(records[i].Candles, c)
+		}
+	}
+	addBook := func(book schemas.OrderBook) {
+		r := base
+		r.Kind = KindBooks
+		r.Symbol = book.Symbol
+		r.Book = &book
+		records = append(records, r)
+	}
+	addQuote := func(quote schemas.Quote) {
+		r := base
+		r.Kind = KindQuotes
+		r.Symbol = quote.Symbol
+		r.Quote = &quote
+		records = append(records, r)
+	}
+
+	switch v := msg.Data.(type) {
+	case schemas.OrderBook:
+		addBook(v)
+	case []schemas.OrderBook:
+		for _, b := range v {
+			addBook(b)
+		}
+	case schemas.Quote:
+		addQuote(v)
+	case []schemas.Quote:
+		for _, q := range v {
+			addQuote(q)
+		}
+	case schemas.Trade:
+		addTrades([]schemas.Trade{v})
+	case []schemas.Trade:
+		addTrades(v)
+	case [][]schemas.Trade:
+		for _, t := range v {
+			addTrades(t)
+		}
+	case schemas.Candle:
+		addCandles([]schemas.Candle{v})
+	case []schemas.Candle:
+		addCandles(v)
+	case [][]schemas.Candle:
+		for _, c := range v {
+			addCandles(c)
+		}
+	}
+	return
+}
@@ -0,0 +1,256 @@
+/*
+Package recorder writes market data subscriptions to journal:
+append-only gzip files with JSON line per symbol message, rotated by size and age
+*/
+package recorder
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+const (
+	defaultPrefix        = "market"
+	defaultMaxSize       = 100 << 20
+	defaultMaxAge        = time.Hour
+	defaultFlushInterval = time.Second
+
+	fileExtension  = ".jsonl.gz"
+	fileTimeFormat = "20060102-150405.000000"
+)
+
+// ErrClosed - recorder is closed
+var ErrClosed = errors.New("Recorder is closed")
+
+// Options - journal files options, zero values are replaced with defaults
+type Options struct {
+	Prefix        string        // file name prefix, "market" by default
+	MaxSize       int64         // uncompressed bytes of file before rotation, 100 MB by default
+	MaxAge        time.Duration // file age before rotation, 1 hour by default
+	FlushInterval time.Duration // compressed data is flushed to disk with interval, 1 second by default
+}
+
+// Recorder - writing subscriptions messages to journal files in directory
+type Recorder struct {
+	dir  string
+	opts Options
+
+	file    *os.File
+	gz      *gzip.Writer
+	buf     *bufio.Writer
+	size    int64
+	opened  time.Time
+	closed  bool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	written int64
+
+	sync.Mutex
+}
+
+// New - Recorder constructor, directory is created if it doesn't exist
+func New(dir string, opts Options) (*Recorder, error) {
+	if opts.Prefix == "" {
+		opts.Prefix = defaultPrefix
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = defaultMaxSize
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = defaultMaxAge
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultFlushInterval
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	r := &Recorder{
+		dir:  dir,
+		opts: opts,
+		stop: make(chan struct{}),
+	}
+	go r.flushing()
+	return r, nil
+}
+
+/*
+Record - writing messages of subscription channel until it's closed or recorder is closed.
+Kind is used for messages without data, i.e. errors
+*/
+func (r *Recorder) Record(exchange, kind string, ch chan schemas.ResultChannel) {
+	r.Lock()
+	defer r.Unlock()
+	if r.closed {
+		return
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		for {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				if err := r.Write(exchange, kind, msg); err != nil {
+					if err == ErrClosed {
+						return
+					}
+					log.Println("[RECORDER] Error writing journal:", err)
+				}
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// RecordBooks - subscribing to order books of symbols and recording them
+func (r *Recorder) RecordBooks(exchange string, provider schemas.OrdersProvider, symbols []schemas.Symbol, d time.Duration) {
+	r.Record(exchange, KindBooks, provider.SetSymbols(symbols).SubscribeAll(d))
+}
+
+// RecordTrades - subscribing to public trades of symbols and recording them
+func (r *Recorder) RecordTrades(exchange string, provider schemas.TradesProvider, symbols []schemas.Symbol, d time.Duration) {
+	r.Record(exchange, KindTrades, provider.SetSymbols(symbols).SubscribeAll(d))
+}
+
+// RecordQuotes - subscribing to quotes of symbols and recording them
+func (r *Recorder) RecordQuotes(exchange string, provider schemas.QuotesProvider, symbols []schemas.Symbol, d time.Duration) {
+	r.Record(exchange, KindQuotes, provider.SetSymbols(symbols).SubscribeAll(d))
+}
+
+// RecordCandles - subscribing to candles of symbols and recording them
+func (r *Recorder) RecordCandles(exchange string, provider schemas.CandlesProvider, symbols []schemas.Symbol, d time.Duration) {
+	r.Record(exchange, KindCandles, provider.SetSymbols(symbols).SubscribeAll(d))
+}
+
+// Write - writing message received now, it's split into records by symbol
+func (r *Recorder) Write(exchange, kind string, msg schemas.ResultChannel) error {
+	return r.WriteRecords(split(time.Now(), exchange, kind, msg)...)
+}
+
+// WriteRecords - writing records as they are, file is rotated before record if it's full or old
+func (r *Recorder) WriteRecords(records ...Record) error {
+	r.Lock()
+	defer r.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	for _, rec := range records {
+		b, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		b = append(b, '\n')
+		if r.file == nil || r.size+int64(len(b)) > r.opts.MaxSize || time.Since(r.opened) >= r.opts.MaxAge {
+			if err = r.rotate(); err != nil {
+				return err
+			}
+		}
+		if _, err = r.buf.Write(b); err != nil {
+			return err
+		}
+		r.size += int64(len(b))
+		r.written++
+	}
+	return nil
+}
+
+// Written - records count written since start
+func (r *Recorder) Written() int64 {
+	r.Lock()
+	defer r.Unlock()
+	return r.written
+}
+
+// Close - stopping recording and closing current file
+func (r *Recorder) Close() error {
+	r.Lock()
+	if r.closed {
+		r.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.stop)
+	r.Unlock()
+	r.wg.Wait()
+
+	r.Lock()
+	defer r.Unlock()
+	return r.closeFile()
+}
+
+// rotate - closing current file and opening new one, has to be called under lock
+func (r *Recorder) rotate() error {
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+	now := time.Now()
+	name := filepath.Join(r.dir, fmt.Sprintf("%s-%s%s", r.opts.Prefix, now.UTC().Format(fileTimeFormat), fileExtension))
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	r.file = f
+	r.gz = gzip.NewWriter(f)
+	r.buf = bufio.NewWriter(r.gz)
+	r.size = 0
+	r.opened = now
+	return nil
+}
+
+// closeFile - has to be called under lock
+func (r *Recorder) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+	if err := r.buf.Flush(); err != nil {
+		return err
+	}
+	if err := r.gz.Close(); err != nil {
+		return err
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// flushing - flushing compressed data with interval, so journal can be read while it's written
+func (r *Recorder) flushing() {
+	ticker := time.NewTicker(r.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.Lock()
+			if r.file != nil {
+				if err := r.flush(); err != nil {
+					log.Println("[RECORDER] Error flushing journal:", err)
+				}
+			}
+			r.Unlock()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// flush - has to be called under lock
+func (r *Recorder) flush() error {
+	if err := r.buf.Flush(); err != nil {
+		return err
+	}
+	return r.gz.Flush()
+}
@@ -0,0 +1,354 @@
+package replay
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/syndicatedb/goex/recorder"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+const (
+	subscriptionBufferSize = 100
+	// tradesLimit - last public trades of symbol returned by Get
+	tradesLimit = 100
+	// candlesLimit - last candles of symbol returned by Get
+	candlesLimit = 1000
+)
+
+// ErrNoData - there is no replayed data of symbol yet
+var ErrNoData = errors.New("No replayed data of symbol")
+
+/*
+subscriber - subscription channel and its symbols.
+Subscription without symbols gets every symbol except removed ones, names aren't checked then,
+so removing last name never turns symbols subscription into every symbol one
+*/
+type subscriber struct {
+	names   map[string]bool
+	every   bool            // subscription without symbols
+	removed map[string]bool // symbols removed from every symbol subscription
+	all     bool            // SubscribeAll subscription, it follows AddSymbols and RemoveSymbols
+	ch      chan schemas.ResultChannel
+	// snapshots of unbuffered subscription, they are delivered before its next record
+	pending []schemas.ResultChannel
+}
+
+// delivery - record message of subscription with its pending snapshots to be sent first
+type delivery struct {
+	ch      chan schemas.ResultChannel
+	pending []schemas.ResultChannel
+}
+
+func (s *subscriber) matches(symbol string) bool {
+	if s.every {
+		return !s.removed[symbol]
+	}
+	return s.names[symbol]
+}
+
+/*
+market - replayed data of one kind of exchange: last state of every symbol
+and subscriptions data is delivered to
+*/
+type market struct {
+	kind        string
+	buffer      int // subscriptions buffer, unbuffered ones are read by every record
+	symbols     []schemas.Symbol
+	subscribers []*subscriber
+
+	books   map[string]*bookState
+	trades  map[string][]schemas.Trade
+	quotes  map[string]schemas.Quote
+	candles map[string][]schemas.Candle
+
+	sync.Mutex
+}
+
+func newMarket(kind string, buffer int) *market {
+	return &market{
+		kind:    kind,
+		buffer:  buffer,
+		books:   make(map[string]*bookState),
+		trades:  make(map[string][]schemas.Trade),
+		quotes:  make(map[string]schemas.Quote),
+		candles: make(map[string][]schemas.Candle),
+	}
+}
+
+// AddSymbols - adding symbols to SubscribeAll subscriptions
+func (m *market) AddSymbols(symbols []schemas.Symbol) {
+	m.Lock()
+	defer m.Unlock()
+	m.symbols = append(m.symbols, symbols...)
+	for _, s := range m.subscribers {
+		if !s.all {
+			continue
+		}
+		for name := range symbolNames(symbols) {
+			s.names[name] = true
+			delete(s.removed, name)
+		}
+	}
+}
+
+// RemoveSymbols - removing symbols from SubscribeAll subscriptions
+func (m *market) RemoveSymbols(symbols []schemas.Symbol) {
+	m.Lock()
+	defer m.Unlock()
+	removed := symbolNames(symbols)
+	var kept []schemas.Symbol
+	for _, s := range m.symbols {
+		if !removed[s.Name] {
+			kept = append(kept, s)
+		}
+	}
+	m.symbols = kept
+	for _, s := range m.subscribers {
+		if !s.all {
+			continue
+		}
+		for name := range removed {
+			delete(s.names, name)
+			s.removed[name] = true
+		}
+	}
+}
+
+func (m *market) setSymbols(symbols []schemas.Symbol) {
+	m.Lock()
+	defer m.Unlock()
+	m.symbols = symbols
+}
+
+/*
+subscribe - channel of data of symbols, all symbols if empty.
+Last state of symbols is sent as snapshot first, so late subscription gets it.
+Snapshots of unbuffered subscription are delivered before its first record
+*/
+func (m *market) subscribe(symbols []schemas.Symbol, all bool) chan schemas.ResultChannel {
+	m.Lock()
+	defer m.Unlock()
+	if all {
+		symbols = m.symbols
+	}
+	s := &subscriber{
+		names:   symbolNames(symbols),
+		every:   len(symbols) == 0,
+		removed: make(map[string]bool),
+		all:     all,
+		ch:      make(chan schemas.ResultChannel, m.buffer),
+	}
+	m.subscribers = append(m.subscribers, s)
+	for _, name := range m.stateSymbols() {
+		if !s.matches(name) {
+			continue
+		}
+		msg, ok := m.snapshot(name)
+		switch {
+		case !ok:
+		case m.buffer == 0:
+			s.pending = append(s.pending, msg)
+		case len(s.ch) < cap(s.ch):
+			s.ch <- msg
+		}
+	}
+	return s.ch
+}
+
+/*
+apply - updating symbol state by record and returning subscriptions it has to be delivered to,
+nothing if record isn't delivered
+*/
+func (m *market) apply(rec recorder.Record, deliver bool) (deliveries []delivery) {
+	m.Lock()
+	defer m.Unlock()
+	if rec.Error == "" {
+		m.update(rec)
+	}
+	if !deliver {
+		return
+	}
+	for _, s := range m.subscribers {
+		if rec.Symbol == "" || s.matches(rec.Symbol) {
+			deliveries = append(deliveries, delivery{ch: s.ch, pending: s.pending})
+			s.pending = nil
+		}
+	}
+	return
+}
+
+// close - closing subscriptions when replay is over
+func (m *market) close() {
+	m.Lock()
+	defer m.Unlock()
+	for _, s := range m.subscribers {
+		close(s.ch)
+	}
+	m.subscribers = nil
+}
+
+// update - has to be called under lock
+func (m *market) update(rec recorder.Record) {
+	switch rec.Kind {
+	case recorder.KindBooks:
+		if rec.Book == nil {
+			return
+		}
+		b := m.books[rec.Symbol]
+		if b == nil || rec.DataType == schemas.DataTypeSnapshot {
+			b = newBookState()
+			m.books[rec.Symbol] = b
+		}
+		b.apply(*rec.Book)
+	case recorder.KindTrades:
+		trades := append(m.trades[rec.Symbol], rec.Trades...)
+		if len(trades) > tradesLimit {
+			trades = trades[len(trades)-tradesLimit:]
+		}
+		m.trades[rec.Symbol] = trades
+	case recorder.KindQuotes:
+		if rec.Quote != nil {
+			m.quotes[rec.Symbol] = *rec.Quote
+		}
+	case recorder.KindCandles:
+		candles := m.candles[rec.Symbol]
+		for _, c := range rec.Candles {
+			if n := len(candles); n > 0 && candles[n-1].Timestamp == c.Timestamp {
+				candles[n-1] = c
+				continue
+			}
+			candles = append(candles, c)
+		}
+		if len(candles) > candlesLimit {
+			candles = candles[len(candles)-candlesLimit:]
+		}
+		m.candles[rec.Symbol] = candles
+	}
+}
+
+// snapshot - last state of symbol as subscription message, has to be called under lock
+func (m *market) snapshot(name string) (msg schemas.ResultChannel, ok bool) {
+	msg.DataType = schemas.DataTypeSnapshot
+	switch m.kind {
+	case recorder.KindBooks:
+		var b *bookState
+		if b, ok = m.books[name]; ok {
+			msg.Data = b.orderBook(name)
+		}
+	case recorder.KindTrades:
+		var trades []schemas.Trade
+		if trades, ok = m.trades[name]; ok {
+			msg.Data = copyTrades(trades)
+		}
+	case recorder.KindQuotes:
+		msg.Data, ok = m.quotes[name]
+	case recorder.KindCandles:
+		var candles []schemas.Candle
+		if candles, ok = m.candles[name]; ok {
+			msg.Data = copyCandles(candles)
+		}
+	}
+	return
+}
+
+// stateSymbols - symbols with state, sorted so snapshots are sent in the same order every replay
+func (m *market) stateSymbols() (names []string) {
+	switch m.kind {
+	case recorder.KindBooks:
+		for name := range m.books {
+			names = append(names, name)
+		}
+	case recorder.KindTrades:
+		for name := range m.trades {
+			names = append(names, name)
+		}
+	case recorder.KindQuotes:
+		for name := range m.quotes {
+			names = append(names, name)
+		}
+	case recorder.KindCandles:
+		for name := range m.candles {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// find - state of symbol by common or original name
+func (m *market) find(symbol schemas.Symbol) (msg schemas.ResultChannel, ok bool) {
+	m.Lock()
+	defer m.Unlock()
+	if msg, ok = m.snapshot(symbol.Name); ok {
+		return
+	}
+	return m.snapshot(symbol.OriginalName)
+}
+
+// bookState - order book levels by price
+type bookState struct {
+	buy  map[float64]float64
+	sell map[float64]float64
+}
+
+func newBookState() *bookState {
+	return &bookState{
+		buy:  make(map[float64]float64),
+		sell: make(map[float64]float64),
+	}
+}
+
+func (b *bookState) apply(book schemas.OrderBook) {
+	schemas.ApplyLevels(b.buy, book.Buy)
+	schemas.ApplyLevels(b.sell, book.Sell)
+}
+
+// orderBook - levels as order book: highest bids and lowest asks first
+func (b *bookState) orderBook(symbol string) schemas.OrderBook {
+	book := schemas.OrderBook{
+		Symbol: symbol,
+		Buy:    levelsOrders(symbol, b.buy),
+		Sell:   levelsOrders(symbol, b.sell),
+	}
+	sort.Slice(book.Buy, func(i, j int) bool { return book.Buy[i].Price > book.Buy[j].Price })
+	sort.Slice(book.Sell, func(i, j int) bool { return book.Sell[i].Price < book.Sell[j].Price })
+	return book
+}
+
+func levelsOrders(symbol string, levels map[float64]float64) []schemas.Order {
+	orders := make([]schemas.Order, 0, len(levels))
+	for price, amount := range levels {
+		orders = append(orders, schemas.Order{
+			Symbol: symbol,
+			Price:  price,
+			Amount: amount,
+		})
+	}
+	return orders
+}
+
+// symbolNames - common and original names of symbols
+func symbolNames(symbols []schemas.Symbol) map[string]bool {
+	names := make(map[string]bool)
+	for _, s := range symbols {
+		names[s.Name] = true
+		if s.OriginalName != "" {
+			names[s.OriginalName] = true
+		}
+	}
+	return names
+}
+
+func copyTrades(trades []schemas.Trade) []schemas.Trade {
+	result := make([]schemas.Trade, len(trades))
+	copy(result, trades)
+	return result
+}
+
+func copyCandles(candles []schemas.Candle) []schemas.Candle {
+	result := make([]schemas.Candle, len(candles))
+	copy(result, candles)
+	return result
+}
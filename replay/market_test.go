@@ -0,0 +1,73 @@
+package replay
+
+import (
+	"testing"
+
+	"github.com/syndicatedb/goex/recorder"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+func TestMarketSubscribers(t *testing.T) {
+	eth := schemas.Symbol{Name: "ETH-BTC"}
+	ltc := schemas.Symbol{Name: "LTC-BTC"}
+	tests := []struct {
+		name    string
+		symbols []schemas.Symbol // market symbols
+		steps   func(m *market) chan schemas.ResultChannel
+		want    map[string]bool // delivered by symbol
+	}{
+		{
+			name:    "removing last symbol of SubscribeAll stops its data",
+			symbols: []schemas.Symbol{eth},
+			steps: func(m *market) chan schemas.ResultChannel {
+				ch := m.subscribe(nil, true)
+				m.RemoveSymbols([]schemas.Symbol{eth})
+				return ch
+			},
+			want: map[string]bool{"ETH-BTC": false, "LTC-BTC": false},
+		},
+		{
+			name: "subscription without symbols skips removed ones",
+			steps: func(m *market) chan schemas.ResultChannel {
+				ch := m.subscribe(nil, true)
+				m.RemoveSymbols([]schemas.Symbol{eth})
+				return ch
+			},
+			want: map[string]bool{"ETH-BTC": false, "LTC-BTC": true},
+		},
+		{
+			name:    "added symbol is delivered to SubscribeAll",
+			symbols: []schemas.Symbol{eth},
+			steps: func(m *market) chan schemas.ResultChannel {
+				ch := m.subscribe(nil, true)
+				m.AddSymbols([]schemas.Symbol{ltc})
+				return ch
+			},
+			want: map[string]bool{"ETH-BTC": true, "LTC-BTC": true},
+		},
+		{
+			name:    "symbols subscription doesn't follow added symbols",
+			symbols: []schemas.Symbol{eth},
+			steps: func(m *market) chan schemas.ResultChannel {
+				ch := m.subscribe([]schemas.Symbol{eth}, false)
+				m.AddSymbols([]schemas.Symbol{ltc})
+				return ch
+			},
+			want: map[string]bool{"ETH-BTC": true, "LTC-BTC": false},
+		},
+	}
+	for _, tt := range tests {
+		m := newMarket(recorder.KindTrades, subscriptionBufferSize)
+		m.setSymbols(tt.symbols)
+		ch := tt.steps(m)
+		for symbol, want := range tt.want {
+			delivered := false
+			for _, d := range m.apply(recorder.Record{Symbol: symbol, Error: "skipped"}, true) {
+				delivered = delivered || d.ch == ch
+			}
+			if delivered != want {
+				t.Errorf("%s: %s delivered = %v, want %v", tt.name, symbol, delivered, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,334 @@
+/*
+Package replay plays recorded market data journal through exchange providers interfaces,
+so code written against goex API runs on history. Trading is paper trading filled by replayed data
+*/
+package replay
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/exchanges/paper"
+	"github.com/syndicatedb/goex/recorder"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// Replay speeds
+const (
+	AsFastAsPossible = 0
+	RealTime         = 1
+)
+
+// Source - records in time order, io.EOF after last one. *recorder.Reader is journal source
+type Source interface {
+	Next() (recorder.Record, error)
+}
+
+// Options - replay options
+type Options struct {
+	// 1 is real time, 10 is ten times faster, 0 is as fast as subscribers read data:
+	// subscriptions are unbuffered then, so next record is played when previous one is received
+	Speed float64
+	// as fast as possible replay waits for Ack after every record delivered to subscriptions,
+	// so orders created by reaction to record are sent before next one is played
+	Acknowledge bool
+	// records before Since build state (i.e. order books) without delivering, records after Until end replay
+	Since, Until time.Time
+	// exchange symbols with fees and filters by exchange, names seen in journal are used if not set
+	Symbols map[string][]schemas.Symbol
+	// paper trading options by exchange, latency is counted by replay time
+	Paper map[string]schemas.PaperOptions
+}
+
+// Exchange - replayed exchange, implements goex API
+type Exchange struct {
+	schemas.Exchange
+	player  *Player
+	name    string
+	symbols *SymbolsProvider
+	books   *OrdersProvider
+	trades  *TradesProvider
+	quotes  *QuotesProvider
+	candles *CandlesProvider
+	paper   *paper.TradingProvider
+}
+
+// Paper - paper trading provider of exchange
+func (ex *Exchange) Paper() *paper.TradingProvider {
+	return ex.paper
+}
+
+// Ack - acknowledging received record is handled, see Player.Ack
+func (ex *Exchange) Ack() {
+	ex.player.Ack()
+}
+
+// kindMarket - market of record kind
+func (ex *Exchange) kindMarket(kind string) *market {
+	switch kind {
+	case recorder.KindBooks:
+		return ex.books.market
+	case recorder.KindTrades:
+		return ex.trades.market
+	case recorder.KindQuotes:
+		return ex.quotes.market
+	case recorder.KindCandles:
+		return ex.candles.market
+	}
+	return nil
+}
+
+/*
+Player - replaying source records in their time order.
+Subscriptions get records of their symbols, delivering blocks while subscription buffer is full,
+so every subscription has to be read. As fast as possible replay is played in lock-step:
+subscriptions are unbuffered and next record is played when delivered one is received,
+or acknowledged if Acknowledge is set. Subscriptions are closed when replay is over
+*/
+type Player struct {
+	source    Source
+	opts      Options
+	exchanges map[string]*Exchange
+	names     []string
+	onRecord  func(rec recorder.Record)
+
+	now     time.Time
+	started bool
+	err     error
+	acks    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+
+	sync.Mutex
+}
+
+// NewPlayer - Player constructor
+func NewPlayer(source Source, opts Options) *Player {
+	return &Player{
+		source:    source,
+		opts:      opts,
+		exchanges: make(map[string]*Exchange),
+		acks:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Exchange - replayed exchange by name, it has to be taken before replay is started to get all data
+func (p *Player) Exchange(name string) *Exchange {
+	p.Lock()
+	defer p.Unlock()
+	if ex, ok := p.exchanges[name]; ok {
+		return ex
+	}
+	buffer := subscriptionBufferSize
+	if p.lockStep() {
+		buffer = 0
+	}
+	ex := &Exchange{
+		player:  p,
+		name:    name,
+		symbols: newSymbolsProvider(p.opts.Symbols[name]),
+		books:   &OrdersProvider{newMarket(recorder.KindBooks, buffer)},
+		trades:  &TradesProvider{newMarket(recorder.KindTrades, buffer)},
+		quotes:  &QuotesProvider{newMarket(recorder.KindQuotes, buffer)},
+		candles: &CandlesProvider{newMarket(recorder.KindCandles, buffer)},
+		paper:   paper.NewTradingProvider(p.opts.Paper[name], nil).SetSymbols(p.opts.Symbols[name]).SetClock(p.Now),
+	}
+	ex.Exchange = schemas.Exchange{
+		Symbol:  ex.symbols,
+		Orders:  ex.books,
+		Trades:  ex.trades,
+		Quotes:  ex.quotes,
+		Candles: ex.candles,
+		Trading: ex.paper,
+	}
+	p.exchanges[name] = ex
+	p.names = append(p.names, name)
+	return ex
+}
+
+/*
+OnRecord - setting function called after every played record of taken exchanges is delivered,
+i.e. to sample balances. It has to be set before replay is started
+*/
+func (p *Player) OnRecord(fn func(rec recorder.Record)) {
+	p.Lock()
+	defer p.Unlock()
+	p.onRecord = fn
+}
+
+/*
+Ack - acknowledging last record delivered to subscriptions is handled, once by record.
+It's used with Acknowledge option, next record is played after it
+*/
+func (p *Player) Ack() {
+	select {
+	case p.acks <- struct{}{}:
+	default:
+	}
+}
+
+// lockStep - as fast as possible replay, it's paced by subscribers
+func (p *Player) lockStep() bool {
+	return p.opts.Speed <= 0
+}
+
+// Now - replay time: time of last played record
+func (p *Player) Now() time.Time {
+	p.Lock()
+	defer p.Unlock()
+	return p.now
+}
+
+// Start - replaying in background
+func (p *Player) Start() {
+	go p.Run()
+}
+
+/*
+Run - replaying until source is over, Until is reached or player is stopped.
+Records of exchanges which weren't taken are skipped
+*/
+func (p *Player) Run() error {
+	p.Lock()
+	if p.started {
+		p.Unlock()
+		return nil
+	}
+	p.started = true
+	p.Unlock()
+
+	err := p.play()
+	p.Lock()
+	p.err = err
+	exchanges := make([]*Exchange, 0, len(p.names))
+	for _, name := range p.names {
+		exchanges = append(exchanges, p.exchanges[name])
+	}
+	p.Unlock()
+	for _, ex := range exchanges {
+		for _, m := range []*market{ex.books.market, ex.trades.market, ex.quotes.market, ex.candles.market} {
+			m.close()
+		}
+	}
+	close(p.done)
+	return err
+}
+
+// Stop - stopping replay, subscriptions are closed
+func (p *Player) Stop() {
+	p.Lock()
+	defer p.Unlock()
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+}
+
+// Done - closed when replay is over
+func (p *Player) Done() chan struct{} {
+	return p.done
+}
+
+// Err - error of reading source, nil if it was read to the end
+func (p *Player) Err() error {
+	p.Lock()
+	defer p.Unlock()
+	return p.err
+}
+
+func (p *Player) play() error {
+	var first time.Time
+	started := time.Now()
+	for {
+		select {
+		case <-p.stop:
+			return nil
+		default:
+		}
+		rec, err := p.source.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		at := rec.At()
+		if !p.opts.Until.IsZero() && at.After(p.opts.Until) {
+			return nil
+		}
+		deliver := p.opts.Since.IsZero() || !at.Before(p.opts.Since)
+		if deliver && p.opts.Speed > 0 {
+			if first.IsZero() {
+				first = at
+				started = time.Now()
+			}
+			wait := time.Duration(float64(at.Sub(first))/p.opts.Speed) - time.Since(started)
+			if wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-p.stop:
+					return nil
+				}
+			}
+		}
+
+		p.Lock()
+		if at.After(p.now) {
+			p.now = at
+		}
+		ex := p.exchanges[rec.Exchange]
+		onRecord := p.onRecord
+		p.Unlock()
+		if ex == nil {
+			continue
+		}
+		p.apply(ex, rec, deliver)
+		if onRecord != nil {
+			onRecord(rec)
+		}
+	}
+}
+
+/*
+apply - filling paper orders by record, then updating state and delivering it to subscriptions,
+so orders created by reaction to record are filled by next records only
+*/
+func (p *Player) apply(ex *Exchange, rec recorder.Record, deliver bool) {
+	m := ex.kindMarket(rec.Kind)
+	if m == nil {
+		return
+	}
+	symbol, added := ex.symbols.symbol(rec.Symbol)
+	if added {
+		// paper orders are validated by symbols seen until now when symbols are not configured
+		symbols, _ := ex.symbols.Get()
+		ex.paper.SetSymbols(symbols)
+	}
+	msg := rec.Message()
+	switch rec.Kind {
+	case recorder.KindBooks:
+		ex.paper.ApplyBook(symbol, msg)
+	case recorder.KindTrades:
+		ex.paper.ApplyTrades(symbol, msg)
+	}
+	deliveries := m.apply(rec, deliver)
+	for _, d := range deliveries {
+		for _, out := range append(d.pending, msg) {
+			select {
+			case d.ch <- out:
+			case <-p.stop:
+				return
+			}
+		}
+	}
+	if len(deliveries) > 0 && p.lockStep() && p.opts.Acknowledge {
+		select {
+		case <-p.acks:
+		case <-p.stop:
+		}
+	}
+}
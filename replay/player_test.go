@@ -0,0 +1,61 @@
+package replay
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/syndicatedb/goex/recorder"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// records - source of records
+type records []recorder.Record
+
+func (r *records) Next() (rec recorder.Record, err error) {
+	if len(*r) == 0 {
+		return rec, io.EOF
+	}
+	rec, *r = (*r)[0], (*r)[1:]
+	return rec, nil
+}
+
+func trades(at int64, price float64) recorder.Record {
+	return recorder.Record{
+		Time:     at,
+		Exchange: "binance",
+		Kind:     recorder.KindTrades,
+		Symbol:   "ETH-BTC",
+		Trades:   []schemas.Trade{{Symbol: "ETH-BTC", Price: price, Amount: 1}},
+	}
+}
+
+func TestPlayerLockStep(t *testing.T) {
+	source := &records{trades(1, 1), trades(2, 2)}
+	p := NewPlayer(source, Options{Acknowledge: true})
+	ex := p.Exchange("binance")
+	ch := ex.TradesProvider().Subscribe(schemas.Symbol{Name: "ETH-BTC"}, time.Second)
+	p.Start()
+
+	for _, at := range []int64{1, 2} {
+		select {
+		case msg := <-ch:
+			if trades := msg.Data.([]schemas.Trade); len(trades) != 1 || trades[0].Price != float64(at) {
+				t.Fatalf("record %d: data = %+v", at, msg.Data)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("record %d isn't delivered", at)
+		}
+		// player waits for acknowledge, so its clock stays at received record
+		time.Sleep(10 * time.Millisecond)
+		if now := p.Now(); now.UnixNano() != at {
+			t.Errorf("record %d: replay time = %d", at, now.UnixNano())
+		}
+		ex.Ack()
+	}
+	select {
+	case <-p.Done():
+	case <-time.After(time.Second):
+		t.Fatal("replay isn't over")
+	}
+}
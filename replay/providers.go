@@ -0,0 +1,205 @@
+package replay
+
+import (
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex/internal/listing"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// OrdersProvider - replayed order books, Get returns book at current replay time
+type OrdersProvider struct {
+	*market
+}
+
+// SetSymbols - setting symbols of SubscribeAll, all symbols if empty
+func (op *OrdersProvider) SetSymbols(symbols []schemas.Symbol) schemas.OrdersProvider {
+	op.setSymbols(symbols)
+	return op
+}
+
+// Get - order book of symbol at current replay time
+func (op *OrdersProvider) Get(symbol schemas.Symbol) (book schemas.OrderBook, err error) {
+	msg, ok := op.find(symbol)
+	if !ok {
+		return book, ErrNoData
+	}
+	return msg.Data.(schemas.OrderBook), nil
+}
+
+// Subscribe - replayed order book of symbol, interval is not used
+func (op *OrdersProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan schemas.ResultChannel {
+	return op.subscribe([]schemas.Symbol{symbol}, false)
+}
+
+// SubscribeAll - replayed order books of provider symbols, interval is not used
+func (op *OrdersProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return op.subscribe(nil, true)
+}
+
+// TradesProvider - replayed public trades
+type TradesProvider struct {
+	*market
+}
+
+// SetSymbols - setting symbols of SubscribeAll, all symbols if empty
+func (tp *TradesProvider) SetSymbols(symbols []schemas.Symbol) schemas.TradesProvider {
+	tp.setSymbols(symbols)
+	return tp
+}
+
+// Get - last trades of symbol before current replay time
+func (tp *TradesProvider) Get(symbol schemas.Symbol) (trades []schemas.Trade, err error) {
+	msg, ok := tp.find(symbol)
+	if !ok {
+		return nil, ErrNoData
+	}
+	return msg.Data.([]schemas.Trade), nil
+}
+
+// Subscribe - replayed trades of symbol, interval is not used
+func (tp *TradesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan schemas.ResultChannel {
+	return tp.subscribe([]schemas.Symbol{symbol}, false)
+}
+
+// SubscribeAll - replayed trades of provider symbols, interval is not used
+func (tp *TradesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return tp.subscribe(nil, true)
+}
+
+// QuotesProvider - replayed quotes
+type QuotesProvider struct {
+	*market
+}
+
+// SetSymbols - setting symbols of SubscribeAll, all symbols if empty
+func (qp *QuotesProvider) SetSymbols(symbols []schemas.Symbol) schemas.QuotesProvider {
+	qp.setSymbols(symbols)
+	return qp
+}
+
+// Get - last quote of symbol before current replay time
+func (qp *QuotesProvider) Get(symbol schemas.Symbol) (q schemas.Quote, err error) {
+	msg, ok := qp.find(symbol)
+	if !ok {
+		return q, ErrNoData
+	}
+	return msg.Data.(schemas.Quote), nil
+}
+
+// Subscribe - replayed quotes of symbol, interval is not used
+func (qp *QuotesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan schemas.ResultChannel {
+	return qp.subscribe([]schemas.Symbol{symbol}, false)
+}
+
+// SubscribeAll - replayed quotes of provider symbols, interval is not used
+func (qp *QuotesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return qp.subscribe(nil, true)
+}
+
+// CandlesProvider - replayed candles
+type CandlesProvider struct {
+	*market
+}
+
+// SetSymbols - setting symbols of SubscribeAll, all symbols if empty
+func (cp *CandlesProvider) SetSymbols(symbols []schemas.Symbol) schemas.CandlesProvider {
+	cp.setSymbols(symbols)
+	return cp
+}
+
+// Get - candles of symbol before current replay time
+func (cp *CandlesProvider) Get(symbol schemas.Symbol) ([]schemas.Candle, error) {
+	msg, ok := cp.find(symbol)
+	if !ok {
+		return nil, ErrNoData
+	}
+	return msg.Data.([]schemas.Candle), nil
+}
+
+// Subscribe - replayed candles of symbol, interval is not used
+func (cp *CandlesProvider) Subscribe(symbol schemas.Symbol, d time.Duration) chan schemas.ResultChannel {
+	return cp.subscribe([]schemas.Symbol{symbol}, false)
+}
+
+// SubscribeAll - replayed candles of provider symbols, interval is not used
+func (cp *CandlesProvider) SubscribeAll(d time.Duration) chan schemas.ResultChannel {
+	return cp.subscribe(nil, true)
+}
+
+/*
+SymbolsProvider - symbols set in player options,
+names seen in journal until now (without filters and fees) if they are not set
+*/
+type SymbolsProvider struct {
+	configured []schemas.Symbol
+	seen       []schemas.Symbol
+	known      map[string]bool
+
+	sync.Mutex
+}
+
+func newSymbolsProvider(symbols []schemas.Symbol) *SymbolsProvider {
+	sp := &SymbolsProvider{
+		configured: symbols,
+		known:      make(map[string]bool),
+	}
+	for name := range symbolNames(symbols) {
+		sp.known[name] = true
+	}
+	return sp
+}
+
+// Get - exchange symbols
+func (sp *SymbolsProvider) Get() (symbols []schemas.Symbol, err error) {
+	sp.Lock()
+	defer sp.Unlock()
+	if len(sp.configured) > 0 {
+		symbols = make([]schemas.Symbol, len(sp.configured))
+		copy(symbols, sp.configured)
+		return
+	}
+	symbols = make([]schemas.Symbol, len(sp.seen))
+	copy(symbols, sp.seen)
+	return
+}
+
+// Subscribe - symbols with interval of wall time
+func (sp *SymbolsProvider) Subscribe(d time.Duration) chan schemas.ResultChannel {
+	ch := make(chan schemas.ResultChannel)
+	go func() {
+		for {
+			symbols, err := sp.Get()
+			ch <- schemas.ResultChannel{
+				Data:  symbols,
+				Error: err,
+			}
+			time.Sleep(d)
+		}
+	}()
+	return ch
+}
+
+// SubscribeChanges - symbols changes, new names seen in journal are listings
+func (sp *SymbolsProvider) SubscribeChanges(d time.Duration) chan schemas.SymbolEventsChannel {
+	return listing.Watch(sp.Get, d)
+}
+
+// symbol - configured symbol by name, new name is added to seen symbols and added is true
+func (sp *SymbolsProvider) symbol(name string) (symbol schemas.Symbol, added bool) {
+	sp.Lock()
+	defer sp.Unlock()
+	for _, s := range sp.configured {
+		if s.Name == name || s.OriginalName == name {
+			return s, false
+		}
+	}
+	symbol = schemas.Symbol{Name: name, OriginalName: name}
+	if name != "" && !sp.known[name] {
+		sp.known[name] = true
+		sp.seen = append(sp.seen, symbol)
+		added = true
+	}
+	return
+}
@@ -0,0 +1,254 @@
+package router
+
+import (
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+const (
+	// tradesLimit - user trades loaded by exchange to find children fills
+	tradesLimit = 500
+	// clockSkew - trades of child are made after it has been sent, minus exchange clock difference
+	clockSkew = 10 * time.Second
+	// closeChecks - refreshes of closed child not filled by its trades, exchange can show its trades later
+	closeChecks = 3
+)
+
+// ChildStatus - fills of child order
+type ChildStatus struct {
+	Child
+	Filled       float64
+	AveragePrice float64 // average exchange price of fills, without fee
+	// order is in exchange open orders, or it's closed and fills are still checked
+	Open bool
+
+	checks int // refreshes of closed child not filled by trades
+}
+
+// Status - aggregate fills of order children
+type Status struct {
+	Amount       float64 // planned amount
+	Filled       float64
+	AveragePrice float64 // average exchange price of fills, without fee
+	Children     []ChildStatus
+	// all children are filled or closed
+	Done  bool
+	Error error
+}
+
+// Remaining - planned amount which is not filled yet
+func (s Status) Remaining() float64 {
+	return s.Amount - s.Filled
+}
+
+// Execution - sent order children and their fills
+type Execution struct {
+	manager *goex.Manager
+	plan    Plan
+	status  Status
+
+	sync.Mutex
+}
+
+func newExecution(manager *goex.Manager, plan Plan) *Execution {
+	e := &Execution{
+		manager: manager,
+		plan:    plan,
+	}
+	for _, child := range plan.Children {
+		if child.Error != nil {
+			continue
+		}
+		e.status.Amount += child.Order.Amount
+		e.status.Children = append(e.status.Children, ChildStatus{
+			Child: child,
+			Open:  true,
+		})
+	}
+	return e
+}
+
+// Plan - executed plan with children results
+func (e *Execution) Plan() Plan {
+	return e.plan
+}
+
+// Status - last loaded status
+func (e *Execution) Status() Status {
+	e.Lock()
+	defer e.Unlock()
+	return e.copyStatus()
+}
+
+/*
+Refresh - loading children fills from user trades by order ID,
+fill of open order is taken from open orders if trades are not loaded yet.
+Child which isn't open anymore is closed: filled or cancelled
+*/
+func (e *Execution) Refresh() (Status, error) {
+	e.Lock()
+	children := make([]ChildStatus, len(e.status.Children))
+	copy(children, e.status.Children)
+	e.Unlock()
+
+	byExchange := make(map[string][]int)
+	for i, child := range children {
+		if child.Open {
+			byExchange[child.Exchange] = append(byExchange[child.Exchange], i)
+		}
+	}
+	errs := make(goex.ExchangesError)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for exchange, idx := range byExchange {
+		wg.Add(1)
+		go func(exchange string, idx []int) {
+			defer wg.Done()
+			if err := e.refreshExchange(exchange, children, idx); err != nil {
+				mu.Lock()
+				errs[exchange] = err
+				mu.Unlock()
+			}
+		}(exchange, idx)
+	}
+	wg.Wait()
+
+	e.Lock()
+	defer e.Unlock()
+	e.status.Children = children
+	e.status.Filled = 0
+	e.status.AveragePrice = 0
+	e.status.Done = true
+	var cost float64
+	for _, child := range children {
+		e.status.Filled += child.Filled
+		cost += child.Filled * child.AveragePrice
+		if child.Open {
+			e.status.Done = false
+		}
+	}
+	if e.status.Filled > 0 {
+		e.status.AveragePrice = cost / e.status.Filled
+	}
+	e.status.Error = nil
+	if len(errs) > 0 {
+		e.status.Error = errs
+	}
+	return e.copyStatus(), e.status.Error
+}
+
+// refreshExchange - updating children of one exchange, they are changed in place by indexes
+func (e *Execution) refreshExchange(exchange string, children []ChildStatus, idx []int) error {
+	symbol, _ := e.manager.Symbol(exchange, e.plan.Order.Symbol)
+	trading := e.manager.Exchange(exchange).TradingProvider()
+
+	// exchanges take Since in different units, trades are filtered by time here
+	trades, _, err := trading.Trades(schemas.FilterOptions{
+		Symbols: []schemas.Symbol{symbol},
+		Limit:   tradesLimit,
+	})
+	if err != nil {
+		return err
+	}
+	orders, err := trading.Orders([]schemas.Symbol{symbol})
+	if err != nil {
+		return err
+	}
+	open := make(map[string]schemas.Order)
+	for _, o := range orders {
+		open[o.ID] = o
+	}
+	for _, i := range idx {
+		updateChild(&children[i], trades, open, symbol)
+	}
+	return nil
+}
+
+// updateChild - updating child fills by its trades and open order
+func updateChild(child *ChildStatus, trades []schemas.Trade, open map[string]schemas.Order, symbol schemas.Symbol) {
+	var filled, cost float64
+	for _, t := range trades {
+		if isChildTrade(t, child, symbol) {
+			filled += t.Amount
+			cost += t.Amount * t.Price
+		}
+	}
+	o, isOpen := open[child.Result.ID]
+	if isOpen && o.AmountFilled > filled {
+		cost += (o.AmountFilled - filled) * child.Order.Price
+		filled = o.AmountFilled
+	}
+	// fill known before is kept until trades show it
+	if filled >= child.Filled {
+		child.Filled = filled
+		child.AveragePrice = 0
+		if filled > 0 {
+			child.AveragePrice = cost / filled
+		}
+	}
+	child.Open = isOpen
+	if !isOpen && child.Filled < child.Order.Amount && child.checks < closeChecks {
+		// closed order can be filled by trades not shown yet, it's cancelled if they don't come
+		child.checks++
+		child.Open = true
+	}
+}
+
+// isChildTrade - trade of child order: by order ID, symbol and time since child has been sent
+func isChildTrade(t schemas.Trade, child *ChildStatus, symbol schemas.Symbol) bool {
+	if t.OrderID != child.Result.ID {
+		return false
+	}
+	if t.Symbol != "" && t.Symbol != symbol.Name && t.Symbol != symbol.OriginalName {
+		return false
+	}
+	return child.Sent.IsZero() || t.Time().After(child.Sent.Add(-clockSkew))
+}
+
+// Track - refreshing status with interval and sending it until execution is done
+func (e *Execution) Track(d time.Duration) chan Status {
+	ch := make(chan Status)
+	go func() {
+		defer close(ch)
+		for {
+			status, _ := e.Refresh()
+			ch <- status
+			if status.Done {
+				return
+			}
+			time.Sleep(d)
+		}
+	}()
+	return ch
+}
+
+// Cancel - cancelling open children, errors are returned by exchange
+func (e *Execution) Cancel() error {
+	errs := make(goex.ExchangesError)
+	for _, child := range e.Status().Children {
+		// closed children being checked aren't on exchange anymore
+		if !child.Open || child.checks > 0 {
+			continue
+		}
+		order := child.Order
+		order.ID = child.Result.ID
+		if err := e.manager.Exchange(child.Exchange).TradingProvider().Cancel(order); err != nil {
+			errs[child.Exchange] = err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// copyStatus - status with copy of children, has to be called under lock
+func (e *Execution) copyStatus() Status {
+	status := e.status
+	status.Children = make([]ChildStatus, len(e.status.Children))
+	copy(status.Children, e.status.Children)
+	return status
+}
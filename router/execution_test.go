@@ -0,0 +1,73 @@
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/syndicatedb/goex/schemas"
+)
+
+func TestUpdateChild(t *testing.T) {
+	sent := time.Unix(1500000000, 0)
+	symbol := schemas.Symbol{Name: "ETH-BTC", OriginalName: "ETHBTC"}
+	trade := func(orderID, symbol string, ts int64, amount float64) schemas.Trade {
+		return schemas.Trade{OrderID: orderID, Symbol: symbol, Timestamp: ts, Price: 0.01, Amount: amount}
+	}
+	tests := []struct {
+		name    string
+		trades  []schemas.Trade
+		open    map[string]schemas.Order
+		refresh int
+		filled  float64
+		isOpen  bool
+	}{
+		{
+			name:    "trades of child are summed",
+			trades:  []schemas.Trade{trade("1", "ETHBTC", 1500000001000, 1), trade("1", "ETH-BTC", 1500000002, 1)},
+			refresh: 1,
+			filled:  2,
+		},
+		{
+			name:    "trades of other symbol or before child is sent are skipped",
+			trades:  []schemas.Trade{trade("1", "LTC-BTC", 1500000001, 2), trade("1", "ETH-BTC", 1400000000, 2)},
+			refresh: 1,
+			isOpen:  true,
+		},
+		{
+			name:    "open order fill is taken when trades are missing",
+			open:    map[string]schemas.Order{"1": {ID: "1", AmountFilled: 0.5}},
+			refresh: 1,
+			filled:  0.5,
+			isOpen:  true,
+		},
+		{
+			name:    "closed child not filled by trades is checked again",
+			trades:  []schemas.Trade{trade("1", "ETH-BTC", 1500000001, 1)},
+			refresh: closeChecks,
+			filled:  1,
+			isOpen:  true,
+		},
+		{
+			name:    "closed child is closed after checks",
+			trades:  []schemas.Trade{trade("1", "ETH-BTC", 1500000001, 1)},
+			refresh: closeChecks + 1,
+			filled:  1,
+		},
+	}
+	for _, tt := range tests {
+		child := &ChildStatus{
+			Child: Child{
+				Order:  schemas.Order{Symbol: "ETHBTC", Price: 0.01, Amount: 2},
+				Result: schemas.Order{ID: "1"},
+				Sent:   sent,
+			},
+			Open: true,
+		}
+		for i := 0; i < tt.refresh; i++ {
+			updateChild(child, tt.trades, tt.open, symbol)
+		}
+		if child.Filled != tt.filled || child.Open != tt.isOpen {
+			t.Errorf("%s: filled = %v, open = %v, want %v, %v", tt.name, child.Filled, child.Open, tt.filled, tt.isOpen)
+		}
+	}
+}
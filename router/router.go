@@ -0,0 +1,255 @@
+/*
+Package router splits orders between manager exchanges by consolidated order book:
+cheapest levels after fees are taken first while exchange balances and symbol limits allow
+*/
+package router
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syndicatedb/goex"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+// ErrNoLiquidity - there are no levels, balances or exchanges to route order to
+var ErrNoLiquidity = errors.New("No liquidity to route order")
+
+// Order - parent order to be split between exchanges
+type Order struct {
+	Symbol string  // common symbol name, i.e. BTC-USDT
+	Side   string  // schemas.TypeBuy or schemas.TypeSell
+	Amount float64 // base coin amount
+	// worst exchange price, levels behind it are not taken. 0 takes any level
+	LimitPrice float64
+	// exchanges order can be sent to, all exchanges with credentials if empty
+	Exchanges []string
+}
+
+// Child - part of order sent to one exchange
+type Child struct {
+	Exchange string
+	// order as it's sent: exchange symbol name, price of worst taken level
+	Order schemas.Order
+	// average price of taken levels after fee
+	EffectivePrice float64
+
+	// exchange response and time order has been sent, filled by Execute
+	Result schemas.Order
+	Sent   time.Time
+	Error  error
+}
+
+// Plan - order split, children are sorted as exchanges in manager config
+type Plan struct {
+	Order    Order
+	Children []Child
+	// planned amount, less than order amount if liquidity or balances are not enough
+	Amount float64
+	// average price of planned amount after fees
+	EffectivePrice float64
+	// exchanges skipped because their part doesn't pass symbol limits
+	Rejected map[string]error
+}
+
+// Router - smart order router across manager exchanges
+type Router struct {
+	manager *goex.Manager
+	book    *goex.ConsolidatedBook
+}
+
+// New - Router constructor, book has to be subscribed to routed symbols
+func New(manager *goex.Manager, book *goex.ConsolidatedBook) *Router {
+	return &Router{
+		manager: manager,
+		book:    book,
+	}
+}
+
+// Route - planning order split and sending children orders
+func (r *Router) Route(order Order) (*Execution, error) {
+	plan, err := r.Plan(order)
+	if err != nil {
+		return nil, err
+	}
+	return r.Execute(plan)
+}
+
+/*
+Plan - splitting order by consolidated book levels, best effective price first.
+Part of exchange is limited by book amounts, available balance
+(quote coin for buying with fee, base coin for selling) and symbol max amount.
+Part which doesn't pass symbol filters (i.e. min notional) is dropped
+and its levels are taken from other exchanges.
+Balances of exchanges which failed to load are treated as empty
+*/
+func (r *Router) Plan(order Order) (plan Plan, err error) {
+	order.Side = strings.ToUpper(order.Side)
+	if order.Side != schemas.TypeBuy && order.Side != schemas.TypeSell {
+		err = fmt.Errorf("Invalid order side: %s", order.Side)
+		return
+	}
+	if order.Amount <= 0 {
+		err = fmt.Errorf("Invalid order amount: %v", order.Amount)
+		return
+	}
+	coins := strings.Split(strings.ToUpper(order.Symbol), "-")
+	if len(coins) != 2 {
+		err = fmt.Errorf("Invalid symbol: %s", order.Symbol)
+		return
+	}
+	balances, berr := r.manager.Balances()
+	if berr != nil {
+		log.Println("[ROUTER] Error loading balances:", berr)
+	}
+
+	book := r.book.Book(order.Symbol)
+	levels := book.Sell
+	coin := coins[1]
+	if order.Side == schemas.TypeSell {
+		levels = book.Buy
+		coin = coins[0]
+	}
+	available := make(map[string]float64)
+	for _, exchange := range r.venues(order) {
+		available[exchange] = balances[exchange][coin].Available
+	}
+
+	plan = Plan{
+		Order:    order,
+		Rejected: make(map[string]error),
+	}
+	for {
+		parts := r.allocate(order, levels, available)
+		plan.Children = plan.Children[:0]
+		rejected := false
+		for _, exchange := range r.manager.Exchanges() {
+			child, ok := parts[exchange]
+			if !ok {
+				continue
+			}
+			symbol, _ := r.manager.Symbol(exchange, order.Symbol)
+			if child.Order, err = symbol.ValidateOrder(child.Order); err != nil {
+				plan.Rejected[exchange] = err
+				delete(available, exchange)
+				rejected = true
+				continue
+			}
+			plan.Children = append(plan.Children, child)
+		}
+		err = nil
+		if !rejected {
+			break
+		}
+	}
+
+	var cost float64
+	for _, child := range plan.Children {
+		plan.Amount += child.Order.Amount
+		cost += child.Order.Amount * child.EffectivePrice
+	}
+	if plan.Amount == 0 {
+		err = ErrNoLiquidity
+		return
+	}
+	plan.EffectivePrice = cost / plan.Amount
+	return
+}
+
+// venues - exchanges with credentials order can be routed to
+func (r *Router) venues(order Order) (names []string) {
+	allowed := make(map[string]bool)
+	for _, name := range order.Exchanges {
+		allowed[name] = true
+	}
+	for _, name := range r.manager.TradingExchanges() {
+		if len(allowed) == 0 || allowed[name] {
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+// allocate - taking levels by effective price into exchange parts, exchanges without balance are skipped
+func (r *Router) allocate(order Order, levels []goex.BookLevel, available map[string]float64) map[string]Child {
+	parts := make(map[string]Child)
+	costs := make(map[string]float64)
+	remaining := order.Amount
+	for _, level := range levels {
+		if remaining <= 0 {
+			break
+		}
+		balance, ok := available[level.Exchange]
+		if !ok {
+			continue
+		}
+		// raw prices of exchanges with different fees are not sorted, so level is skipped, not loop
+		if order.LimitPrice > 0 && schemas.WorsePrice(order.Side, level.Price, order.LimitPrice) {
+			continue
+		}
+		child := parts[level.Exchange]
+		amount := math.Min(level.Amount, remaining)
+		if order.Side == schemas.TypeBuy {
+			// whole part is reserved by order price, which is price of last taken level
+			amount = math.Min(amount, balance/(level.Price*(1+level.Fee))-child.Order.Amount)
+		} else {
+			amount = math.Min(amount, balance-child.Order.Amount)
+		}
+		symbol, _ := r.manager.Symbol(level.Exchange, order.Symbol)
+		if symbol.MaxAmount > 0 {
+			amount = math.Min(amount, symbol.MaxAmount-child.Order.Amount)
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		child.Exchange = level.Exchange
+		child.Order.Symbol = symbol.Name
+		child.Order.Type = order.Side
+		child.Order.Price = level.Price
+		child.Order.Amount += amount
+		costs[level.Exchange] += amount * level.EffectivePrice
+		child.EffectivePrice = costs[level.Exchange] / child.Order.Amount
+		parts[level.Exchange] = child
+		remaining -= amount
+	}
+	return parts
+}
+
+/*
+Execute - sending plan children to exchanges in parallel.
+Execution is returned when at least one child is created,
+errors of children are kept in their Error field
+*/
+func (r *Router) Execute(plan Plan) (*Execution, error) {
+	var wg sync.WaitGroup
+	for i := range plan.Children {
+		wg.Add(1)
+		go func(child *Child) {
+			defer wg.Done()
+			api := r.manager.Exchange(child.Exchange)
+			child.Sent = time.Now()
+			child.Result, child.Error = api.TradingProvider().Create(child.Order)
+			if child.Error != nil {
+				log.Println("[ROUTER] Error creating order on", child.Exchange, child.Error)
+			}
+		}(&plan.Children[i])
+	}
+	wg.Wait()
+
+	errs := make(goex.ExchangesError)
+	for _, child := range plan.Children {
+		if child.Error != nil {
+			errs[child.Exchange] = child.Error
+		}
+	}
+	if len(errs) == len(plan.Children) {
+		return nil, errs
+	}
+	return newExecution(r.manager, plan), nil
+}
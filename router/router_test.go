@@ -0,0 +1,118 @@
+package router
+
+import (
+	"math"
+	"testing"
+
+	"github.com/syndicatedb/goex"
+	"github.com/syndicatedb/goex/schemas"
+)
+
+func level(exchange string, price, amount, fee float64, side string) goex.BookLevel {
+	effective := price * (1 + fee)
+	if side == schemas.TypeSell {
+		effective = price * (1 - fee)
+	}
+	return goex.BookLevel{Exchange: exchange, Price: price, Amount: amount, Fee: fee, EffectivePrice: effective}
+}
+
+func TestAllocate(t *testing.T) {
+	manager, err := goex.NewManager(goex.ManagerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := New(manager, goex.NewConsolidatedBook(manager))
+
+	buy := schemas.TypeBuy
+	sell := schemas.TypeSell
+	// asks by effective price: b is cheaper after fee than a with lower raw price
+	asks := []goex.BookLevel{
+		level("b", 100.05, 2, 0, buy),
+		level("a", 100, 1, 0.001, buy),
+		level("a", 101, 5, 0.001, buy),
+	}
+	bids := []goex.BookLevel{
+		level("b", 99.95, 2, 0, sell),
+		level("a", 100, 1, 0.001, sell),
+	}
+
+	type part struct {
+		amount, price, effective float64
+	}
+	tests := []struct {
+		name      string
+		order     Order
+		levels    []goex.BookLevel
+		available map[string]float64
+		want      map[string]part
+	}{
+		{
+			name:      "best effective price first",
+			order:     Order{Side: buy, Amount: 2.5},
+			levels:    asks,
+			available: map[string]float64{"a": 1000, "b": 1000},
+			want: map[string]part{
+				"b": {2, 100.05, 100.05},
+				"a": {0.5, 100, 100.1},
+			},
+		},
+		{
+			name:      "quote balance limits part",
+			order:     Order{Side: buy, Amount: 2.5},
+			levels:    asks,
+			available: map[string]float64{"a": 1000, "b": 100.05},
+			want: map[string]part{
+				"b": {1, 100.05, 100.05},
+				"a": {1.5, 101, (100.1 + 0.5*101.101) / 1.5},
+			},
+		},
+		{
+			name:      "limit price skips worse levels",
+			order:     Order{Side: buy, Amount: 2.5, LimitPrice: 100.02},
+			levels:    asks,
+			available: map[string]float64{"a": 1000, "b": 1000},
+			want: map[string]part{
+				"a": {1, 100, 100.1},
+			},
+		},
+		{
+			name:      "exchanges without balance are skipped",
+			order:     Order{Side: buy, Amount: 2.5},
+			levels:    asks,
+			available: map[string]float64{"a": 1000},
+			want: map[string]part{
+				"a": {2.5, 101, (100.1 + 1.5*101.101) / 2.5},
+			},
+		},
+		{
+			name:      "base balance limits selling",
+			order:     Order{Side: sell, Amount: 3},
+			levels:    bids,
+			available: map[string]float64{"a": 1, "b": 0.5},
+			want: map[string]part{
+				"b": {0.5, 99.95, 99.95},
+				"a": {1, 100, 99.9},
+			},
+		},
+	}
+	for _, tt := range tests {
+		parts := r.allocate(tt.order, tt.levels, tt.available)
+		if len(parts) != len(tt.want) {
+			t.Errorf("%s: parts = %+v, want %+v", tt.name, parts, tt.want)
+			continue
+		}
+		for exchange, want := range tt.want {
+			child := parts[exchange]
+			if child.Exchange != exchange || child.Order.Type != tt.order.Side {
+				t.Errorf("%s: %s child = %+v", tt.name, exchange, child)
+			}
+			if math.Abs(child.Order.Amount-want.amount) > 1e-9 ||
+				child.Order.Price != want.price ||
+				math.Abs(child.EffectivePrice-want.effective) > 1e-9 {
+				t.Errorf("%s: %s = %v @ %v (%v), want %v @ %v (%v)", tt.name, exchange,
+					child.Order.Amount, child.Order.Price, child.EffectivePrice,
+					want.amount, want.price, want.effective)
+			}
+		}
+	}
+}
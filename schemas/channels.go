@@ -26,4 +26,20 @@ type UserTradesChannel struct {
 	Data     []Trade
 	DataType string
 	Error    error
+	// set on ImportTrades pages with Acknowledge option: checkpoint of page is saved after Ack(nil),
+	// Ack with error cancels import. It's nil on other messages
+	Ack func(err error)
+}
+
+// SymbolEventsChannel - for symbols changes subscription
+type SymbolEventsChannel struct {
+	Data     []SymbolEvent
+	DataType string
+	Error    error
+}
+
+// ExchangeChannel - data of one of several exchanges
+type ExchangeChannel struct {
+	Exchange string
+	ResultChannel
 }
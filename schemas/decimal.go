@@ -0,0 +1,169 @@
+package schemas
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+Decimal - exact decimal number.
+It's kept as string exchange has sent, so precision is preserved
+from parsing until order is sent back to exchange.
+Empty decimal means value is not set and equals zero in arithmetic.
+*/
+type Decimal string
+
+// decimalRe - plain decimal notation, without exponent, fractions or other bases
+var decimalRe = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// ParseDecimal - checking string is decimal number in plain notation, i.e. "-0.001"
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if !decimalRe.MatchString(s) {
+		return "", fmt.Errorf("Invalid decimal: %q", s)
+	}
+	return Decimal(s), nil
+}
+
+// DecimalFromFloat - shortest decimal representation of float
+func DecimalFromFloat(f float64) Decimal {
+	return Decimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// UnmarshalJSON - decimal can be parsed from JSON string or number without loosing precision
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*d = ""
+		return nil
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// String - decimal string, zero for empty decimal
+func (d Decimal) String() string {
+	if d == "" {
+		return "0"
+	}
+	return string(d)
+}
+
+// IsSet - decimal has value
+func (d Decimal) IsSet() bool {
+	return d != ""
+}
+
+// Float64 - nearest float value
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// Sign - -1, 0 or 1
+func (d Decimal) Sign() int {
+	return d.rat().Sign()
+}
+
+// Cmp - comparing decimals: -1 if d < o, 0 if d == o, 1 if d > o
+func (d Decimal) Cmp(o Decimal) int {
+	return d.rat().Cmp(o.rat())
+}
+
+// Add - exact sum, result has max scale of arguments
+func (d Decimal) Add(o Decimal) Decimal {
+	return fromRat(new(big.Rat).Add(d.rat(), o.rat()), maxInt(d.scale(), o.scale()))
+}
+
+// Sub - exact difference, result has max scale of arguments
+func (d Decimal) Sub(o Decimal) Decimal {
+	return fromRat(new(big.Rat).Sub(d.rat(), o.rat()), maxInt(d.scale(), o.scale()))
+}
+
+// Mul - exact product, result scale is sum of arguments scales
+func (d Decimal) Mul(o Decimal) Decimal {
+	return fromRat(new(big.Rat).Mul(d.rat(), o.rat()), d.scale()+o.scale())
+}
+
+// Neg - negated decimal
+func (d Decimal) Neg() Decimal {
+	return fromRat(new(big.Rat).Neg(d.rat()), d.scale())
+}
+
+// RoundStep - rounding decimal to step multiple: up or down. Result has step scale
+func (d Decimal) RoundStep(step Decimal, up bool) Decimal {
+	s := step.rat()
+	if s.Sign() <= 0 {
+		return d
+	}
+	q := new(big.Rat).Quo(d.rat(), s)
+	// Euclidean division with positive denominator is floor
+	n := new(big.Int).Div(q.Num(), q.Denom())
+	if up && !q.IsInt() {
+		n.Add(n, big.NewInt(1))
+	}
+	return fromRat(new(big.Rat).Mul(new(big.Rat).SetInt(n), s), step.scale())
+}
+
+func (d Decimal) rat() *big.Rat {
+	if d == "" {
+		return new(big.Rat)
+	}
+	r, ok := new(big.Rat).SetString(string(d))
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// scale - count of decimal places
+func (d Decimal) scale() int {
+	s := string(d)
+	if strings.ContainsAny(s, "eE") {
+		return ratScale(d.rat())
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+// ratScale - decimal places of rational with 2^a*5^b denominator
+func ratScale(r *big.Rat) int {
+	var twos, fives int
+	mod := new(big.Int)
+	denom := new(big.Int).Set(r.Denom())
+	for _, f := range []struct {
+		n     int64
+		count *int
+	}{{2, &twos}, {5, &fives}} {
+		div := big.NewInt(f.n)
+		for denom.Cmp(big.NewInt(1)) > 0 {
+			q, m := new(big.Int).QuoRem(denom, div, mod)
+			if m.Sign() != 0 {
+				break
+			}
+			denom = q
+			*f.count++
+		}
+	}
+	return maxInt(twos, fives)
+}
+
+func fromRat(r *big.Rat, scale int) Decimal {
+	return Decimal(r.FloatString(scale))
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,136 @@
+package schemas
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  Decimal
+		valid bool
+	}{
+		{"0", "0", true},
+		{"0.00100000", "0.00100000", true},
+		{"-12.5", "-12.5", true},
+		{" 42 ", "42", true},
+		{"", "", false},
+		{"1/3", "", false},
+		{"0x10", "", false},
+		{"1e-8", "", false},
+		{"1.", "", false},
+		{".5", "", false},
+		{"+1", "", false},
+		{"1,5", "", false},
+		{"NaN", "", false},
+	}
+	for _, tt := range tests {
+		got, err := ParseDecimal(tt.in)
+		if (err == nil) != tt.valid {
+			t.Errorf("ParseDecimal(%q) error = %v, valid %v", tt.in, err, tt.valid)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDecimal(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  Decimal
+		valid bool
+	}{
+		{`"0.12345678901234567890"`, "0.12345678901234567890", true},
+		{`0.1`, "0.1", true},
+		{`null`, "", true},
+		{`""`, "", true},
+		{`"1e-8"`, "", false},
+		{`1e-8`, "", false},
+		{`"1/3"`, "", false},
+	}
+	for _, tt := range tests {
+		var d Decimal
+		err := json.Unmarshal([]byte(tt.in), &d)
+		if (err == nil) != tt.valid {
+			t.Errorf("Unmarshal(%s) error = %v, valid %v", tt.in, err, tt.valid)
+			continue
+		}
+		if d != tt.want {
+			t.Errorf("Unmarshal(%s) = %q, want %q", tt.in, d, tt.want)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Decimal
+		want Decimal
+	}{
+		{"add", Decimal("0.1").Add("0.2"), "0.3"},
+		{"add scale", Decimal("1.50").Add("2"), "3.50"},
+		{"add empty", Decimal("").Add("1.5"), "1.5"},
+		{"sub", Decimal("1").Sub("0.00000001"), "0.99999999"},
+		{"sub negative", Decimal("0.1").Sub("0.25"), "-0.15"},
+		{"mul", Decimal("0.1").Mul("0.3"), "0.03"},
+		{"mul scale", Decimal("1.10").Mul("2.0"), "2.200"},
+		{"neg", Decimal("0.5").Neg(), "-0.5"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestDecimalRoundStep(t *testing.T) {
+	tests := []struct {
+		d, step Decimal
+		up      bool
+		want    Decimal
+	}{
+		{"0.123456", "0.01", false, "0.12"},
+		{"0.123456", "0.01", true, "0.13"},
+		{"0.12", "0.01", true, "0.12"},
+		{"0.12", "0.01", false, "0.12"},
+		{"1234.5", "5", false, "1230"},
+		{"1234.5", "5", true, "1235"},
+		{"-0.125", "0.01", false, "-0.13"},
+		{"-0.125", "0.01", true, "-0.12"},
+		{"0.3", "0.1", false, "0.3"},
+		{"0.00000001", "0.00000001", false, "0.00000001"},
+		{"0.5", "0", false, "0.5"},
+		{"0.5", "", true, "0.5"},
+	}
+	for _, tt := range tests {
+		if got := tt.d.RoundStep(tt.step, tt.up); got != tt.want {
+			t.Errorf("%q.RoundStep(%q, %v) = %q, want %q", tt.d, tt.step, tt.up, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalCompare(t *testing.T) {
+	tests := []struct {
+		a, b Decimal
+		want int
+	}{
+		{"0.10", "0.1", 0},
+		{"", "0", 0},
+		{"0.09", "0.1", -1},
+		{"-1", "-2", 1},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Cmp(tt.b); got != tt.want {
+			t.Errorf("%q.Cmp(%q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if f := Decimal("0.001").Float64(); f != 0.001 {
+		t.Errorf("Float64 = %v, want 0.001", f)
+	}
+	if d := DecimalFromFloat(0.0001); d != "0.0001" {
+		t.Errorf("DecimalFromFloat = %q, want 0.0001", d)
+	}
+}
@@ -0,0 +1,31 @@
+package schemas
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ErrEmptySymbols - returned when method requires symbols, but nothing passed.
+// Scoped cancelling never falls back to whole account
+var ErrEmptySymbols = errors.New("Symbols empty")
+
+// ErrImportNotSupported - sent as final status of ImportTrades when exchange can't page user trades
+var ErrImportNotSupported = errors.New("Trades import is not supported")
+
+// SymbolsError - errors by symbol name, i.e. of cancelling orders of several symbols
+type SymbolsError map[string]error
+
+// Error - to implement error interface
+func (e SymbolsError) Error() string {
+	var names []string
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var msgs []string
+	for _, name := range names {
+		msgs = append(msgs, name+": "+e[name].Error())
+	}
+	return strings.Join(msgs, "; ")
+}
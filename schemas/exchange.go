@@ -1,20 +1,24 @@
 package schemas
 
 import (
+	"time"
+
 	"github.com/syndicatedb/goproxy/proxy"
 )
 
-// DataTypes - when sending data: update or snapshot
+// DataTypes - when sending data: update, snapshot or final status of finite channel
 const (
 	DataTypeSnapshot = "s"
 	DataTypeUpdate   = "u"
+	DataTypeDone     = "d"
 )
 
 // Credentials - struct to store credentials for private requests
 type Credentials struct {
-	APIKey    string
-	APISecret string
-	Sign      Signer
+	APIKey     string
+	APISecret  string
+	Passphrase string // required by some exchanges (Kucoin), set when creating API key
+	Sign       Signer
 }
 
 /*
@@ -70,4 +74,18 @@ type Options struct {
 	API           string
 	Credentials   Credentials
 	ProxyProvider proxy.Provider
+
+	// paper trading: simulated TradingProvider filled by live market data, credentials are not used
+	Paper *PaperOptions
+}
+
+/*
+PaperOptions - paper trading options.
+Symbol fees are used when both MakerFee and TakerFee are 0
+*/
+type PaperOptions struct {
+	Balances map[string]float64 // initial balances by coin
+	MakerFee float64            // rate, 0.001 is 0.1%
+	TakerFee float64            // rate, 0.001 is 0.1%
+	Latency  time.Duration      // delay of Create and Cancel requests
 }
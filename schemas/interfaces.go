@@ -12,11 +12,13 @@ type Signer func(string, string, *http.Request) *http.Request
 type SymbolProvider interface {
 	Get() (symbols []Symbol, err error)
 	Subscribe(time.Duration) chan ResultChannel
+	SubscribeChanges(time.Duration) chan SymbolEventsChannel
 }
 
 // OrdersProvider - provides access to Order book
 type OrdersProvider interface {
 	SetSymbols(symbols []Symbol) OrdersProvider
+	SymbolsSubscriber
 	Get(symbol Symbol) (book OrderBook, err error)
 	subscriber
 }
@@ -24,6 +26,7 @@ type OrdersProvider interface {
 // QuotesProvider - provides quotes/ticker
 type QuotesProvider interface {
 	SetSymbols(symbols []Symbol) QuotesProvider
+	SymbolsSubscriber
 	Get(symbol Symbol) (q Quote, err error)
 	subscriber
 }
@@ -31,6 +34,7 @@ type QuotesProvider interface {
 // TradesProvider - provides public trades
 type TradesProvider interface {
 	SetSymbols(symbols []Symbol) TradesProvider
+	SymbolsSubscriber
 	Get(symbol Symbol) (t []Trade, err error)
 	subscriber
 }
@@ -64,4 +68,5 @@ type TradingProvider interface {
 	Create(order Order) (result Order, err error)
 	Cancel(order Order) (err error)
 	CancelAll() (err error)
+	CancelAllBy(symbols []Symbol, side string) (cancelled []Order, err error)
 }
@@ -0,0 +1,36 @@
+package schemas
+
+// Position statuses
+const (
+	PositionActive = "ACTIVE"
+	PositionClosed = "CLOSED"
+)
+
+// Position - margin position
+type Position struct {
+	ID               string
+	Symbol           string
+	Status           string
+	Amount           float64 // negative for short position
+	BasePrice        float64
+	MarginFunding    float64
+	ProfitLoss       float64
+	ProfitLossRate   float64 // in percents
+	LiquidationPrice float64
+	Leverage         float64
+	CreatedAt        int64
+	UpdatedAt        int64
+}
+
+// FundingOffer - offer to lend coin for margin trading
+type FundingOffer struct {
+	ID             string
+	Coin           string
+	Status         string
+	Amount         float64 // remaining amount
+	AmountOriginal float64
+	Rate           float64 // daily rate
+	Period         int     // days
+	CreatedAt      int64
+	UpdatedAt      int64
+}
@@ -1,5 +1,10 @@
 package schemas
 
+import (
+	"strconv"
+	"strings"
+)
+
 // Order statuses
 const (
 	StatusNew       = "NEW"
@@ -33,4 +38,65 @@ type Order struct {
 	CreatedAt    int64   `json:"c_at"`
 	Remove       int     `json:"r"`
 	Status       string  `json:"st"`
+
+	// exact values, opt-in: used instead of floats when set
+	PriceDecimal        Decimal `json:"pd,omitempty"`
+	AmountDecimal       Decimal `json:"ad,omitempty"`
+	AmountFilledDecimal Decimal `json:"afd,omitempty"`
+}
+
+// PriceValue - price string for exchange request: exact decimal if set, float otherwise
+func (o Order) PriceValue() string {
+	if o.PriceDecimal.IsSet() {
+		return o.PriceDecimal.String()
+	}
+	return strconv.FormatFloat(o.Price, 'f', -1, 64)
+}
+
+// AmountValue - amount string for exchange request: exact decimal if set, float otherwise
+func (o Order) AmountValue() string {
+	if o.AmountDecimal.IsSet() {
+		return o.AmountDecimal.String()
+	}
+	return strconv.FormatFloat(o.Amount, 'f', -1, 64)
+}
+
+// IsSide - checking order side, empty side matches any order
+func (o Order) IsSide(side string) bool {
+	return side == "" || strings.EqualFold(o.Type, side)
+}
+
+// FilterOrders - filtering orders by symbols and side.
+// Symbol matches by common or original exchange name
+func FilterOrders(orders []Order, symbols []Symbol, side string) (result []Order) {
+	names := make(map[string]bool)
+	for _, s := range symbols {
+		names[s.Name] = true
+		names[s.OriginalName] = true
+	}
+	for _, o := range orders {
+		if names[o.Symbol] && o.IsSide(side) {
+			result = append(result, o)
+		}
+	}
+	return
+}
+
+// ApplyLevels - applying book levels to price levels: level with zero amount or Remove flag is removed
+func ApplyLevels(levels map[float64]float64, orders []Order) {
+	for _, o := range orders {
+		if o.Remove == 1 || o.Amount == 0 {
+			delete(levels, o.Price)
+			continue
+		}
+		levels[o.Price] = o.Amount
+	}
+}
+
+// WorsePrice - price is worse than limit for order side: higher for buy, lower for sell
+func WorsePrice(side string, price, limit float64) bool {
+	if side == TypeBuy {
+		return price > limit
+	}
+	return price < limit
 }
@@ -1,19 +1,85 @@
 package schemas
 
+import "strings"
+
+// Symbol statuses
+const (
+	SymbolStatusTrading  = "TRADING"
+	SymbolStatusHalted   = "HALTED"
+	SymbolStatusDelisted = "DELISTED"
+)
+
+// Order types allowed on symbol
+const (
+	OrderTypeLimit             = "LIMIT"
+	OrderTypeMarket            = "MARKET"
+	OrderTypeLimitMaker        = "LIMIT_MAKER"
+	OrderTypeStopLoss          = "STOP_LOSS"
+	OrderTypeStopLossLimit     = "STOP_LOSS_LIMIT"
+	OrderTypeTakeProfit        = "TAKE_PROFIT"
+	OrderTypeTakeProfitLimit   = "TAKE_PROFIT_LIMIT"
+	OrderTypeTrailingStop      = "TRAILING_STOP"
+	OrderTypeFillOrKill        = "FILL_OR_KILL"
+	OrderTypeImmediateOrCancel = "IMMEDIATE_OR_CANCEL"
+)
+
 // Symbol represents exchange symbol model
 type Symbol struct {
-	Name            string  `json:"name"`
-	OriginalName    string  `json:"original"`
-	Coin            string  `json:"coin"`
-	BaseCoin        string  `json:"baseCoin"`
-	Fee             float64 `json:"fee"`
-	MinPrice        float64 `json:"minPrice"`
-	MaxPrice        float64 `json:"maxPrice"`
-	MinAmount       float64 `json:"minAmount"`
-	MaxAmount       float64 `json:"maxAmount"`
-	PricePrecision  int     `json:"pricePrecision"`
-	AmountPrecision int     `json:"amountPrecision"`
+	Name            string   `json:"name"`
+	OriginalName    string   `json:"original"`
+	Coin            string   `json:"coin"`
+	BaseCoin        string   `json:"baseCoin"`
+	Status          string   `json:"status"`
+	OrderTypes      []string `json:"orderTypes"`
+	Fee             float64  `json:"fee"`         // taker rate, 0.001 is 0.1%
+	MakerFee        float64  `json:"makerFee"`    // rate, 0.001 is 0.1%
+	TakerFee        float64  `json:"takerFee"`    // rate, 0.001 is 0.1%
+	DefaultFees     bool     `json:"defaultFees"` // fees are exchange base tier, not account ones
+	MinPrice        float64  `json:"minPrice"`
+	MaxPrice        float64  `json:"maxPrice"`
+	TickSize        float64  `json:"tickSize"` // price step, 0 if exchange hasn't fixed one
+	MinAmount       float64  `json:"minAmount"`
+	MaxAmount       float64  `json:"maxAmount"`
+	LotStep         float64  `json:"lotStep"`        // amount step, 0 if exchange hasn't fixed one
+	MinNotional     float64  `json:"minNotional"`    // min price * amount
+	DefaultFilters  bool     `json:"defaultFilters"` // filters are exchange documented defaults, orders aren't rounded or rejected by them
+	PricePrecision  int      `json:"pricePrecision"`
+	AmountPrecision int      `json:"amountPrecision"`
 	// BasePrecision  int     `json:"basePrecision"`
 	// QuotePrecision int     `json:"quotePrecision"`
 	Volume float64 `json:"volume"`
 }
+
+// IsTrading - symbol is open for trading. Empty status is treated as trading
+func (s Symbol) IsTrading() bool {
+	return s.Status == "" || s.Status == SymbolStatusTrading
+}
+
+// AllowsOrderType - checking order type is allowed. Empty list allows any type
+func (s Symbol) AllowsOrderType(t string) bool {
+	if len(s.OrderTypes) == 0 {
+		return true
+	}
+	for _, ot := range s.OrderTypes {
+		if ot == t {
+			return true
+		}
+	}
+	return false
+}
+
+// BaseCoinOf - base coin of common BASE-QUOTE symbol name, empty if name has no dash
+func BaseCoinOf(name string) string {
+	if i := strings.Index(name, "-"); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// QuoteCoinOf - quote coin of common BASE-QUOTE symbol name, empty if name has no dash
+func QuoteCoinOf(name string) string {
+	if i := strings.LastIndex(name, "-"); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package schemas
+
+// Symbol event types
+const (
+	SymbolEventListed   = "LISTED"
+	SymbolEventDelisted = "DELISTED"
+	SymbolEventStatus   = "STATUS"
+	SymbolEventFilters  = "FILTERS"
+)
+
+// SymbolEvent - symbol change found between two loads of exchange symbols
+type SymbolEvent struct {
+	Type     string `json:"type"`
+	Symbol   Symbol `json:"symbol"`
+	Previous Symbol `json:"previous"` // symbol before change, empty for listings
+}
+
+// SymbolsSubscriber - provider which can change symbols of running subscription
+type SymbolsSubscriber interface {
+	AddSymbols(symbols []Symbol)
+	RemoveSymbols(symbols []Symbol)
+}
+
+// DiffSymbols - comparing previous and current symbols lists by symbol name.
+// One symbol can get both status and filters events
+func DiffSymbols(prev, next []Symbol) (events []SymbolEvent) {
+	known := make(map[string]Symbol, len(prev))
+	for _, s := range prev {
+		known[s.Name] = s
+	}
+	for _, s := range next {
+		p, ok := known[s.Name]
+		if !ok {
+			events = append(events, SymbolEvent{Type: SymbolEventListed, Symbol: s})
+			continue
+		}
+		delete(known, s.Name)
+		if s.Status != p.Status {
+			events = append(events, SymbolEvent{Type: SymbolEventStatus, Symbol: s, Previous: p})
+		}
+		if !sameFilters(s, p) {
+			events = append(events, SymbolEvent{Type: SymbolEventFilters, Symbol: s, Previous: p})
+		}
+	}
+	// keeping previous order for delistings
+	for _, p := range prev {
+		if _, ok := known[p.Name]; !ok {
+			continue
+		}
+		s := p
+		s.Status = SymbolStatusDelisted
+		events = append(events, SymbolEvent{Type: SymbolEventDelisted, Symbol: s, Previous: p})
+	}
+	return
+}
+
+// ApplySymbolEvents - adding listed and resumed symbols to subscribers,
+// removing delisted and halted ones. Filters changes don't touch subscriptions
+func ApplySymbolEvents(events []SymbolEvent, subscribers ...SymbolsSubscriber) {
+	var added, removed []Symbol
+	for _, e := range events {
+		switch e.Type {
+		case SymbolEventListed, SymbolEventStatus:
+			if e.Symbol.IsTrading() {
+				added = append(added, e.Symbol)
+			} else {
+				removed = append(removed, e.Symbol)
+			}
+		case SymbolEventDelisted:
+			removed = append(removed, e.Symbol)
+		}
+	}
+	for _, s := range subscribers {
+		if len(added) > 0 {
+			s.AddSymbols(added)
+		}
+		if len(removed) > 0 {
+			s.RemoveSymbols(removed)
+		}
+	}
+}
+
+// sameFilters - comparing symbol fields used for orders validation
+func sameFilters(a, b Symbol) bool {
+	return a.MinPrice == b.MinPrice &&
+		a.MaxPrice == b.MaxPrice &&
+		a.TickSize == b.TickSize &&
+		a.MinAmount == b.MinAmount &&
+		a.MaxAmount == b.MaxAmount &&
+		a.LotStep == b.LotStep &&
+		a.MinNotional == b.MinNotional &&
+		a.PricePrecision == b.PricePrecision &&
+		a.AmountPrecision == b.AmountPrecision
+}
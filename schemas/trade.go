@@ -1,5 +1,7 @@
 package schemas
 
+import "time"
+
 const (
 	Buy  = "buy"
 	Sell = "sell"
@@ -10,11 +12,30 @@ type Trade struct {
 	ID        string  `json:"id"`       // 21490692,
 	OrderID   string  `json:"order_id"` // 21490692,
 	Symbol    string  `json:"symbol"`
-	Type      string  `json:"type"`   // "ask",
-	Price     float64 `json:"price"`  // 0.0721605,
-	Amount    float64 `json:"amount"` // 0.18422595,
-	Fee       float64 `json:"fee"`    // 0.18422595,
-	Timestamp int64   `json:"ts"`     // 1531088906
+	Type      string  `json:"type"`               // "ask",
+	Price     float64 `json:"price"`              // 0.0721605,
+	Amount    float64 `json:"amount"`             // 0.18422595,
+	Fee       float64 `json:"fee"`                // 0.18422595,
+	FeeCoin   string  `json:"fee_coin,omitempty"` // coin fee is paid in, quote coin if empty
+	Timestamp int64   `json:"ts"`                 // 1531088906
+
+	// exact values, filled when exchange sends strings
+	PriceDecimal  Decimal `json:"price_dec,omitempty"`
+	AmountDecimal Decimal `json:"amount_dec,omitempty"`
+	FeeDecimal    Decimal `json:"fee_dec,omitempty"`
+}
+
+// Time - trade time, timestamp is either in seconds or in milliseconds
+func (t Trade) Time() time.Time {
+	return UnixTime(t.Timestamp)
+}
+
+// UnixTime - time of exchange timestamp, it's either in seconds or in milliseconds
+func UnixTime(ts int64) time.Time {
+	if ts > 1e12 {
+		return time.Unix(0, ts*int64(time.Millisecond))
+	}
+	return time.Unix(ts, 0)
 }
 
 // FilterOptions - options for loading trades
@@ -26,6 +47,11 @@ type FilterOptions struct {
 	Limit   int
 	Skip    int
 	Page    int
+
+	// ImportTrades progress, import is resumed from saved checkpoints. Optional
+	Checkpoints Checkpoints
+	// ImportTrades waits for Ack of every page before its checkpoint is saved, see UserTradesChannel
+	Acknowledge bool
 }
 
 type Paging struct {
@@ -34,3 +60,15 @@ type Paging struct {
 	Current int64
 	Limit   int
 }
+
+// Checkpoint - trades import progress of exchange, account and symbol
+type Checkpoint struct {
+	Cursor    string `json:"cursor"`    // where next page starts: trade ID or time, by exchange
+	Timestamp int64  `json:"timestamp"` // last imported trade time
+}
+
+// Checkpoints - storage of import checkpoints by key
+type Checkpoints interface {
+	Get(key string) (c Checkpoint, ok bool, err error)
+	Set(key string, c Checkpoint) error
+}
@@ -1,12 +1,64 @@
 package schemas
 
+// Wallet types, exchanges without wallets have exchange (spot) balances only
+const (
+	WalletExchange = "exchange"
+	WalletMargin   = "margin"
+	WalletFunding  = "funding"
+)
+
 // UserInfo - user info
 type UserInfo struct {
 	Access   Access
-	Balances map[string]Balance
+	Balances map[string]Balance // exchange (spot) wallet balances by coin
 	Prices   map[string]float64
 	TradesCount,
 	OrdersCount int32
+
+	// filled by exchanges with several wallets: balances by wallet type and coin
+	Wallets map[string]map[string]Balance
+	// margin positions and funding offers, filled by exchanges supporting them
+	Positions     []Position
+	FundingOffers []FundingOffer
+
+	// account trading fees, nil if exchange doesn't return them
+	Fees *Fees
+}
+
+// Fees - account maker and taker fee rates, 0.001 is 0.1%
+type Fees struct {
+	Maker float64
+	Taker float64
+}
+
+/*
+ApplyFees - symbols with account fees instead of exchange default ones.
+Symbols are returned unchanged when fees are nil
+*/
+func ApplyFees(symbols []Symbol, fees *Fees) []Symbol {
+	if fees == nil {
+		return symbols
+	}
+	res := make([]Symbol, len(symbols))
+	for i, s := range symbols {
+		s.Fee = fees.Taker
+		s.MakerFee = fees.Maker
+		s.TakerFee = fees.Taker
+		s.DefaultFees = false
+		res[i] = s
+	}
+	return res
+}
+
+// Wallet - balances of wallet type by coin, empty map if there is no such wallet
+func (ui UserInfo) Wallet(walletType string) map[string]Balance {
+	if walletType == WalletExchange && ui.Wallets == nil {
+		return ui.Balances
+	}
+	if w, ok := ui.Wallets[walletType]; ok {
+		return w
+	}
+	return map[string]Balance{}
 }
 
 // Access - API keys access level
@@ -23,4 +75,9 @@ type Balance struct {
 	Available float64
 	InOrders  float64
 	Total     float64
+
+	// exact values, filled when exchange sends strings
+	AvailableDecimal Decimal
+	InOrdersDecimal  Decimal
+	TotalDecimal     Decimal
 }
@@ -0,0 +1,151 @@
+package schemas
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Validation error reasons
+const (
+	ReasonStatus      = "NOT_TRADING"
+	ReasonSide        = "INVALID_SIDE"
+	ReasonMinPrice    = "MIN_PRICE"
+	ReasonMaxPrice    = "MAX_PRICE"
+	ReasonMinAmount   = "MIN_AMOUNT"
+	ReasonMaxAmount   = "MAX_AMOUNT"
+	ReasonMinNotional = "MIN_NOTIONAL"
+	ReasonUnknown     = "UNKNOWN_SYMBOL"
+)
+
+// ValidationError - order doesn't pass symbol filters, request is not sent
+type ValidationError struct {
+	Symbol string
+	Reason string
+	Value  float64
+	Limit  float64
+}
+
+// Error - to implement error interface
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("Order validation error. Symbol: %s, reason: %s, value: %v, limit: %v", e.Symbol, e.Reason, e.Value, e.Limit)
+}
+
+// PriceTick - minimal price change: tick size or derived from price precision
+func (s Symbol) PriceTick() float64 {
+	if s.TickSize > 0 {
+		return s.TickSize
+	}
+	if s.PricePrecision <= 0 {
+		return 0
+	}
+	return math.Pow10(-s.PricePrecision)
+}
+
+// AmountStep - minimal amount change: lot step or derived from amount precision
+func (s Symbol) AmountStep() float64 {
+	if s.LotStep > 0 {
+		return s.LotStep
+	}
+	if s.AmountPrecision <= 0 {
+		return 0
+	}
+	return math.Pow10(-s.AmountPrecision)
+}
+
+/*
+ValidateOrder - rounding order price and amount to symbol tick and step
+and checking it against symbol limits.
+Buy price is rounded down and sell price is rounded up, so rounding never makes
+order price worse. Amount is always rounded down to not exceed balance.
+Exact decimals are rounded without float conversion when set.
+Symbol with DefaultFilters is checked by status and side only
+*/
+func (s Symbol) ValidateOrder(order Order) (Order, error) {
+	var up bool
+	if !s.IsTrading() {
+		return order, ValidationError{Symbol: s.Name, Reason: ReasonStatus}
+	}
+	switch strings.ToUpper(order.Type) {
+	case TypeBuy:
+		up = false
+	case TypeSell:
+		up = true
+	default:
+		return order, ValidationError{Symbol: s.Name, Reason: ReasonSide}
+	}
+	if s.DefaultFilters {
+		return order, nil
+	}
+
+	if order.PriceDecimal.IsSet() {
+		order.PriceDecimal = order.PriceDecimal.RoundStep(DecimalFromFloat(s.PriceTick()), up)
+		order.Price = order.PriceDecimal.Float64()
+	} else {
+		order.Price = roundToStep(order.Price, s.PriceTick(), up)
+	}
+	if order.AmountDecimal.IsSet() {
+		order.AmountDecimal = order.AmountDecimal.RoundStep(DecimalFromFloat(s.AmountStep()), false)
+		order.Amount = order.AmountDecimal.Float64()
+	} else {
+		order.Amount = roundToStep(order.Amount, s.AmountStep(), false)
+	}
+
+	if order.Price <= 0 || (s.MinPrice > 0 && order.Price < s.MinPrice) {
+		return order, ValidationError{Symbol: s.Name, Reason: ReasonMinPrice, Value: order.Price, Limit: s.MinPrice}
+	}
+	if s.MaxPrice > 0 && order.Price > s.MaxPrice {
+		return order, ValidationError{Symbol: s.Name, Reason: ReasonMaxPrice, Value: order.Price, Limit: s.MaxPrice}
+	}
+	if order.Amount <= 0 || (s.MinAmount > 0 && order.Amount < s.MinAmount) {
+		return order, ValidationError{Symbol: s.Name, Reason: ReasonMinAmount, Value: order.Amount, Limit: s.MinAmount}
+	}
+	if s.MaxAmount > 0 && order.Amount > s.MaxAmount {
+		return order, ValidationError{Symbol: s.Name, Reason: ReasonMaxAmount, Value: order.Amount, Limit: s.MaxAmount}
+	}
+	if notional := order.Price * order.Amount; s.MinNotional > 0 && notional < s.MinNotional {
+		return order, ValidationError{Symbol: s.Name, Reason: ReasonMinNotional, Value: notional, Limit: s.MinNotional}
+	}
+	return order, nil
+}
+
+/*
+PrepareOrder - finding order symbol and validating order against it.
+Order of unknown symbol is not sent: ValidationError with ReasonUnknown is returned,
+i.e. when symbols failed to load
+*/
+func PrepareOrder(symbols []Symbol, order Order) (Order, error) {
+	for _, s := range symbols {
+		if s.Name == order.Symbol || s.OriginalName == order.Symbol {
+			return s.ValidateOrder(order)
+		}
+	}
+	return order, ValidationError{Symbol: order.Symbol, Reason: ReasonUnknown}
+}
+
+// roundToStep - rounding value to step, values already on step are kept
+func roundToStep(v, step float64, up bool) float64 {
+	if step <= 0 {
+		return v
+	}
+	n := v / step
+	if r := math.Round(n); math.Abs(n-r) < 1e-9 {
+		n = r
+	} else if up {
+		n = math.Ceil(n)
+	} else {
+		n = math.Floor(n)
+	}
+	// cutting float tail, e.g. 0.30000000000000004
+	f, _ := strconv.ParseFloat(strconv.FormatFloat(n*step, 'f', stepDecimals(step), 64), 64)
+	return f
+}
+
+func stepDecimals(step float64) int {
+	s := strconv.FormatFloat(step, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
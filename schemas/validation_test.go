@@ -0,0 +1,141 @@
+package schemas
+
+import "testing"
+
+func TestPrepareOrder(t *testing.T) {
+	symbols := []Symbol{
+		{
+			Name:         "BTC-USDT",
+			OriginalName: "BTCUSDT",
+			TickSize:     0.01,
+			LotStep:      0.0001,
+			MinAmount:    0.001,
+			MinNotional:  10,
+		},
+		{
+			Name:            "ETH-BTC",
+			PricePrecision:  6,
+			AmountPrecision: 3,
+			MaxAmount:       100,
+		},
+		{
+			Name:   "XRP-BTC",
+			Status: SymbolStatusHalted,
+		},
+		{
+			Name:           "DOGE-BTC",
+			TickSize:       1e-8,
+			MinNotional:    0.0001,
+			DefaultFilters: true,
+		},
+	}
+	tests := []struct {
+		name         string
+		order        Order
+		price        float64
+		amount       float64
+		priceDecimal Decimal
+		reason       string
+	}{
+		{
+			name:   "buy price rounded down",
+			order:  Order{Symbol: "BTC-USDT", Type: TypeBuy, Price: 10000.129, Amount: 0.12345},
+			price:  10000.12,
+			amount: 0.1234,
+		},
+		{
+			name:   "sell price rounded up",
+			order:  Order{Symbol: "BTCUSDT", Type: TypeSell, Price: 10000.121, Amount: 0.12345},
+			price:  10000.13,
+			amount: 0.1234,
+		},
+		{
+			name:   "values on step are kept",
+			order:  Order{Symbol: "BTC-USDT", Type: TypeSell, Price: 0.3 * 1000, Amount: 0.1 + 0.2},
+			price:  300,
+			amount: 0.3,
+		},
+		{
+			name:   "step from precision",
+			order:  Order{Symbol: "ETH-BTC", Type: TypeSell, Price: 0.0312345, Amount: 1.23456},
+			price:  0.031235,
+			amount: 1.234,
+		},
+		{
+			name:         "exact decimals",
+			order:        Order{Symbol: "BTC-USDT", Type: TypeBuy, PriceDecimal: "10000.129", AmountDecimal: "0.12345"},
+			price:        10000.12,
+			amount:       0.1234,
+			priceDecimal: "10000.12",
+		},
+		{
+			name:   "min amount after rounding",
+			order:  Order{Symbol: "BTC-USDT", Type: TypeBuy, Price: 10000, Amount: 0.00099},
+			price:  10000,
+			amount: 0.0009,
+			reason: ReasonMinAmount,
+		},
+		{
+			name:   "min notional",
+			order:  Order{Symbol: "BTC-USDT", Type: TypeBuy, Price: 100, Amount: 0.05},
+			price:  100,
+			amount: 0.05,
+			reason: ReasonMinNotional,
+		},
+		{
+			name:   "max amount",
+			order:  Order{Symbol: "ETH-BTC", Type: TypeBuy, Price: 0.03, Amount: 101},
+			price:  0.03,
+			amount: 101,
+			reason: ReasonMaxAmount,
+		},
+		{
+			name:   "invalid side",
+			order:  Order{Symbol: "ETH-BTC", Type: "HOLD", Price: 0.03, Amount: 1},
+			price:  0.03,
+			amount: 1,
+			reason: ReasonSide,
+		},
+		{
+			name:   "halted symbol",
+			order:  Order{Symbol: "XRP-BTC", Type: TypeBuy, Price: 0.00001, Amount: 1},
+			price:  0.00001,
+			amount: 1,
+			reason: ReasonStatus,
+		},
+		{
+			name:   "default filters are not applied",
+			order:  Order{Symbol: "DOGE-BTC", Type: TypeBuy, Price: 0.0000000051, Amount: 10},
+			price:  0.0000000051,
+			amount: 10,
+		},
+		{
+			name:   "unknown symbol",
+			order:  Order{Symbol: "LTC-BTC", Type: TypeBuy, Price: 0.0123456789, Amount: 1.23456789},
+			price:  0.0123456789,
+			amount: 1.23456789,
+			reason: ReasonUnknown,
+		},
+	}
+	for _, tt := range tests {
+		order, err := PrepareOrder(symbols, tt.order)
+		var reason string
+		if err != nil {
+			verr, ok := err.(ValidationError)
+			if !ok {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+				continue
+			}
+			reason = verr.Reason
+		}
+		if reason != tt.reason {
+			t.Errorf("%s: reason = %q, want %q", tt.name, reason, tt.reason)
+		}
+		if order.Price != tt.price || order.Amount != tt.amount {
+			t.Errorf("%s: price, amount = %v, %v, want %v, %v", tt.name, order.Price, order.Amount, tt.price, tt.amount)
+		}
+		if order.PriceDecimal != tt.priceDecimal {
+			t.Errorf("%s: price decimal = %q, want %q", tt.name, order.PriceDecimal, tt.priceDecimal)
+		}
+	}
+}